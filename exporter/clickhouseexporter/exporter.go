@@ -45,6 +45,11 @@ func newExporter(logger *zap.Logger, cfg *Config) (*clickhouseExporter, error) {
 		return nil, err
 	}
 
+	if err = createLogsTable(cfg, client); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
 	insertLogsSQL := renderInsertLogsSQL(cfg)
 
 	return &clickhouseExporter{
@@ -115,7 +120,7 @@ func (e *clickhouseExporter) pushLogsData(ctx context.Context, ld plog.Logs) err
 func attributesToMap(attributes pcommon.Map) map[string]string {
 	m := make(map[string]string, attributes.Len())
 	attributes.Range(func(k string, v pcommon.Value) bool {
-		m[k] = v.StringVal()
+		m[k] = v.AsString()
 		return true
 	})
 	return m
@@ -182,17 +187,23 @@ func newClickhouseClient(cfg *Config) (*sql.DB, error) {
 	if err != nil {
 		return nil, fmt.Errorf("sql.Open:%w", err)
 	}
-	// create table
-	query := fmt.Sprintf(createLogsTableSQL, cfg.LogsTableName, "")
-	if cfg.TTLDays > 0 {
-		query = fmt.Sprintf(createLogsTableSQL,
-			cfg.LogsTableName,
-			fmt.Sprintf(`TTL toDateTime(Timestamp) + toIntervalDay(%d)`, cfg.TTLDays))
-	}
+	return db, nil
+}
+
+func createLogsTable(cfg *Config, db *sql.DB) error {
+	query := fmt.Sprintf(createLogsTableSQL, cfg.LogsTableName, renderTTL(cfg, "Timestamp"))
 	if _, err := db.Exec(query); err != nil {
-		return nil, fmt.Errorf("exec create table sql: %w", err)
+		return fmt.Errorf("exec create logs table sql: %w", err)
 	}
-	return db, nil
+	return nil
+}
+
+// renderTTL returns the TTL clause of a table on the given time column, or an empty string if there is no ttl.
+func renderTTL(cfg *Config, timeColumn string) string {
+	if cfg.TTLDays == 0 {
+		return ""
+	}
+	return fmt.Sprintf(`TTL toDateTime(%s) + toIntervalDay(%d)`, timeColumn, cfg.TTLDays)
 }
 
 func renderInsertLogsSQL(cfg *Config) string {
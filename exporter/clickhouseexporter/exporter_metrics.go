@@ -0,0 +1,384 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouseexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter"
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
+	"go.uber.org/zap"
+)
+
+// metricTable describes the table storing the data points of one metric type.
+type metricTable struct {
+	// suffix is appended to MetricsTableName to build the table name.
+	suffix string
+	// columns are the DDL of the columns specific to the metric type.
+	columns string
+	// insertColumns are the names of the columns specific to the metric type.
+	insertColumns []string
+}
+
+var metricTables = map[pmetric.MetricDataType]metricTable{
+	pmetric.MetricDataTypeGauge: {
+		suffix: "_gauge",
+		columns: `
+     Value Float64 CODEC(ZSTD(1)),`,
+		insertColumns: []string{"Value"},
+	},
+	pmetric.MetricDataTypeSum: {
+		suffix: "_sum",
+		columns: `
+     Value Float64 CODEC(ZSTD(1)),
+     AggTemp Int32 CODEC(ZSTD(1)),
+     IsMonotonic Boolean CODEC(Delta, ZSTD(1)),`,
+		insertColumns: []string{"Value", "AggTemp", "IsMonotonic"},
+	},
+	pmetric.MetricDataTypeHistogram: {
+		suffix: "_histogram",
+		columns: `
+     Count UInt64 CODEC(Delta, ZSTD(1)),
+     Sum Float64 CODEC(ZSTD(1)),
+     BucketCounts Array(UInt64) CODEC(ZSTD(1)),
+     ExplicitBounds Array(Float64) CODEC(ZSTD(1)),
+     Min Nullable(Float64) CODEC(ZSTD(1)),
+     Max Nullable(Float64) CODEC(ZSTD(1)),
+     AggTemp Int32 CODEC(ZSTD(1)),`,
+		insertColumns: []string{"Count", "Sum", "BucketCounts", "ExplicitBounds", "Min", "Max", "AggTemp"},
+	},
+	pmetric.MetricDataTypeExponentialHistogram: {
+		suffix: "_exponential_histogram",
+		columns: `
+     Count UInt64 CODEC(Delta, ZSTD(1)),
+     Sum Float64 CODEC(ZSTD(1)),
+     Scale Int32 CODEC(ZSTD(1)),
+     ZeroCount UInt64 CODEC(ZSTD(1)),
+     PositiveOffset Int32 CODEC(ZSTD(1)),
+     PositiveBucketCounts Array(UInt64) CODEC(ZSTD(1)),
+     NegativeOffset Int32 CODEC(ZSTD(1)),
+     NegativeBucketCounts Array(UInt64) CODEC(ZSTD(1)),
+     Min Nullable(Float64) CODEC(ZSTD(1)),
+     Max Nullable(Float64) CODEC(ZSTD(1)),
+     AggTemp Int32 CODEC(ZSTD(1)),`,
+		insertColumns: []string{"Count", "Sum", "Scale", "ZeroCount", "PositiveOffset", "PositiveBucketCounts",
+			"NegativeOffset", "NegativeBucketCounts", "Min", "Max", "AggTemp"},
+	},
+	pmetric.MetricDataTypeSummary: {
+		suffix: "_summary",
+		columns: `
+     Count UInt64 CODEC(Delta, ZSTD(1)),
+     Sum Float64 CODEC(ZSTD(1)),
+     ValueAtQuantiles Nested (
+         Quantile Float64,
+         Value Float64
+     ) CODEC(ZSTD(1)),`,
+		insertColumns: []string{"Count", "Sum", "ValueAtQuantiles.Quantile", "ValueAtQuantiles.Value"},
+	},
+}
+
+// metricsCommonInsertColumns are the names of the columns shared by all the metric tables.
+var metricsCommonInsertColumns = []string{
+	"ResourceAttributes",
+	"ResourceSchemaUrl",
+	"ScopeName",
+	"ScopeVersion",
+	"ScopeAttributes",
+	"ScopeSchemaUrl",
+	"ServiceName",
+	"MetricName",
+	"MetricDescription",
+	"MetricUnit",
+	"Attributes",
+	"StartTimeUnix",
+	"TimeUnix",
+	"Flags",
+	"Exemplars.FilteredAttributes",
+	"Exemplars.TimeUnix",
+	"Exemplars.Value",
+	"Exemplars.SpanId",
+	"Exemplars.TraceId",
+}
+
+type metricsExporter struct {
+	client     *sql.DB
+	insertSQLs map[pmetric.MetricDataType]string
+
+	logger *zap.Logger
+	cfg    *Config
+}
+
+func newMetricsExporter(logger *zap.Logger, cfg *Config) (*metricsExporter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := newClickhouseClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = createMetricsTables(cfg, client); err != nil {
+		_ = client.Close()
+		return nil, err
+	}
+
+	insertSQLs := make(map[pmetric.MetricDataType]string, len(metricTables))
+	for dataType, table := range metricTables {
+		insertSQLs[dataType] = renderInsertMetricSQL(cfg, table)
+	}
+
+	return &metricsExporter{
+		client:     client,
+		insertSQLs: insertSQLs,
+		logger:     logger,
+		cfg:        cfg,
+	}, nil
+}
+
+// Shutdown will shutdown the exporter.
+func (e *metricsExporter) Shutdown(_ context.Context) error {
+	if e.client != nil {
+		return e.client.Close()
+	}
+	return nil
+}
+
+func (e *metricsExporter) pushMetricsData(ctx context.Context, md pmetric.Metrics) error {
+	start := time.Now()
+	rows := metricsToRows(md)
+	err := doWithTx(ctx, e.client, func(tx *sql.Tx) error {
+		for dataType, tableRows := range rows {
+			if err := insertRows(ctx, tx, e.insertSQLs[dataType], tableRows); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	duration := time.Since(start)
+	e.logger.Info("insert metrics", zap.Int("records", md.DataPointCount()),
+		zap.String("cost", duration.String()))
+	return err
+}
+
+func insertRows(ctx context.Context, tx *sql.Tx, insertSQL string, rows [][]interface{}) error {
+	statement, err := tx.PrepareContext(ctx, insertSQL)
+	if err != nil {
+		return fmt.Errorf("PrepareContext:%w", err)
+	}
+	defer func() {
+		_ = statement.Close()
+	}()
+	for _, row := range rows {
+		if _, err = statement.ExecContext(ctx, row...); err != nil {
+			return fmt.Errorf("ExecContext:%w", err)
+		}
+	}
+	return nil
+}
+
+// metricsToRows converts the data points to the rows of the table of their metric type.
+func metricsToRows(md pmetric.Metrics) map[pmetric.MetricDataType][][]interface{} {
+	rows := make(map[pmetric.MetricDataType][][]interface{})
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		res := rm.Resource()
+		var serviceName string
+		if v, ok := res.Attributes().Get(conventions.AttributeServiceName); ok {
+			serviceName = v.StringVal()
+		}
+		resAttr := attributesToMap(res.Attributes())
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			sm := rm.ScopeMetrics().At(j)
+			scope := sm.Scope()
+			scopeAttr := attributesToMap(scope.Attributes())
+			for k := 0; k < sm.Metrics().Len(); k++ {
+				m := sm.Metrics().At(k)
+				// common returns the values of the columns shared by all the metric tables, followed by the given values.
+				common := func(attrs pcommon.Map, startTime, ts pcommon.Timestamp, flags pmetric.MetricDataPointFlags, exemplars pmetric.ExemplarSlice, values ...interface{}) []interface{} {
+					exAttrs, exTimes, exValues, exSpanIDs, exTraceIDs := convertExemplars(exemplars)
+					return append([]interface{}{
+						resAttr,
+						rm.SchemaUrl(),
+						scope.Name(),
+						scope.Version(),
+						scopeAttr,
+						sm.SchemaUrl(),
+						serviceName,
+						m.Name(),
+						m.Description(),
+						m.Unit(),
+						attributesToMap(attrs),
+						startTime.AsTime(),
+						ts.AsTime(),
+						uint32(flags),
+						exAttrs,
+						exTimes,
+						exValues,
+						exSpanIDs,
+						exTraceIDs,
+					}, values...)
+				}
+
+				switch m.DataType() {
+				case pmetric.MetricDataTypeGauge:
+					dps := m.Gauge().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						rows[m.DataType()] = append(rows[m.DataType()], common(dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), dp.Flags(), dp.Exemplars(),
+							numberValue(dp)))
+					}
+				case pmetric.MetricDataTypeSum:
+					dps := m.Sum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						rows[m.DataType()] = append(rows[m.DataType()], common(dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), dp.Flags(), dp.Exemplars(),
+							numberValue(dp), int32(m.Sum().AggregationTemporality()), m.Sum().IsMonotonic()))
+					}
+				case pmetric.MetricDataTypeHistogram:
+					dps := m.Histogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						rows[m.DataType()] = append(rows[m.DataType()], common(dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), dp.Flags(), dp.Exemplars(),
+							dp.Count(), dp.Sum(), dp.BucketCounts().AsRaw(), dp.ExplicitBounds().AsRaw(),
+							optionalFloat(dp.HasMin(), dp.Min()), optionalFloat(dp.HasMax(), dp.Max()),
+							int32(m.Histogram().AggregationTemporality())))
+					}
+				case pmetric.MetricDataTypeExponentialHistogram:
+					dps := m.ExponentialHistogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						rows[m.DataType()] = append(rows[m.DataType()], common(dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), dp.Flags(), dp.Exemplars(),
+							dp.Count(), dp.Sum(), dp.Scale(), dp.ZeroCount(),
+							dp.Positive().Offset(), dp.Positive().BucketCounts().AsRaw(),
+							dp.Negative().Offset(), dp.Negative().BucketCounts().AsRaw(),
+							optionalFloat(dp.HasMin(), dp.Min()), optionalFloat(dp.HasMax(), dp.Max()),
+							int32(m.ExponentialHistogram().AggregationTemporality())))
+					}
+				case pmetric.MetricDataTypeSummary:
+					dps := m.Summary().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						quantiles := make([]float64, 0, dp.QuantileValues().Len())
+						values := make([]float64, 0, dp.QuantileValues().Len())
+						for q := 0; q < dp.QuantileValues().Len(); q++ {
+							quantiles = append(quantiles, dp.QuantileValues().At(q).Quantile())
+							values = append(values, dp.QuantileValues().At(q).Value())
+						}
+						rows[m.DataType()] = append(rows[m.DataType()], common(dp.Attributes(), dp.StartTimestamp(), dp.Timestamp(), dp.Flags(), pmetric.NewExemplarSlice(),
+							dp.Count(), dp.Sum(), quantiles, values))
+					}
+				}
+			}
+		}
+	}
+	return rows
+}
+
+func numberValue(dp pmetric.NumberDataPoint) float64 {
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		return float64(dp.IntVal())
+	}
+	return dp.DoubleVal()
+}
+
+// optionalFloat returns nil for the values that are not set, so that they are stored as NULL.
+func optionalFloat(isSet bool, value float64) interface{} {
+	if !isSet {
+		return nil
+	}
+	return value
+}
+
+func convertExemplars(exemplars pmetric.ExemplarSlice) ([]map[string]string, []time.Time, []float64, []string, []string) {
+	attrs := make([]map[string]string, 0, exemplars.Len())
+	times := make([]time.Time, 0, exemplars.Len())
+	values := make([]float64, 0, exemplars.Len())
+	spanIDs := make([]string, 0, exemplars.Len())
+	traceIDs := make([]string, 0, exemplars.Len())
+	for i := 0; i < exemplars.Len(); i++ {
+		exemplar := exemplars.At(i)
+		attrs = append(attrs, attributesToMap(exemplar.FilteredAttributes()))
+		times = append(times, exemplar.Timestamp().AsTime())
+		if exemplar.ValueType() == pmetric.ExemplarValueTypeInt {
+			values = append(values, float64(exemplar.IntVal()))
+		} else {
+			values = append(values, exemplar.DoubleVal())
+		}
+		spanIDs = append(spanIDs, exemplar.SpanID().HexString())
+		traceIDs = append(traceIDs, exemplar.TraceID().HexString())
+	}
+	return attrs, times, values, spanIDs, traceIDs
+}
+
+const (
+	// language=ClickHouse SQL
+	createMetricTableSQL = `
+CREATE TABLE IF NOT EXISTS %s (
+     ResourceAttributes Map(LowCardinality(String), String) CODEC(ZSTD(1)),
+     ResourceSchemaUrl String CODEC(ZSTD(1)),
+     ScopeName String CODEC(ZSTD(1)),
+     ScopeVersion String CODEC(ZSTD(1)),
+     ScopeAttributes Map(LowCardinality(String), String) CODEC(ZSTD(1)),
+     ScopeSchemaUrl String CODEC(ZSTD(1)),
+     ServiceName LowCardinality(String) CODEC(ZSTD(1)),
+     MetricName String CODEC(ZSTD(1)),
+     MetricDescription String CODEC(ZSTD(1)),
+     MetricUnit String CODEC(ZSTD(1)),
+     Attributes Map(LowCardinality(String), String) CODEC(ZSTD(1)),
+     StartTimeUnix DateTime64(9) CODEC(Delta, ZSTD(1)),
+     TimeUnix DateTime64(9) CODEC(Delta, ZSTD(1)),
+     Flags UInt32 CODEC(ZSTD(1)),
+     Exemplars Nested (
+         FilteredAttributes Map(LowCardinality(String), String),
+         TimeUnix DateTime64(9),
+         Value Float64,
+         SpanId String,
+         TraceId String
+     ) CODEC(ZSTD(1)),%s
+     INDEX idx_res_attr_key mapKeys(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
+     INDEX idx_res_attr_value mapValues(ResourceAttributes) TYPE bloom_filter(0.01) GRANULARITY 1,
+     INDEX idx_attr_key mapKeys(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1,
+     INDEX idx_attr_value mapValues(Attributes) TYPE bloom_filter(0.01) GRANULARITY 1
+) ENGINE MergeTree()
+%s
+PARTITION BY toDate(TimeUnix)
+ORDER BY (ServiceName, MetricName, toUnixTimestamp64Nano(TimeUnix))
+SETTINGS index_granularity=8192, ttl_only_drop_parts = 1;
+`
+	// language=ClickHouse SQL
+	insertMetricSQLTemplate = `INSERT INTO %s (%s) VALUES (%s)`
+)
+
+func createMetricsTables(cfg *Config, db *sql.DB) error {
+	for _, table := range metricTables {
+		query := fmt.Sprintf(createMetricTableSQL, cfg.MetricsTableName+table.suffix, table.columns, renderTTL(cfg, "TimeUnix"))
+		if _, err := db.Exec(query); err != nil {
+			return fmt.Errorf("exec create metrics table sql: %w", err)
+		}
+	}
+	return nil
+}
+
+func renderInsertMetricSQL(cfg *Config, table metricTable) string {
+	columns := append(append([]string{}, metricsCommonInsertColumns...), table.insertColumns...)
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	return fmt.Sprintf(insertMetricSQLTemplate, cfg.MetricsTableName+table.suffix, strings.Join(columns, ", "), placeholders)
+}
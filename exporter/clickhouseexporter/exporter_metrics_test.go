@@ -0,0 +1,209 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouseexporter
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestExporter_pushMetricsData(t *testing.T) {
+	t.Run("push success", func(t *testing.T) {
+		inserted := map[string]int{}
+		var created []string
+		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
+			if strings.HasPrefix(query, "INSERT") {
+				inserted[strings.Fields(query)[2]]++
+				require.Equal(t, strings.Count(query, "?"), len(values))
+			} else {
+				created = append(created, query)
+			}
+			return nil
+		})
+
+		exporter := newTestMetricsExporter(t, defaultDSN)
+		mustPushMetricsData(t, exporter, simpleMetrics(1))
+		mustPushMetricsData(t, exporter, simpleMetrics(2))
+
+		require.Len(t, created, 5)
+		require.Equal(t, map[string]int{
+			"otel_metrics_gauge":                 3,
+			"otel_metrics_sum":                   3,
+			"otel_metrics_histogram":             3,
+			"otel_metrics_exponential_histogram": 3,
+			"otel_metrics_summary":               3,
+		}, inserted)
+	})
+	t.Run("only the tables of the received metric types are written", func(t *testing.T) {
+		inserted := map[string]int{}
+		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
+			if strings.HasPrefix(query, "INSERT") {
+				inserted[strings.Fields(query)[2]]++
+			}
+			return nil
+		})
+
+		exporter := newTestMetricsExporter(t, defaultDSN)
+		md := pmetric.NewMetrics()
+		m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("gauge")
+		m.SetDataType(pmetric.MetricDataTypeGauge)
+		m.Gauge().DataPoints().AppendEmpty().SetIntVal(1)
+		mustPushMetricsData(t, exporter, md)
+
+		require.Equal(t, map[string]int{"otel_metrics_gauge": 1}, inserted)
+	})
+	t.Run("ttl", func(t *testing.T) {
+		var created []string
+		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
+			created = append(created, query)
+			return nil
+		})
+
+		newTestMetricsExporter(t, defaultDSN, func(cfg *Config) {
+			cfg.TTLDays = 3
+		})
+
+		require.Len(t, created, 5)
+		for _, query := range created {
+			require.Contains(t, query, "TTL toDateTime(TimeUnix) + toIntervalDay(3)")
+		}
+	})
+}
+
+func TestMetricsToRows(t *testing.T) {
+	rows := metricsToRows(simpleMetrics(1))
+
+	gauge := rows[pmetric.MetricDataTypeGauge]
+	require.Len(t, gauge, 1)
+	require.Equal(t, map[string]string{"service.name": "test-service"}, gauge[0][0])
+	require.Equal(t, "scope", gauge[0][2])
+	require.Equal(t, "test-service", gauge[0][6])
+	require.Equal(t, "gauge", gauge[0][7])
+	require.Equal(t, map[string]string{"k": "v"}, gauge[0][10])
+	require.Equal(t, []float64{1}, gauge[0][16])
+	require.Equal(t, float64(1), gauge[0][19])
+
+	sum := rows[pmetric.MetricDataTypeSum]
+	require.Len(t, sum, 1)
+	require.Equal(t, []interface{}{1.5, int32(pmetric.MetricAggregationTemporalityCumulative), true}, sum[0][19:])
+
+	histogram := rows[pmetric.MetricDataTypeHistogram]
+	require.Len(t, histogram, 1)
+	require.Equal(t, []interface{}{uint64(3), float64(6), []uint64{1, 2}, []float64{2}, float64(1), nil,
+		int32(pmetric.MetricAggregationTemporalityDelta)}, histogram[0][19:])
+
+	expHistogram := rows[pmetric.MetricDataTypeExponentialHistogram]
+	require.Len(t, expHistogram, 1)
+	require.Equal(t, []interface{}{uint64(3), float64(6), int32(1), uint64(0), int32(1), []uint64{1, 2}, int32(0), []uint64(nil),
+		nil, nil, int32(pmetric.MetricAggregationTemporalityDelta)}, expHistogram[0][19:])
+
+	summary := rows[pmetric.MetricDataTypeSummary]
+	require.Len(t, summary, 1)
+	require.Equal(t, []interface{}{uint64(3), float64(6), []float64{0.5, 0.99}, []float64{2, 3}}, summary[0][19:])
+}
+
+func newTestMetricsExporter(t *testing.T, dsn string, fns ...func(*Config)) *metricsExporter {
+	exporter, err := newMetricsExporter(zaptest.NewLogger(t), withTestExporterConfig(fns...)(dsn))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = exporter.Shutdown(context.TODO()) })
+	return exporter
+}
+
+// simpleMetrics returns one metric of each type, with count data points each.
+func simpleMetrics(count int) pmetric.Metrics {
+	metrics := pmetric.NewMetrics()
+	rm := metrics.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().UpsertString("service.name", "test-service")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("scope")
+	ts := pcommon.NewTimestampFromTime(time.Now())
+
+	gauge := sm.Metrics().AppendEmpty()
+	gauge.SetName("gauge")
+	gauge.SetDataType(pmetric.MetricDataTypeGauge)
+	sum := sm.Metrics().AppendEmpty()
+	sum.SetName("sum")
+	sum.SetDataType(pmetric.MetricDataTypeSum)
+	sum.Sum().SetAggregationTemporality(pmetric.MetricAggregationTemporalityCumulative)
+	sum.Sum().SetIsMonotonic(true)
+	histogram := sm.Metrics().AppendEmpty()
+	histogram.SetName("histogram")
+	histogram.SetDataType(pmetric.MetricDataTypeHistogram)
+	histogram.Histogram().SetAggregationTemporality(pmetric.MetricAggregationTemporalityDelta)
+	expHistogram := sm.Metrics().AppendEmpty()
+	expHistogram.SetName("exponential_histogram")
+	expHistogram.SetDataType(pmetric.MetricDataTypeExponentialHistogram)
+	expHistogram.ExponentialHistogram().SetAggregationTemporality(pmetric.MetricAggregationTemporalityDelta)
+	summary := sm.Metrics().AppendEmpty()
+	summary.SetName("summary")
+	summary.SetDataType(pmetric.MetricDataTypeSummary)
+
+	for i := 0; i < count; i++ {
+		dp := gauge.Gauge().DataPoints().AppendEmpty()
+		dp.SetTimestamp(ts)
+		dp.SetIntVal(1)
+		dp.Attributes().UpsertString("k", "v")
+		exemplar := dp.Exemplars().AppendEmpty()
+		exemplar.SetIntVal(1)
+		exemplar.SetTraceID(pcommon.NewTraceID([16]byte{1, 2, 3}))
+
+		sdp := sum.Sum().DataPoints().AppendEmpty()
+		sdp.SetTimestamp(ts)
+		sdp.SetDoubleVal(1.5)
+
+		hdp := histogram.Histogram().DataPoints().AppendEmpty()
+		hdp.SetTimestamp(ts)
+		hdp.SetCount(3)
+		hdp.SetSum(6)
+		hdp.SetMin(1)
+		hdp.SetBucketCounts(pcommon.NewImmutableUInt64Slice([]uint64{1, 2}))
+		hdp.SetExplicitBounds(pcommon.NewImmutableFloat64Slice([]float64{2}))
+
+		edp := expHistogram.ExponentialHistogram().DataPoints().AppendEmpty()
+		edp.SetTimestamp(ts)
+		edp.SetCount(3)
+		edp.SetSum(6)
+		edp.SetScale(1)
+		edp.Positive().SetOffset(1)
+		edp.Positive().SetBucketCounts(pcommon.NewImmutableUInt64Slice([]uint64{1, 2}))
+
+		qdp := summary.Summary().DataPoints().AppendEmpty()
+		qdp.SetTimestamp(ts)
+		qdp.SetCount(3)
+		qdp.SetSum(6)
+		q := qdp.QuantileValues().AppendEmpty()
+		q.SetQuantile(0.5)
+		q.SetValue(2)
+		q = qdp.QuantileValues().AppendEmpty()
+		q.SetQuantile(0.99)
+		q.SetValue(3)
+	}
+	return metrics
+}
+
+func mustPushMetricsData(t *testing.T, exporter *metricsExporter, md pmetric.Metrics) {
+	err := exporter.pushMetricsData(context.TODO(), md)
+	require.NoError(t, err)
+}
@@ -0,0 +1,138 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package clickhouseexporter
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestExporter_pushTracesData(t *testing.T) {
+	t.Run("push success", func(t *testing.T) {
+		var items int
+		var created []string
+		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
+			t.Logf("%d, values:%+v", items, values)
+			if strings.HasPrefix(query, "INSERT") {
+				items++
+			} else {
+				created = append(created, query)
+			}
+			return nil
+		})
+
+		exporter := newTestTracesExporter(t, defaultDSN)
+		mustPushTracesData(t, exporter, simpleTraces(1))
+		mustPushTracesData(t, exporter, simpleTraces(2))
+
+		require.Equal(t, 3, items)
+		require.Len(t, created, 1)
+		require.Contains(t, created[0], "CREATE TABLE IF NOT EXISTS otel_traces")
+	})
+	t.Run("span values", func(t *testing.T) {
+		var rows [][]driver.Value
+		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
+			if strings.HasPrefix(query, "INSERT") {
+				rows = append(rows, values)
+			}
+			return nil
+		})
+
+		exporter := newTestTracesExporter(t, defaultDSN)
+		mustPushTracesData(t, exporter, simpleTraces(1))
+
+		require.Len(t, rows, 1)
+		row := rows[0]
+		require.Equal(t, "01020300000000000000000000000000", row[1])
+		require.Equal(t, "0102030000000000", row[2])
+		require.Equal(t, "span", row[5])
+		require.Equal(t, "SPAN_KIND_SERVER", row[6])
+		require.Equal(t, "test-service", row[7])
+		require.Equal(t, map[string]string{"service.name": "test-service"}, row[8])
+		require.Equal(t, map[string]string{"k": "v", "code": "200"}, row[9])
+		require.Equal(t, time.Second.Nanoseconds(), row[10])
+		require.Equal(t, "STATUS_CODE_ERROR", row[11])
+		require.Equal(t, "error", row[12])
+		require.Equal(t, []string{"event"}, row[14])
+		require.Equal(t, []map[string]string{{"ek": "ev"}}, row[15])
+		require.Equal(t, []string{"01020300000000000000000000000000"}, row[16])
+		require.Equal(t, []string{"0102040000000000"}, row[17])
+	})
+	t.Run("ttl", func(t *testing.T) {
+		var created []string
+		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
+			created = append(created, query)
+			return nil
+		})
+
+		newTestTracesExporter(t, defaultDSN, func(cfg *Config) {
+			cfg.TTLDays = 3
+			cfg.TracesTableName = "spans"
+		})
+
+		require.Len(t, created, 1)
+		require.Contains(t, created[0], "CREATE TABLE IF NOT EXISTS spans")
+		require.Contains(t, created[0], "TTL toDateTime(Timestamp) + toIntervalDay(3)")
+	})
+}
+
+func newTestTracesExporter(t *testing.T, dsn string, fns ...func(*Config)) *tracesExporter {
+	exporter, err := newTracesExporter(zaptest.NewLogger(t), withTestExporterConfig(fns...)(dsn))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = exporter.Shutdown(context.TODO()) })
+	return exporter
+}
+
+func simpleTraces(count int) ptrace.Traces {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().UpsertString("service.name", "test-service")
+	ss := rs.ScopeSpans().AppendEmpty()
+	for i := 0; i < count; i++ {
+		s := ss.Spans().AppendEmpty()
+		s.SetTraceID(pcommon.NewTraceID([16]byte{1, 2, 3}))
+		s.SetSpanID(pcommon.NewSpanID([8]byte{1, 2, 3}))
+		s.SetName("span")
+		s.SetKind(ptrace.SpanKindServer)
+		s.SetStartTimestamp(pcommon.NewTimestampFromTime(time.Unix(1, 0)))
+		s.SetEndTimestamp(pcommon.NewTimestampFromTime(time.Unix(2, 0)))
+		s.Attributes().UpsertString("k", "v")
+		s.Attributes().UpsertInt("code", 200)
+		s.Status().SetCode(ptrace.StatusCodeError)
+		s.Status().SetMessage("error")
+		event := s.Events().AppendEmpty()
+		event.SetName("event")
+		event.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1, 0)))
+		event.Attributes().UpsertString("ek", "ev")
+		link := s.Links().AppendEmpty()
+		link.SetTraceID(pcommon.NewTraceID([16]byte{1, 2, 3}))
+		link.SetSpanID(pcommon.NewSpanID([8]byte{1, 2, 4}))
+	}
+	return traces
+}
+
+func mustPushTracesData(t *testing.T, exporter *tracesExporter, td ptrace.Traces) {
+	err := exporter.pushTraceData(context.TODO(), td)
+	require.NoError(t, err)
+}
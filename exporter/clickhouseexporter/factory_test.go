@@ -16,6 +16,7 @@ package clickhouseexporter
 
 import (
 	"context"
+	"database/sql/driver"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,6 +33,7 @@ func TestCreateDefaultConfig(t *testing.T) {
 }
 
 func TestFactory_CreateLogsExporter(t *testing.T) {
+	initClickhouseTestServer(t, func(string, []driver.Value) error { return nil })
 	factory := NewFactory()
 	cfg := withDefaultConfig(func(cfg *Config) {
 		cfg.DSN = defaultDSN
@@ -44,12 +46,40 @@ func TestFactory_CreateLogsExporter(t *testing.T) {
 	require.NoError(t, exporter.Shutdown(context.TODO()))
 }
 
+func TestFactory_CreateTracesExporter(t *testing.T) {
+	initClickhouseTestServer(t, func(string, []driver.Value) error { return nil })
+	factory := NewFactory()
+	cfg := withDefaultConfig(func(cfg *Config) {
+		cfg.DSN = defaultDSN
+	})
+	params := componenttest.NewNopExporterCreateSettings()
+	exporter, err := factory.CreateTracesExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, exporter)
+
+	require.NoError(t, exporter.Shutdown(context.TODO()))
+}
+
+func TestFactory_CreateMetricsExporter(t *testing.T) {
+	initClickhouseTestServer(t, func(string, []driver.Value) error { return nil })
+	factory := NewFactory()
+	cfg := withDefaultConfig(func(cfg *Config) {
+		cfg.DSN = defaultDSN
+	})
+	params := componenttest.NewNopExporterCreateSettings()
+	exporter, err := factory.CreateMetricsExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, exporter)
+
+	require.NoError(t, exporter.Shutdown(context.TODO()))
+}
+
 func TestFactory_CreateMetricsExporter_Fail(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig()
 	params := componenttest.NewNopExporterCreateSettings()
 	_, err := factory.CreateMetricsExporter(context.Background(), params, cfg)
-	require.Error(t, err, "expected an error when creating a traces exporter")
+	require.Error(t, err, "expected an error when creating a metrics exporter")
 }
 
 func TestFactory_CreateTracesExporter_Fail(t *testing.T) {
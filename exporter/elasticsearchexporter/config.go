@@ -53,11 +53,21 @@ type Config struct {
 	Index string `mapstructure:"index"`
 
 	// This setting is required when logging pipelines used.
+	//
+	// The index names can contain placeholders, e.g. `logs-{service.name}`, that are replaced by
+	// the value of the attribute of each document, looked up in the record and then in the resource
+	// attributes. See the README for the placeholders syntax.
 	LogsIndex string `mapstructure:"logs_index"`
 
 	// This setting is required when traces pipelines used.
 	TracesIndex string `mapstructure:"traces_index"`
 
+	// This setting is required when metrics pipelines used.
+	MetricsIndex string `mapstructure:"metrics_index"`
+
+	// IndexDateSuffix configures the date suffix appended to the index names.
+	IndexDateSuffix DateSuffixSettings `mapstructure:"index_date_suffix"`
+
 	// Pipeline configures the ingest node pipeline name that should be used to process the
 	// events.
 	//
@@ -150,6 +160,19 @@ type RetrySettings struct {
 	MaxInterval time.Duration `mapstructure:"max_interval"`
 }
 
+// DateSuffixSettings defines the date based suffix appended to the index names, computed
+// from the timestamp of each document.
+type DateSuffixSettings struct {
+	// Enabled configures whether the date suffix is appended to the index names.
+	Enabled bool `mapstructure:"enabled"`
+
+	// Separator is inserted between the index name and the date.
+	Separator string `mapstructure:"separator"`
+
+	// Format is the Go time layout of the date, e.g. `2006.01.02`.
+	Format string `mapstructure:"format"`
+}
+
 type MappingsSettings struct {
 	// Mode configures the field mappings.
 	Mode string `mapstructure:"mode"`
@@ -172,11 +195,16 @@ type MappingMode int
 const (
 	MappingNone MappingMode = iota
 	MappingECS
+	MappingTSDB
 )
 
 var (
 	errConfigNoEndpoint    = errors.New("endpoints or cloudid must be specified")
 	errConfigEmptyEndpoint = errors.New("endpoints must not include empty entries")
+
+	errConfigEmptyDateSuffixFormat = errors.New("index_date_suffix format must not be empty when enabled")
+
+	errConfigTSDBNotMetrics = errors.New("mapping mode tsdb only applies to metrics")
 )
 
 func (m MappingMode) String() string {
@@ -185,6 +213,8 @@ func (m MappingMode) String() string {
 		return ""
 	case MappingECS:
 		return "ecs"
+	case MappingTSDB:
+		return "tsdb"
 	default:
 		return ""
 	}
@@ -195,6 +225,7 @@ var mappingModes = func() map[string]MappingMode {
 	for _, m := range []MappingMode{
 		MappingNone,
 		MappingECS,
+		MappingTSDB,
 	} {
 		table[strings.ToLower(m.String())] = m
 	}
@@ -226,5 +257,15 @@ func (cfg *Config) Validate() error {
 		return fmt.Errorf("unknown mapping mode %v", cfg.Mapping.Mode)
 	}
 
+	for _, index := range []string{cfg.Index, cfg.LogsIndex, cfg.TracesIndex, cfg.MetricsIndex} {
+		if _, err := newIndexResolver(index, cfg.IndexDateSuffix); err != nil {
+			return err
+		}
+	}
+
+	if cfg.IndexDateSuffix.Enabled && cfg.IndexDateSuffix.Format == "" {
+		return errConfigEmptyDateSuffixFormat
+	}
+
 	return nil
 }
@@ -44,7 +44,12 @@ func TestLoad_DeprecatedIndexConfigOption(t *testing.T) {
 		Index:            "my_log_index",
 		LogsIndex:        "logs-generic-default",
 		TracesIndex:      "traces-generic-default",
-		Pipeline:         "mypipeline",
+		MetricsIndex:     "metrics-generic-default",
+		IndexDateSuffix: DateSuffixSettings{
+			Separator: "-",
+			Format:    "2006.01.02",
+		},
+		Pipeline: "mypipeline",
 		HTTPClientSettings: HTTPClientSettings{
 			Authentication: AuthenticationSettings{
 				User:     "elastic",
@@ -101,7 +106,12 @@ func TestLoadConfig(t *testing.T) {
 		Index:            "",
 		LogsIndex:        "logs-generic-default",
 		TracesIndex:      "trace_index",
-		Pipeline:         "mypipeline",
+		MetricsIndex:     "metrics-generic-default",
+		IndexDateSuffix: DateSuffixSettings{
+			Separator: "-",
+			Format:    "2006.01.02",
+		},
+		Pipeline: "mypipeline",
 		HTTPClientSettings: HTTPClientSettings{
 			Authentication: AuthenticationSettings{
 				User:     "elastic",
@@ -138,9 +148,15 @@ func TestLoadConfig(t *testing.T) {
 		Endpoints:        []string{"http://localhost:9200"},
 		CloudID:          "TRNMxjXlNJEt",
 		Index:            "",
-		LogsIndex:        "my_log_index",
+		LogsIndex:        "logs-{service.name}",
 		TracesIndex:      "traces-generic-default",
-		Pipeline:         "mypipeline",
+		MetricsIndex:     "metrics-generic-default",
+		IndexDateSuffix: DateSuffixSettings{
+			Enabled:   true,
+			Separator: ".",
+			Format:    "2006.01",
+		},
+		Pipeline: "mypipeline",
 		HTTPClientSettings: HTTPClientSettings{
 			Authentication: AuthenticationSettings{
 				User:     "elastic",
@@ -26,9 +26,10 @@ import (
 
 const (
 	// The value of "type" key in configuration.
-	typeStr            = "elasticsearch"
-	defaultLogsIndex   = "logs-generic-default"
-	defaultTracesIndex = "traces-generic-default"
+	typeStr             = "elasticsearch"
+	defaultLogsIndex    = "logs-generic-default"
+	defaultTracesIndex  = "traces-generic-default"
+	defaultMetricsIndex = "metrics-generic-default"
 	// The stability level of the exporter.
 	stability = component.StabilityLevelBeta
 )
@@ -40,6 +41,7 @@ func NewFactory() component.ExporterFactory {
 		createDefaultConfig,
 		component.WithLogsExporter(createLogsExporter, stability),
 		component.WithTracesExporter(createTracesExporter, stability),
+		component.WithMetricsExporter(createMetricsExporter, component.StabilityLevelAlpha),
 	)
 }
 
@@ -49,9 +51,14 @@ func createDefaultConfig() config.Exporter {
 		HTTPClientSettings: HTTPClientSettings{
 			Timeout: 90 * time.Second,
 		},
-		Index:       "",
-		LogsIndex:   defaultLogsIndex,
-		TracesIndex: defaultTracesIndex,
+		Index:        "",
+		LogsIndex:    defaultLogsIndex,
+		TracesIndex:  defaultTracesIndex,
+		MetricsIndex: defaultMetricsIndex,
+		IndexDateSuffix: DateSuffixSettings{
+			Separator: "-",
+			Format:    "2006.01.02",
+		},
 		Retry: RetrySettings{
 			Enabled:         true,
 			MaxRequests:     3,
@@ -103,3 +110,20 @@ func createTracesExporter(ctx context.Context,
 	return exporterhelper.NewTracesExporter(ctx, set, cfg, exporter.pushTraceData,
 		exporterhelper.WithShutdown(exporter.Shutdown))
 }
+
+// createMetricsExporter creates a new exporter for metrics.
+//
+// Data points are indexed into Elasticsearch, either one document per data point,
+// or grouped by resource, attributes and timestamp in the tsdb mapping mode.
+func createMetricsExporter(
+	ctx context.Context,
+	set component.ExporterCreateSettings,
+	cfg config.Exporter,
+) (component.MetricsExporter, error) {
+	exporter, err := newMetricsExporter(set.Logger, cfg.(*Config))
+	if err != nil {
+		return nil, fmt.Errorf("cannot configure Elasticsearch metrics exporter: %w", err)
+	}
+	return exporterhelper.NewMetricsExporter(ctx, set, cfg, exporter.pushMetricsData,
+		exporterhelper.WithShutdown(exporter.Shutdown))
+}
@@ -52,6 +52,19 @@ func TestFactory_CreateMetricsExporter_Fail(t *testing.T) {
 	require.Error(t, err, "expected an error when creating a traces exporter")
 }
 
+func TestFactory_CreateMetricsExporter(t *testing.T) {
+	factory := NewFactory()
+	cfg := withDefaultConfig(func(cfg *Config) {
+		cfg.Endpoints = []string{"test:9200"}
+	})
+	params := componenttest.NewNopExporterCreateSettings()
+	exporter, err := factory.CreateMetricsExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, exporter)
+
+	require.NoError(t, exporter.Shutdown(context.TODO()))
+}
+
 func TestFactory_CreateTracesExporter_Fail(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig()
@@ -77,3 +90,20 @@ func TestFactory_CreateLogsAndTracesExporterWithDeprecatedIndexOption(t *testing
 	require.NotNil(t, tracesExporter)
 	require.NoError(t, tracesExporter.Shutdown(context.TODO()))
 }
+
+func TestFactory_CreateLogsAndTracesExporterWithTSDBMapping(t *testing.T) {
+	factory := NewFactory()
+	cfg := withDefaultConfig(func(cfg *Config) {
+		cfg.Endpoints = []string{"test:9200"}
+		cfg.Mapping.Mode = "tsdb"
+	})
+	params := componenttest.NewNopExporterCreateSettings()
+	_, err := factory.CreateLogsExporter(context.Background(), params, cfg)
+	assert.ErrorIs(t, err, errConfigTSDBNotMetrics)
+	_, err = factory.CreateTracesExporter(context.Background(), params, cfg)
+	assert.ErrorIs(t, err, errConfigTSDBNotMetrics)
+
+	metricsExporter, err := factory.CreateMetricsExporter(context.Background(), params, cfg)
+	require.NoError(t, err)
+	require.NoError(t, metricsExporter.Shutdown(context.TODO()))
+}
@@ -0,0 +1,141 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter"
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// unknownIndexValue replaces the placeholders of an index name whose attributes are all missing and
+// that do not configure a default value.
+const unknownIndexValue = "unknown"
+
+// invalidIndexChars are the characters that are not allowed in Elasticsearch index names.
+//
+// https://www.elastic.co/guide/en/elasticsearch/reference/current/indices-create-index.html#indices-create-api-path-params
+var invalidIndexChars = strings.NewReplacer(
+	"\\", "_", "/", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_",
+	"|", "_", " ", "_", ",", "_", "#", "_", ":", "_",
+)
+
+// indexResolver computes the index name of a document from an index name that may contain attributes
+// placeholders, e.g. `logs-{service.name}-{deployment.environment:default}`, and from its timestamp
+// when the date suffix is enabled.
+type indexResolver struct {
+	// name is the configured index name.
+	name string
+
+	parts      []indexPart
+	dateSuffix DateSuffixSettings
+}
+
+// indexPart is either a literal part of the index name, or a placeholder resolved from the
+// first of its attributes found in the document attributes.
+type indexPart struct {
+	literal      string
+	attributes   []string
+	defaultValue string
+}
+
+func newIndexResolver(name string, dateSuffix DateSuffixSettings) (*indexResolver, error) {
+	r := &indexResolver{name: name, dateSuffix: dateSuffix}
+
+	rest := name
+	for rest != "" {
+		start := strings.IndexByte(rest, '{')
+		if start < 0 {
+			r.parts = append(r.parts, indexPart{literal: rest})
+			break
+		}
+		end := strings.IndexByte(rest[start:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("index %q: unclosed placeholder", name)
+		}
+		end += start
+		if start > 0 {
+			r.parts = append(r.parts, indexPart{literal: rest[:start]})
+		}
+
+		placeholder := rest[start+1 : end]
+		part := indexPart{defaultValue: unknownIndexValue}
+		if i := strings.IndexByte(placeholder, ':'); i >= 0 {
+			part.defaultValue = normalizeIndexValue(placeholder[i+1:])
+			placeholder = placeholder[:i]
+		}
+		for _, attribute := range strings.Split(placeholder, "|") {
+			if attribute = strings.TrimSpace(attribute); attribute == "" {
+				return nil, fmt.Errorf("index %q: empty attribute name in placeholder", name)
+			}
+			part.attributes = append(part.attributes, attribute)
+		}
+		r.parts = append(r.parts, part)
+		rest = rest[end+1:]
+	}
+	return r, nil
+}
+
+// isStatic returns whether the index name is the same for all documents.
+func (r *indexResolver) isStatic() bool {
+	return !r.dateSuffix.Enabled && (len(r.parts) == 0 || len(r.parts) == 1 && r.parts[0].attributes == nil)
+}
+
+// resolve returns the index name of a document. The placeholders are first looked up in the attributes
+// of the document, then in the attributes of its resource.
+func (r *indexResolver) resolve(attributes, resourceAttributes pcommon.Map, timestamp pcommon.Timestamp) string {
+	if r.isStatic() {
+		return r.name
+	}
+
+	var b strings.Builder
+	for _, part := range r.parts {
+		if part.attributes == nil {
+			b.WriteString(part.literal)
+			continue
+		}
+		b.WriteString(part.value(attributes, resourceAttributes))
+	}
+
+	if r.dateSuffix.Enabled {
+		t := time.Now()
+		if timestamp != 0 {
+			t = timestamp.AsTime()
+		}
+		b.WriteString(r.dateSuffix.Separator)
+		b.WriteString(t.UTC().Format(r.dateSuffix.Format))
+	}
+	return b.String()
+}
+
+func (p indexPart) value(attributes, resourceAttributes pcommon.Map) string {
+	for _, attribute := range p.attributes {
+		for _, m := range []pcommon.Map{attributes, resourceAttributes} {
+			if v, ok := m.Get(attribute); ok {
+				if s := v.AsString(); s != "" {
+					return normalizeIndexValue(s)
+				}
+			}
+		}
+	}
+	return p.defaultValue
+}
+
+// normalizeIndexValue makes a placeholder value valid in an index name.
+func normalizeIndexValue(s string) string {
+	return invalidIndexChars.Replace(strings.ToLower(s))
+}
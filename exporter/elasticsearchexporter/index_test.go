@@ -0,0 +1,96 @@
+// Copyright 2021, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestIndexResolver(t *testing.T) {
+	timestamp := pcommon.NewTimestampFromTime(time.Date(2026, 10, 18, 23, 30, 0, 0, time.UTC))
+	dateSuffix := DateSuffixSettings{Enabled: true, Separator: "-", Format: "2006.01.02"}
+
+	tests := map[string]struct {
+		index      string
+		dateSuffix DateSuffixSettings
+		want       string
+	}{
+		"static": {
+			index: "logs-generic-default",
+			want:  "logs-generic-default",
+		},
+		"record attribute": {
+			index: "logs-{service.name}",
+			want:  "logs-checkout",
+		},
+		"record attribute before resource attribute": {
+			index: "logs-{env}",
+			want:  "logs-staging",
+		},
+		"resource attribute": {
+			index: "logs-{tenant}",
+			want:  "logs-acme_corp",
+		},
+		"first attribute found": {
+			index: "logs-{team|tenant}",
+			want:  "logs-acme_corp",
+		},
+		"default value": {
+			index: "logs-{team:default}",
+			want:  "logs-default",
+		},
+		"default value normalized": {
+			index: "logs-{team:Default Value}",
+			want:  "logs-default_value",
+		},
+		"unknown value": {
+			index: "logs-{team}",
+			want:  "logs-unknown",
+		},
+		"date suffix": {
+			index:      "logs-{service.name}",
+			dateSuffix: dateSuffix,
+			want:       "logs-checkout-2026.10.18",
+		},
+	}
+
+	attributes := pcommon.NewMap()
+	attributes.UpsertString("service.name", "checkout")
+	attributes.UpsertString("env", "staging")
+	resourceAttributes := pcommon.NewMap()
+	resourceAttributes.UpsertString("env", "prod")
+	resourceAttributes.UpsertString("tenant", "Acme Corp")
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, err := newIndexResolver(test.index, test.dateSuffix)
+			require.NoError(t, err)
+			assert.Equal(t, test.want, r.resolve(attributes, resourceAttributes, timestamp))
+		})
+	}
+}
+
+func TestIndexResolver_Invalid(t *testing.T) {
+	_, err := newIndexResolver("logs-{service.name", DateSuffixSettings{})
+	assert.ErrorContains(t, err, "unclosed placeholder")
+
+	_, err = newIndexResolver("logs-{|tenant}", DateSuffixSettings{})
+	assert.ErrorContains(t, err, "empty attribute name")
+}
@@ -30,7 +30,7 @@ import (
 type elasticsearchLogsExporter struct {
 	logger *zap.Logger
 
-	index       string
+	index       *indexResolver
 	maxAttempts int
 
 	client      *esClientCurrent
@@ -46,6 +46,9 @@ func newLogsExporter(logger *zap.Logger, cfg *Config) (*elasticsearchLogsExporte
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
+	if mappingModes[cfg.Mapping.Mode] == MappingTSDB {
+		return nil, errConfigTSDBNotMetrics
+	}
 
 	client, err := newElasticsearchClient(logger, cfg)
 	if err != nil {
@@ -69,11 +72,15 @@ func newLogsExporter(logger *zap.Logger, cfg *Config) (*elasticsearchLogsExporte
 	if cfg.Index != "" {
 		indexStr = cfg.Index
 	}
+	index, err := newIndexResolver(indexStr, cfg.IndexDateSuffix)
+	if err != nil {
+		return nil, err
+	}
 	esLogsExp := &elasticsearchLogsExporter{
 		logger:      logger,
 		client:      client,
 		bulkIndexer: bulkIndexer,
-		index:       indexStr,
+		index:       index,
 		maxAttempts: maxAttempts,
 		model:       model,
 	}
@@ -114,5 +121,10 @@ func (e *elasticsearchLogsExporter) pushLogRecord(ctx context.Context, resource
 	if err != nil {
 		return fmt.Errorf("Failed to encode log event: %w", err)
 	}
-	return pushDocuments(ctx, e.logger, e.index, document, e.bulkIndexer, e.maxAttempts)
+	timestamp := record.Timestamp()
+	if timestamp == 0 {
+		timestamp = record.ObservedTimestamp()
+	}
+	index := e.index.resolve(record.Attributes(), resource.Attributes(), timestamp)
+	return pushDocuments(ctx, e.logger, index, document, e.bulkIndexer, e.maxAttempts)
 }
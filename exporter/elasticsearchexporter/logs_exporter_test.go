@@ -27,6 +27,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
@@ -43,7 +45,7 @@ func TestExporter_New(t *testing.T) {
 		return func(t *testing.T, exporter *elasticsearchLogsExporter, err error) {
 			require.Nil(t, err)
 			require.NotNil(t, exporter)
-			require.EqualValues(t, index, exporter.index)
+			require.EqualValues(t, index, exporter.index.name)
 		}
 	}
 
@@ -314,6 +316,30 @@ func TestExporter_PushEvent(t *testing.T) {
 
 		assert.Equal(t, [3]int{1, 2, 1}, attempts)
 	})
+
+	t.Run("dynamic index", func(t *testing.T) {
+		rec := newBulkRecorder()
+		server := newESTestServer(t, func(docs []itemRequest) ([]itemResponse, error) {
+			rec.Record(docs)
+			return itemsAllOK(docs)
+		})
+
+		exporter := newTestExporter(t, server.URL, func(cfg *Config) {
+			cfg.LogsIndex = "logs-{tenant}-{deployment.environment:default}"
+			cfg.IndexDateSuffix.Enabled = true
+		})
+
+		logs := plog.NewLogs()
+		rl := logs.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().UpsertString("tenant", "Acme")
+		record := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		record.SetTimestamp(pcommon.NewTimestampFromTime(time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)))
+		record.Body().SetStringVal("test")
+		require.NoError(t, exporter.pushLogsData(context.TODO(), logs))
+
+		rec.WaitItems(1)
+		assert.JSONEq(t, `{"create":{"_index":"logs-acme-default-2026.10.18"}}`, string(rec.Items()[0].Action))
+	})
 }
 
 func newTestExporter(t *testing.T, url string, fns ...func(*Config)) *elasticsearchLogsExporter {
@@ -338,6 +364,6 @@ func withTestExporterConfig(fns ...func(*Config)) func(string) *Config {
 }
 
 func mustSend(t *testing.T, exporter *elasticsearchLogsExporter, contents string) {
-	err := pushDocuments(context.TODO(), zap.L(), exporter.index, []byte(contents), exporter.bulkIndexer, exporter.maxAttempts)
+	err := pushDocuments(context.TODO(), zap.L(), exporter.index.name, []byte(contents), exporter.bulkIndexer, exporter.maxAttempts)
 	require.NoError(t, err)
 }
@@ -0,0 +1,126 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+type elasticsearchMetricsExporter struct {
+	logger *zap.Logger
+
+	index       *indexResolver
+	maxAttempts int
+	tsdb        bool
+
+	client      *esClientCurrent
+	bulkIndexer esBulkIndexerCurrent
+	model       mappingModel
+}
+
+func newMetricsExporter(logger *zap.Logger, cfg *Config) (*elasticsearchMetricsExporter, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := newElasticsearchClient(logger, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	bulkIndexer, err := newBulkIndexer(logger, client, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	maxAttempts := 1
+	if cfg.Retry.Enabled {
+		maxAttempts = cfg.Retry.MaxRequests
+	}
+
+	model := &encodeModel{dedup: true, dedot: false}
+
+	index, err := newIndexResolver(cfg.MetricsIndex, cfg.IndexDateSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	return &elasticsearchMetricsExporter{
+		logger:      logger,
+		client:      client,
+		bulkIndexer: bulkIndexer,
+
+		index:       index,
+		maxAttempts: maxAttempts,
+		tsdb:        mappingModes[cfg.Mapping.Mode] == MappingTSDB,
+		model:       model,
+	}, nil
+}
+
+func (e *elasticsearchMetricsExporter) Shutdown(ctx context.Context) error {
+	return e.bulkIndexer.Close(ctx)
+}
+
+func (e *elasticsearchMetricsExporter) pushMetricsData(
+	ctx context.Context,
+	md pmetric.Metrics,
+) error {
+	var errs []error
+	resourceMetrics := md.ResourceMetrics()
+	for i := 0; i < resourceMetrics.Len(); i++ {
+		rm := resourceMetrics.At(i)
+
+		var documents []metricDocument
+		if e.tsdb {
+			encoded, err := e.model.encodeMetricsTSDB(rm)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("Failed to encode metrics: %w", err))
+				continue
+			}
+			documents = encoded
+		} else {
+			scopeMetrics := rm.ScopeMetrics()
+			for j := 0; j < scopeMetrics.Len(); j++ {
+				scope := scopeMetrics.At(j).Scope()
+				metrics := scopeMetrics.At(j).Metrics()
+				for k := 0; k < metrics.Len(); k++ {
+					encoded, err := e.model.encodeMetric(rm.Resource(), scope, metrics.At(k))
+					if err != nil {
+						errs = append(errs, fmt.Errorf("Failed to encode metric: %w", err))
+						continue
+					}
+					documents = append(documents, encoded...)
+				}
+			}
+		}
+
+		for _, document := range documents {
+			index := e.index.resolve(document.attributes, rm.Resource().Attributes(), document.timestamp)
+			if err := pushDocuments(ctx, e.logger, index, document.body, e.bulkIndexer, e.maxAttempts); err != nil {
+				if cerr := ctx.Err(); cerr != nil {
+					return cerr
+				}
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return multierr.Combine(errs...)
+}
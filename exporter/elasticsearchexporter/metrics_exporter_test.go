@@ -0,0 +1,189 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package elasticsearchexporter
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap/zaptest"
+)
+
+func TestMetricsExporter_New(t *testing.T) {
+	t.Run("no endpoint", func(t *testing.T) {
+		t.Setenv(defaultElasticsearchEnvName, "")
+		exporter, err := newMetricsExporter(zaptest.NewLogger(t), withDefaultConfig())
+		require.ErrorIs(t, err, errConfigNoEndpoint)
+		require.Nil(t, exporter)
+	})
+
+	t.Run("create from default with endpoints", func(t *testing.T) {
+		exporter, err := newMetricsExporter(zaptest.NewLogger(t), withDefaultConfig(func(cfg *Config) {
+			cfg.Endpoints = []string{"test:9200"}
+		}))
+		require.NoError(t, err)
+		require.NotNil(t, exporter)
+		assert.Equal(t, defaultMetricsIndex, exporter.index.name)
+		assert.False(t, exporter.tsdb)
+	})
+
+	t.Run("tsdb mapping mode", func(t *testing.T) {
+		exporter, err := newMetricsExporter(zaptest.NewLogger(t), withDefaultConfig(func(cfg *Config) {
+			cfg.Endpoints = []string{"test:9200"}
+			cfg.Mapping.Mode = "tsdb"
+		}))
+		require.NoError(t, err)
+		assert.True(t, exporter.tsdb)
+	})
+}
+
+func TestExporter_PushMetricsData(t *testing.T) {
+	t.Run("one document per data point", func(t *testing.T) {
+		rec := newBulkRecorder()
+		server := newESTestServer(t, func(docs []itemRequest) ([]itemResponse, error) {
+			rec.Record(docs)
+			return itemsAllOK(docs)
+		})
+
+		exporter := newTestMetricsExporter(t, server.URL)
+		require.NoError(t, exporter.pushMetricsData(context.TODO(), newTestMetrics()))
+
+		rec.WaitItems(4)
+		docs := decodeItems(t, rec.Items())
+
+		gauges := filterDocuments(docs, "Name", "cpu.usage")
+		require.Len(t, gauges, 2)
+		assert.Equal(t, "Gauge", gauges[0]["Type"])
+		assert.Equal(t, "host-1", gauges[0]["Resource.host.name"])
+		assert.Equal(t, "test-scope", gauges[0]["Scope.Name"])
+		assert.ElementsMatch(t, []interface{}{"0", "1"}, []interface{}{gauges[0]["Attributes.cpu"], gauges[1]["Attributes.cpu"]})
+
+		sums := filterDocuments(docs, "Name", "requests")
+		require.Len(t, sums, 1)
+		assert.Equal(t, float64(42), sums[0]["Value"])
+		assert.Equal(t, "AGGREGATION_TEMPORALITY_CUMULATIVE", sums[0]["AggregationTemporality"])
+		assert.Equal(t, true, sums[0]["IsMonotonic"])
+
+		histograms := filterDocuments(docs, "Name", "latency")
+		require.Len(t, histograms, 1)
+		assert.Equal(t, float64(6), histograms[0]["Count"])
+		assert.Equal(t, []interface{}{float64(1), float64(0), float64(5)}, histograms[0]["BucketCounts"])
+		assert.Equal(t, []interface{}{float64(10), float64(20)}, histograms[0]["ExplicitBounds"])
+	})
+
+	t.Run("tsdb groups data points by dimensions", func(t *testing.T) {
+		rec := newBulkRecorder()
+		server := newESTestServer(t, func(docs []itemRequest) ([]itemResponse, error) {
+			rec.Record(docs)
+			return itemsAllOK(docs)
+		})
+
+		exporter := newTestMetricsExporter(t, server.URL, func(cfg *Config) {
+			cfg.Mapping.Mode = "tsdb"
+		})
+		require.NoError(t, exporter.pushMetricsData(context.TODO(), newTestMetrics()))
+
+		rec.WaitItems(3)
+		docs := decodeItems(t, rec.Items())
+		require.Len(t, docs, 3)
+
+		cpu0 := filterDocuments(docs, "Attributes.cpu", "0")
+		require.Len(t, cpu0, 1)
+		assert.Equal(t, 0.5, cpu0[0]["cpu.usage"])
+		assert.Equal(t, "host-1", cpu0[0]["Resource.host.name"])
+
+		noAttributes := filterDocuments(docs, "Attributes.cpu", nil)
+		require.Len(t, noAttributes, 1)
+		assert.Equal(t, float64(42), noAttributes[0]["requests"])
+		assert.Equal(t, []interface{}{float64(5), float64(20)}, noAttributes[0]["latency.values"])
+		assert.Equal(t, []interface{}{float64(1), float64(5)}, noAttributes[0]["latency.counts"])
+	})
+}
+
+func newTestMetricsExporter(t *testing.T, url string, fns ...func(*Config)) *elasticsearchMetricsExporter {
+	exporter, err := newMetricsExporter(zaptest.NewLogger(t), withTestTracesExporterConfig(fns...)(url))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { exporter.Shutdown(context.TODO()) })
+	return exporter
+}
+
+func newTestMetrics() pmetric.Metrics {
+	ts := pcommon.NewTimestampFromTime(time.Unix(1662000000, 0))
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().UpsertString("host.name", "host-1")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("test-scope")
+
+	gauge := sm.Metrics().AppendEmpty()
+	gauge.SetName("cpu.usage")
+	gauge.SetDataType(pmetric.MetricDataTypeGauge)
+	for i, v := range []float64{0.5, 0.25} {
+		dp := gauge.Gauge().DataPoints().AppendEmpty()
+		dp.SetTimestamp(ts)
+		dp.SetDoubleVal(v)
+		dp.Attributes().UpsertString("cpu", []string{"0", "1"}[i])
+	}
+
+	sum := sm.Metrics().AppendEmpty()
+	sum.SetName("requests")
+	sum.SetDataType(pmetric.MetricDataTypeSum)
+	sum.Sum().SetAggregationTemporality(pmetric.MetricAggregationTemporalityCumulative)
+	sum.Sum().SetIsMonotonic(true)
+	sdp := sum.Sum().DataPoints().AppendEmpty()
+	sdp.SetTimestamp(ts)
+	sdp.SetIntVal(42)
+
+	histogram := sm.Metrics().AppendEmpty()
+	histogram.SetName("latency")
+	histogram.SetDataType(pmetric.MetricDataTypeHistogram)
+	histogram.Histogram().SetAggregationTemporality(pmetric.MetricAggregationTemporalityCumulative)
+	hdp := histogram.Histogram().DataPoints().AppendEmpty()
+	hdp.SetTimestamp(ts)
+	hdp.SetCount(6)
+	hdp.SetSum(110)
+	hdp.SetBucketCounts(pcommon.NewImmutableUInt64Slice([]uint64{1, 0, 5}))
+	hdp.SetExplicitBounds(pcommon.NewImmutableFloat64Slice([]float64{10, 20}))
+
+	return md
+}
+
+func decodeItems(t *testing.T, items []itemRequest) []map[string]interface{} {
+	docs := make([]map[string]interface{}, 0, len(items))
+	for _, item := range items {
+		var doc map[string]interface{}
+		require.NoError(t, json.Unmarshal(item.Document, &doc))
+		docs = append(docs, doc)
+	}
+	return docs
+}
+
+func filterDocuments(docs []map[string]interface{}, key string, value interface{}) []map[string]interface{} {
+	var filtered []map[string]interface{}
+	for _, doc := range docs {
+		if doc[key] == value {
+			filtered = append(filtered, doc)
+		}
+	}
+	return filtered
+}
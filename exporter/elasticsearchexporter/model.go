@@ -17,9 +17,12 @@ package elasticsearchexporter // import "github.com/open-telemetry/opentelemetry
 import (
 	"bytes"
 	"encoding/json"
+	"sort"
+	"strings"
 
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/elasticsearchexporter/internal/objmodel"
@@ -28,6 +31,16 @@ import (
 type mappingModel interface {
 	encodeLog(pcommon.Resource, plog.LogRecord) ([]byte, error)
 	encodeSpan(pcommon.Resource, ptrace.Span) ([]byte, error)
+	encodeMetric(pcommon.Resource, pcommon.InstrumentationScope, pmetric.Metric) ([]metricDocument, error)
+	encodeMetricsTSDB(pmetric.ResourceMetrics) ([]metricDocument, error)
+}
+
+// metricDocument is an encoded metric document, along with the attributes and the timestamp
+// of the data points it contains.
+type metricDocument struct {
+	attributes pcommon.Map
+	timestamp  pcommon.Timestamp
+	body       []byte
 }
 
 // encodeModel tries to keep the event as close to the original open telemetry semantics as is.
@@ -59,15 +72,7 @@ func (m *encodeModel) encodeLog(resource pcommon.Resource, record plog.LogRecord
 	document.AddAttributes("Attributes", record.Attributes())
 	document.AddAttributes("Resource", resource.Attributes())
 
-	if m.dedup {
-		document.Dedup()
-	} else if m.dedot {
-		document.Sort()
-	}
-
-	var buf bytes.Buffer
-	err := document.Serialize(&buf, m.dedot)
-	return buf.Bytes(), err
+	return m.serialize(document)
 }
 
 func (m *encodeModel) encodeSpan(resource pcommon.Resource, span ptrace.Span) ([]byte, error) {
@@ -84,6 +89,195 @@ func (m *encodeModel) encodeSpan(resource pcommon.Resource, span ptrace.Span) ([
 	document.AddAttributes("Attributes", span.Attributes())
 	document.AddAttributes("Resource", resource.Attributes())
 
+	return m.serialize(document)
+}
+
+// encodeMetric encodes each data point of the metric into its own document.
+func (m *encodeModel) encodeMetric(resource pcommon.Resource, scope pcommon.InstrumentationScope, metric pmetric.Metric) ([]metricDocument, error) {
+	var documents []objmodel.Document
+	var encoded []metricDocument
+	newDocument := func(attributes pcommon.Map, startTimestamp, timestamp pcommon.Timestamp) objmodel.Document {
+		encoded = append(encoded, metricDocument{attributes: attributes, timestamp: timestamp})
+		var document objmodel.Document
+		document.AddTimestamp("@timestamp", timestamp) // We use @timestamp in order to ensure that we can index if the default data stream metrics template is used.
+		if startTimestamp != 0 {
+			document.AddTimestamp("StartTimestamp", startTimestamp)
+		}
+		document.AddString("Name", metric.Name())
+		document.AddString("Description", metric.Description())
+		document.AddString("Unit", metric.Unit())
+		document.AddString("Type", metric.DataType().String())
+		document.AddString("Scope.Name", scope.Name())
+		document.AddString("Scope.Version", scope.Version())
+		document.AddAttributes("Attributes", attributes)
+		document.AddAttributes("Resource", resource.Attributes())
+		return document
+	}
+
+	switch metric.DataType() {
+	case pmetric.MetricDataTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			document := newDocument(dp.Attributes(), dp.StartTimestamp(), dp.Timestamp())
+			document.Add("Value", numberValue(dp))
+			documents = append(documents, document)
+		}
+	case pmetric.MetricDataTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			document := newDocument(dp.Attributes(), dp.StartTimestamp(), dp.Timestamp())
+			document.Add("Value", numberValue(dp))
+			document.AddString("AggregationTemporality", metric.Sum().AggregationTemporality().String())
+			document.Add("IsMonotonic", objmodel.BoolValue(metric.Sum().IsMonotonic()))
+			documents = append(documents, document)
+		}
+	case pmetric.MetricDataTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			document := newDocument(dp.Attributes(), dp.StartTimestamp(), dp.Timestamp())
+			document.AddInt("Count", int64(dp.Count()))
+			addOptionalDouble(&document, "Sum", dp.HasSum(), dp.Sum())
+			addOptionalDouble(&document, "Min", dp.HasMin(), dp.Min())
+			addOptionalDouble(&document, "Max", dp.HasMax(), dp.Max())
+			document.Add("BucketCounts", uintArrValue(dp.BucketCounts().AsRaw()))
+			document.Add("ExplicitBounds", doubleArrValue(dp.ExplicitBounds().AsRaw()))
+			document.AddString("AggregationTemporality", metric.Histogram().AggregationTemporality().String())
+			documents = append(documents, document)
+		}
+	case pmetric.MetricDataTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			document := newDocument(dp.Attributes(), dp.StartTimestamp(), dp.Timestamp())
+			document.AddInt("Count", int64(dp.Count()))
+			addOptionalDouble(&document, "Sum", dp.HasSum(), dp.Sum())
+			addOptionalDouble(&document, "Min", dp.HasMin(), dp.Min())
+			addOptionalDouble(&document, "Max", dp.HasMax(), dp.Max())
+			document.AddInt("Scale", int64(dp.Scale()))
+			document.AddInt("ZeroCount", int64(dp.ZeroCount()))
+			document.AddInt("Positive.Offset", int64(dp.Positive().Offset()))
+			document.Add("Positive.BucketCounts", uintArrValue(dp.Positive().BucketCounts().AsRaw()))
+			document.AddInt("Negative.Offset", int64(dp.Negative().Offset()))
+			document.Add("Negative.BucketCounts", uintArrValue(dp.Negative().BucketCounts().AsRaw()))
+			document.AddString("AggregationTemporality", metric.ExponentialHistogram().AggregationTemporality().String())
+			documents = append(documents, document)
+		}
+	case pmetric.MetricDataTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			document := newDocument(dp.Attributes(), dp.StartTimestamp(), dp.Timestamp())
+			document.AddInt("Count", int64(dp.Count()))
+			document.Add("Sum", objmodel.DoubleValue(dp.Sum()))
+			quantiles := make([]float64, 0, dp.QuantileValues().Len())
+			values := make([]float64, 0, dp.QuantileValues().Len())
+			for j := 0; j < dp.QuantileValues().Len(); j++ {
+				quantiles = append(quantiles, dp.QuantileValues().At(j).Quantile())
+				values = append(values, dp.QuantileValues().At(j).Value())
+			}
+			document.Add("Quantiles", doubleArrValue(quantiles))
+			document.Add("QuantileValues", doubleArrValue(values))
+			documents = append(documents, document)
+		}
+	}
+
+	for i, document := range documents {
+		buf, err := m.serialize(document)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i].body = buf
+	}
+	return encoded, nil
+}
+
+// encodeMetricsTSDB encodes the data points sharing the same scope, attributes and timestamp into
+// a single document, with one field per metric, as expected by Elasticsearch time series data streams.
+// The resource and data point attributes are the dimensions of the time series.
+func (m *encodeModel) encodeMetricsTSDB(resourceMetrics pmetric.ResourceMetrics) ([]metricDocument, error) {
+	var encoded []metricDocument
+	scopeMetrics := resourceMetrics.ScopeMetrics()
+	for i := 0; i < scopeMetrics.Len(); i++ {
+		scope := scopeMetrics.At(i).Scope()
+		documents := map[string]*objmodel.Document{}
+		var keys []string
+		var dimensions []metricDocument
+		documentFor := func(attributes pcommon.Map, timestamp pcommon.Timestamp) *objmodel.Document {
+			key := timestamp.String() + "\x00" + attributesKey(attributes)
+			if document, ok := documents[key]; ok {
+				return document
+			}
+			document := &objmodel.Document{}
+			document.AddTimestamp("@timestamp", timestamp)
+			document.AddString("Scope.Name", scope.Name())
+			document.AddString("Scope.Version", scope.Version())
+			document.AddAttributes("Attributes", attributes)
+			document.AddAttributes("Resource", resourceMetrics.Resource().Attributes())
+			documents[key] = document
+			keys = append(keys, key)
+			dimensions = append(dimensions, metricDocument{attributes: attributes, timestamp: timestamp})
+			return document
+		}
+
+		metrics := scopeMetrics.At(i).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			metric := metrics.At(j)
+			name := metric.Name()
+			switch metric.DataType() {
+			case pmetric.MetricDataTypeGauge:
+				dps := metric.Gauge().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					documentFor(dps.At(k).Attributes(), dps.At(k).Timestamp()).Add(name, numberValue(dps.At(k)))
+				}
+			case pmetric.MetricDataTypeSum:
+				dps := metric.Sum().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					documentFor(dps.At(k).Attributes(), dps.At(k).Timestamp()).Add(name, numberValue(dps.At(k)))
+				}
+			case pmetric.MetricDataTypeHistogram:
+				dps := metric.Histogram().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					values, counts := histogramValues(dps.At(k))
+					if len(values) == 0 {
+						continue
+					}
+					document := documentFor(dps.At(k).Attributes(), dps.At(k).Timestamp())
+					document.Add(name+".values", doubleArrValue(values))
+					document.Add(name+".counts", uintArrValue(counts))
+				}
+			case pmetric.MetricDataTypeExponentialHistogram:
+				dps := metric.ExponentialHistogram().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					document := documentFor(dps.At(k).Attributes(), dps.At(k).Timestamp())
+					document.Add(name+".sum", objmodel.DoubleValue(dps.At(k).Sum()))
+					document.AddInt(name+".value_count", int64(dps.At(k).Count()))
+				}
+			case pmetric.MetricDataTypeSummary:
+				dps := metric.Summary().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					document := documentFor(dps.At(k).Attributes(), dps.At(k).Timestamp())
+					document.Add(name+".sum", objmodel.DoubleValue(dps.At(k).Sum()))
+					document.AddInt(name+".value_count", int64(dps.At(k).Count()))
+				}
+			}
+		}
+
+		for i, key := range keys {
+			buf, err := m.serialize(*documents[key])
+			if err != nil {
+				return nil, err
+			}
+			dimensions[i].body = buf
+			encoded = append(encoded, dimensions[i])
+		}
+	}
+	return encoded, nil
+}
+
+func (m *encodeModel) serialize(document objmodel.Document) ([]byte, error) {
 	if m.dedup {
 		document.Dedup()
 	} else if m.dedot {
@@ -95,6 +289,81 @@ func (m *encodeModel) encodeSpan(resource pcommon.Resource, span ptrace.Span) ([
 	return buf.Bytes(), err
 }
 
+func numberValue(dp pmetric.NumberDataPoint) objmodel.Value {
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		return objmodel.IntValue(dp.IntVal())
+	}
+	return objmodel.DoubleValue(dp.DoubleVal())
+}
+
+func addOptionalDouble(document *objmodel.Document, key string, isSet bool, value float64) {
+	if isSet {
+		document.Add(key, objmodel.DoubleValue(value))
+	}
+}
+
+func uintArrValue(values []uint64) objmodel.Value {
+	arr := make([]objmodel.Value, 0, len(values))
+	for _, v := range values {
+		arr = append(arr, objmodel.IntValue(int64(v)))
+	}
+	return objmodel.ArrValue(arr...)
+}
+
+func doubleArrValue(values []float64) objmodel.Value {
+	arr := make([]objmodel.Value, 0, len(values))
+	for _, v := range values {
+		arr = append(arr, objmodel.DoubleValue(v))
+	}
+	return objmodel.ArrValue(arr...)
+}
+
+// histogramValues converts the buckets of the data point to the values and counts of an Elasticsearch
+// histogram field, using the middle of each bucket as its value. The first and last buckets are unbounded,
+// their values are respectively half of the first bound, when it is positive, and the last bound.
+// Empty buckets are skipped.
+func histogramValues(dp pmetric.HistogramDataPoint) ([]float64, []uint64) {
+	bounds := dp.ExplicitBounds()
+	counts := dp.BucketCounts()
+	if bounds.Len() == 0 || counts.Len() != bounds.Len()+1 {
+		return nil, nil
+	}
+
+	var values []float64
+	var nonEmptyCounts []uint64
+	for i := 0; i < counts.Len(); i++ {
+		if counts.At(i) == 0 {
+			continue
+		}
+		var value float64
+		switch {
+		case i == 0:
+			value = bounds.At(0)
+			if value > 0 {
+				value /= 2
+			}
+		case i == bounds.Len():
+			value = bounds.At(i - 1)
+		default:
+			value = bounds.At(i-1) + (bounds.At(i)-bounds.At(i-1))/2
+		}
+		values = append(values, value)
+		nonEmptyCounts = append(nonEmptyCounts, counts.At(i))
+	}
+	return values, nonEmptyCounts
+}
+
+// attributesKey returns an identifier of the attributes that doesn't depend on their order.
+func attributesKey(attributes pcommon.Map) string {
+	pairs := make([]string, 0, attributes.Len())
+	attributes.Range(func(k string, v pcommon.Value) bool {
+		pairs = append(pairs, k+"="+v.AsString())
+		return true
+	})
+	sort.Strings(pairs)
+	return strings.Join(pairs, "\x00")
+}
+
 func spanLinksToString(spanLinkSlice ptrace.SpanLinkSlice) string {
 	linkArray := make([]map[string]interface{}, 0, spanLinkSlice.Len())
 	for i := 0; i < spanLinkSlice.Len(); i++ {
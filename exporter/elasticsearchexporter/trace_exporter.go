@@ -30,7 +30,7 @@ import (
 type elasticsearchTracesExporter struct {
 	logger *zap.Logger
 
-	index       string
+	index       *indexResolver
 	maxAttempts int
 
 	client      *esClientCurrent
@@ -42,6 +42,9 @@ func newTracesExporter(logger *zap.Logger, cfg *Config) (*elasticsearchTracesExp
 	if err := cfg.Validate(); err != nil {
 		return nil, err
 	}
+	if mappingModes[cfg.Mapping.Mode] == MappingTSDB {
+		return nil, errConfigTSDBNotMetrics
+	}
 
 	client, err := newElasticsearchClient(logger, cfg)
 	if err != nil {
@@ -61,12 +64,17 @@ func newTracesExporter(logger *zap.Logger, cfg *Config) (*elasticsearchTracesExp
 	// TODO: Apply encoding and field mapping settings.
 	model := &encodeModel{dedup: true, dedot: false}
 
+	index, err := newIndexResolver(cfg.TracesIndex, cfg.IndexDateSuffix)
+	if err != nil {
+		return nil, err
+	}
+
 	return &elasticsearchTracesExporter{
 		logger:      logger,
 		client:      client,
 		bulkIndexer: bulkIndexer,
 
-		index:       cfg.TracesIndex,
+		index:       index,
 		maxAttempts: maxAttempts,
 		model:       model,
 	}, nil
@@ -107,5 +115,6 @@ func (e *elasticsearchTracesExporter) pushTraceRecord(ctx context.Context, resou
 	if err != nil {
 		return fmt.Errorf("Failed to encode trace record: %w", err)
 	}
-	return pushDocuments(ctx, e.logger, e.index, document, e.bulkIndexer, e.maxAttempts)
+	index := e.index.resolve(span.Attributes(), resource.Attributes(), span.StartTimestamp())
+	return pushDocuments(ctx, e.logger, index, document, e.bulkIndexer, e.maxAttempts)
 }
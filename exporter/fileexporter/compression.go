@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
+
+import (
+	"bytes"
+	"compress/gzip"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// compressFunc compresses a marshaled message.
+type compressFunc func(src []byte) ([]byte, error)
+
+// buildCompressor returns the compressFunc of the codec, the codec is expected to be valid.
+func buildCompressor(codec string) compressFunc {
+	switch codec {
+	case compressionGZIP:
+		return gzipCompress
+	case compressionZSTD:
+		return zstdCompress
+	default:
+		return noneCompress
+	}
+}
+
+func noneCompress(src []byte) ([]byte, error) {
+	return src, nil
+}
+
+func gzipCompress(src []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// zstdEncoder is safe for concurrent use with EncodeAll.
+var zstdEncoder, _ = zstd.NewWriter(nil)
+
+func zstdCompress(src []byte) ([]byte, error) {
+	return zstdEncoder.EncodeAll(src, nil), nil
+}
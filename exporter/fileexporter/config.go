@@ -16,16 +16,68 @@ package fileexporter // import "github.com/open-telemetry/opentelemetry-collecto
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"go.opentelemetry.io/collector/config"
 )
 
+const (
+	formatTypeJSON  = "json"
+	formatTypeProto = "proto"
+
+	compressionGZIP = "gzip"
+	compressionZSTD = "zstd"
+)
+
 // Config defines configuration for file exporter.
 type Config struct {
 	config.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 
 	// Path of the file to write to. Path is relative to current directory.
+	// The path may contain resource attributes placeholders, e.g.:
+	// /var/otel/{{resource.attributes["service.name"]}}/traces.json, each resource
+	// is then written to the file of its attributes values.
 	Path string `mapstructure:"path"`
+
+	// MaxOpenFiles is the maximum number of files of a templated path kept open,
+	// the least recently used file is closed to open another one.
+	MaxOpenFiles int `mapstructure:"max_open_files"`
+
+	// IdleTimeout is the duration after which the files of a templated path that
+	// are not written to are closed. The files are kept open when it is zero.
+	IdleTimeout time.Duration `mapstructure:"idle_timeout"`
+
+	// Rotation defines when the file is rotated and how many rotated files are kept.
+	// The file is never rotated when it is not set.
+	Rotation *Rotation `mapstructure:"rotation"`
+
+	// FormatType is the encoding of the telemetry data, either "json" (default) for
+	// one Protobuf-JSON message per line, or "proto" for length-delimited OTLP Protobuf.
+	FormatType string `mapstructure:"format"`
+
+	// Compression is the codec each export is compressed with, either "gzip" or "zstd".
+	// The data is not compressed when it is not set.
+	Compression string `mapstructure:"compression"`
+}
+
+// Rotation defines the rotation of the file, the rotated files are kept next to it
+// with the time of the rotation added to their name.
+type Rotation struct {
+	// MaxMegabytes is the maximum size in megabytes of the file before it is rotated.
+	// Defaults to 100 megabytes.
+	MaxMegabytes int `mapstructure:"max_megabytes"`
+
+	// MaxAge is the maximum duration the file is written to before it is rotated.
+	// The file is not rotated based on its age when it is not set.
+	MaxAge time.Duration `mapstructure:"max_age"`
+
+	// MaxBackups is the maximum number of rotated files kept, the oldest ones are removed.
+	// All rotated files are kept when it is not set.
+	MaxBackups int `mapstructure:"max_backups"`
+
+	// LocalTime uses the local time instead of UTC in the names of the rotated files.
+	LocalTime bool `mapstructure:"localtime"`
 }
 
 var _ config.Exporter = (*Config)(nil)
@@ -35,6 +87,33 @@ func (cfg *Config) Validate() error {
 	if cfg.Path == "" {
 		return errors.New("path must be non-empty")
 	}
+	pt, err := parsePathTemplate(cfg.Path)
+	if err != nil {
+		return err
+	}
+	if pt != nil && cfg.MaxOpenFiles <= 0 {
+		return errors.New("max_open_files must be positive")
+	}
+	if cfg.IdleTimeout < 0 {
+		return errors.New("idle_timeout must be non-negative")
+	}
+	if cfg.FormatType != formatTypeJSON && cfg.FormatType != formatTypeProto {
+		return fmt.Errorf("format type %q is not supported, must be %q or %q", cfg.FormatType, formatTypeJSON, formatTypeProto)
+	}
+	if cfg.Compression != "" && cfg.Compression != compressionGZIP && cfg.Compression != compressionZSTD {
+		return fmt.Errorf("compression %q is not supported, must be %q or %q", cfg.Compression, compressionGZIP, compressionZSTD)
+	}
+	if cfg.Rotation != nil {
+		if cfg.Rotation.MaxMegabytes < 0 {
+			return errors.New("rotation max_megabytes must be non-negative")
+		}
+		if cfg.Rotation.MaxAge < 0 {
+			return errors.New("rotation max_age must be non-negative")
+		}
+		if cfg.Rotation.MaxBackups < 0 {
+			return errors.New("rotation max_backups must be non-negative")
+		}
+	}
 
 	return nil
 }
@@ -17,6 +17,7 @@ package fileexporter
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -43,5 +44,87 @@ func TestLoadConfig(t *testing.T) {
 		&Config{
 			ExporterSettings: config.NewExporterSettings(config.NewComponentIDWithName(typeStr, "2")),
 			Path:             "./filename.json",
+			FormatType:       formatTypeJSON,
+			MaxOpenFiles:     defaultMaxOpenFiles,
+			IdleTimeout:      defaultIdleTimeout,
 		})
+
+	e2 := cfg.Exporters[config.NewComponentIDWithName(typeStr, "3")]
+	assert.Equal(t, e2,
+		&Config{
+			ExporterSettings: config.NewExporterSettings(config.NewComponentIDWithName(typeStr, "3")),
+			Path:             "./archive.pb",
+			Rotation: &Rotation{
+				MaxMegabytes: 10,
+				MaxAge:       24 * time.Hour,
+				MaxBackups:   5,
+				LocalTime:    true,
+			},
+			FormatType:   formatTypeProto,
+			Compression:  compressionZSTD,
+			MaxOpenFiles: defaultMaxOpenFiles,
+			IdleTimeout:  defaultIdleTimeout,
+		})
+
+	e3 := cfg.Exporters[config.NewComponentIDWithName(typeStr, "4")]
+	assert.Equal(t, e3,
+		&Config{
+			ExporterSettings: config.NewExporterSettings(config.NewComponentIDWithName(typeStr, "4")),
+			Path:             `./{{resource.attributes["service.name"]}}/traces.json`,
+			FormatType:       formatTypeJSON,
+			MaxOpenFiles:     10,
+			IdleTimeout:      time.Minute,
+		})
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    *Config
+		errMsg string
+	}{
+		{
+			name: "valid",
+			cfg:  &Config{Path: "file.json", FormatType: formatTypeJSON, Compression: compressionGZIP, Rotation: &Rotation{MaxMegabytes: 1}},
+		},
+		{
+			name:   "invalid format",
+			cfg:    &Config{Path: "file.json", FormatType: "yaml"},
+			errMsg: `format type "yaml" is not supported, must be "json" or "proto"`,
+		},
+		{
+			name:   "invalid compression",
+			cfg:    &Config{Path: "file.json", FormatType: formatTypeJSON, Compression: "lz4"},
+			errMsg: `compression "lz4" is not supported, must be "gzip" or "zstd"`,
+		},
+		{
+			name: "valid template",
+			cfg:  &Config{Path: `{{ resource.attributes["tenant.id"] }}.json`, FormatType: formatTypeJSON, MaxOpenFiles: 1},
+		},
+		{
+			name:   "unsupported placeholder",
+			cfg:    &Config{Path: `{{attributes["tenant.id"]}}.json`, FormatType: formatTypeJSON, MaxOpenFiles: 1},
+			errMsg: `unsupported placeholder "{{attributes[\"tenant.id\"]}}" in path, only resource attributes are supported, e.g.: {{resource.attributes["service.name"]}}`,
+		},
+		{
+			name:   "template without max_open_files",
+			cfg:    &Config{Path: `{{resource.attributes["tenant.id"]}}.json`, FormatType: formatTypeJSON},
+			errMsg: "max_open_files must be positive",
+		},
+		{
+			name:   "negative max_backups",
+			cfg:    &Config{Path: "file.json", FormatType: formatTypeJSON, Rotation: &Rotation{MaxBackups: -1}},
+			errMsg: "rotation max_backups must be non-negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.errMsg == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
 }
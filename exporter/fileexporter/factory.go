@@ -16,6 +16,7 @@ package fileexporter // import "github.com/open-telemetry/opentelemetry-collecto
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
@@ -29,6 +30,9 @@ const (
 	typeStr = "file"
 	// The stability level of the exporter.
 	stability = component.StabilityLevelAlpha
+
+	defaultMaxOpenFiles = 100
+	defaultIdleTimeout  = 5 * time.Minute
 )
 
 // NewFactory creates a factory for OTLP exporter.
@@ -44,6 +48,9 @@ func NewFactory() component.ExporterFactory {
 func createDefaultConfig() config.Exporter {
 	return &Config{
 		ExporterSettings: config.NewExporterSettings(config.NewComponentID(typeStr)),
+		FormatType:       formatTypeJSON,
+		MaxOpenFiles:     defaultMaxOpenFiles,
+		IdleTimeout:      defaultIdleTimeout,
 	}
 }
 
@@ -53,8 +60,11 @@ func createTracesExporter(
 	cfg config.Exporter,
 ) (component.TracesExporter, error) {
 	fe := exporters.GetOrAdd(cfg, func() component.Component {
-		return &fileExporter{path: cfg.(*Config).Path}
+		return newFileExporter(cfg.(*Config))
 	})
+	if err := fe.Unwrap().(*fileExporter).addSignal(config.TracesDataType); err != nil {
+		return nil, err
+	}
 	return exporterhelper.NewTracesExporter(
 		ctx,
 		set,
@@ -71,8 +81,11 @@ func createMetricsExporter(
 	cfg config.Exporter,
 ) (component.MetricsExporter, error) {
 	fe := exporters.GetOrAdd(cfg, func() component.Component {
-		return &fileExporter{path: cfg.(*Config).Path}
+		return newFileExporter(cfg.(*Config))
 	})
+	if err := fe.Unwrap().(*fileExporter).addSignal(config.MetricsDataType); err != nil {
+		return nil, err
+	}
 	return exporterhelper.NewMetricsExporter(
 		ctx,
 		set,
@@ -89,8 +102,11 @@ func createLogsExporter(
 	cfg config.Exporter,
 ) (component.LogsExporter, error) {
 	fe := exporters.GetOrAdd(cfg, func() component.Component {
-		return &fileExporter{path: cfg.(*Config).Path}
+		return newFileExporter(cfg.(*Config))
 	})
+	if err := fe.Unwrap().(*fileExporter).addSignal(config.LogsDataType); err != nil {
+		return nil, err
+	}
 	return exporterhelper.NewLogsExporter(
 		ctx,
 		set,
@@ -59,3 +59,30 @@ func TestCreateLogsExporter(t *testing.T) {
 	assert.NoError(t, err)
 	require.NotNil(t, exp)
 }
+
+func TestCreateExportersProtoSharedBySignals(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.FormatType = formatTypeProto
+	set := componenttest.NewNopExporterCreateSettings()
+
+	texp, err := createTracesExporter(context.Background(), set, cfg)
+	require.NoError(t, err)
+	require.NotNil(t, texp)
+
+	_, err = createMetricsExporter(context.Background(), set, cfg)
+	assert.ErrorIs(t, err, errProtoSharedBySignals)
+	_, err = createLogsExporter(context.Background(), set, cfg)
+	assert.ErrorIs(t, err, errProtoSharedBySignals)
+}
+
+func TestCreateExportersJSONSharedBySignals(t *testing.T) {
+	cfg := createDefaultConfig()
+	set := componenttest.NewNopExporterCreateSettings()
+
+	_, err := createTracesExporter(context.Background(), set, cfg)
+	require.NoError(t, err)
+	_, err = createMetricsExporter(context.Background(), set, cfg)
+	require.NoError(t, err)
+	_, err = createLogsExporter(context.Background(), set, cfg)
+	require.NoError(t, err)
+}
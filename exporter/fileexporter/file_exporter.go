@@ -16,28 +16,109 @@ package fileexporter // import "github.com/open-telemetry/opentelemetry-collecto
 
 import (
 	"context"
+	"errors"
+	"encoding/binary"
 	"io"
 	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/multierr"
 )
 
-// Marshaler configuration used for marhsaling Protobuf to JSON.
-var tracesMarshaler = ptrace.NewJSONMarshaler()
-var metricsMarshaler = pmetric.NewJSONMarshaler()
-var logsMarshaler = plog.NewJSONMarshaler()
+// errProtoSharedBySignals is returned when an exporter writing the proto format
+// is used for several signals, the messages then couldn't be told apart.
+var errProtoSharedBySignals = errors.New("the proto format requires a separate file exporter for each signal")
+
+// Marshalers of each format type.
+var tracesMarshalers = map[string]ptrace.Marshaler{
+	formatTypeJSON:  ptrace.NewJSONMarshaler(),
+	formatTypeProto: ptrace.NewProtoMarshaler(),
+}
+var metricsMarshalers = map[string]pmetric.Marshaler{
+	formatTypeJSON:  pmetric.NewJSONMarshaler(),
+	formatTypeProto: pmetric.NewProtoMarshaler(),
+}
+var logsMarshalers = map[string]plog.Marshaler{
+	formatTypeJSON:  plog.NewJSONMarshaler(),
+	formatTypeProto: plog.NewProtoMarshaler(),
+}
 
 // fileExporter is the implementation of file exporter that writes telemetry data to a file
-// in Protobuf-JSON format.
+// in Protobuf-JSON format, or in length-delimited Protobuf format.
 type fileExporter struct {
-	path  string
-	file  io.WriteCloser
-	mutex sync.Mutex
+	path     string
+	rotation *Rotation
+	file     io.WriteCloser
+	mutex    sync.Mutex
+
+	// pathTemplate is set when the path contains resource attributes placeholders,
+	// the data is then written to the files kept open in files instead of file.
+	pathTemplate *pathTemplate
+	files        *fileHandles
+	stopCh       chan struct{}
+	wg           sync.WaitGroup
+
+	tracesMarshaler  ptrace.Marshaler
+	metricsMarshaler pmetric.Marshaler
+	logsMarshaler    plog.Marshaler
+
+	compressor compressFunc
+	exporter   exportFunc
+
+	formatType string
+	signals    map[config.DataType]struct{}
+}
+
+// exportFunc writes a marshaled message to the file.
+type exportFunc func(w io.Writer, buf []byte) error
+
+func newFileExporter(conf *Config) *fileExporter {
+	formatType := conf.FormatType
+	if formatType == "" {
+		formatType = formatTypeJSON
+	}
+	fe := &fileExporter{
+		path:             conf.Path,
+		rotation:         conf.Rotation,
+		tracesMarshaler:  tracesMarshalers[formatType],
+		metricsMarshaler: metricsMarshalers[formatType],
+		logsMarshaler:    logsMarshalers[formatType],
+		compressor:       buildCompressor(conf.Compression),
+		exporter:         exportMessageAsLine,
+		formatType:       formatType,
+		signals:          make(map[config.DataType]struct{}),
+	}
+	// Compressed data may contain new lines, and Protobuf data always may,
+	// the length of each message is written before it instead.
+	if formatType == formatTypeProto || conf.Compression != "" {
+		fe.exporter = exportMessageAsBuffer
+	}
+	// The path is validated with the configuration.
+	fe.pathTemplate, _ = parsePathTemplate(conf.Path)
+	if fe.pathTemplate != nil {
+		fe.files = newFileHandles(fe.openTemplatedFile, conf.MaxOpenFiles, conf.IdleTimeout)
+	}
+	return fe
+}
+
+// addSignal records that the exporter writes the data of the signal. The
+// proto format is only decodable when the file holds a single signal.
+func (e *fileExporter) addSignal(dataType config.DataType) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	if _, ok := e.signals[dataType]; !ok && e.formatType == formatTypeProto && len(e.signals) > 0 {
+		return errProtoSharedBySignals
+	}
+	e.signals[dataType] = struct{}{}
+	return nil
 }
 
 func (e *fileExporter) Capabilities() consumer.Capabilities {
@@ -45,49 +126,162 @@ func (e *fileExporter) Capabilities() consumer.Capabilities {
 }
 
 func (e *fileExporter) ConsumeTraces(_ context.Context, td ptrace.Traces) error {
-	buf, err := tracesMarshaler.MarshalTraces(td)
+	if e.pathTemplate == nil {
+		return e.exportTraces(e.path, td)
+	}
+	var errs error
+	for path, part := range splitTraces(td, e.pathTemplate) {
+		errs = multierr.Append(errs, e.exportTraces(path, part))
+	}
+	return errs
+}
+
+func (e *fileExporter) exportTraces(path string, td ptrace.Traces) error {
+	buf, err := e.tracesMarshaler.MarshalTraces(td)
 	if err != nil {
 		return err
 	}
-	return exportMessageAsLine(e, buf)
+	return e.export(path, buf)
 }
 
 func (e *fileExporter) ConsumeMetrics(_ context.Context, md pmetric.Metrics) error {
-	buf, err := metricsMarshaler.MarshalMetrics(md)
+	if e.pathTemplate == nil {
+		return e.exportMetrics(e.path, md)
+	}
+	var errs error
+	for path, part := range splitMetrics(md, e.pathTemplate) {
+		errs = multierr.Append(errs, e.exportMetrics(path, part))
+	}
+	return errs
+}
+
+func (e *fileExporter) exportMetrics(path string, md pmetric.Metrics) error {
+	buf, err := e.metricsMarshaler.MarshalMetrics(md)
 	if err != nil {
 		return err
 	}
-	return exportMessageAsLine(e, buf)
+	return e.export(path, buf)
 }
 
 func (e *fileExporter) ConsumeLogs(_ context.Context, ld plog.Logs) error {
-	buf, err := logsMarshaler.MarshalLogs(ld)
+	if e.pathTemplate == nil {
+		return e.exportLogs(e.path, ld)
+	}
+	var errs error
+	for path, part := range splitLogs(ld, e.pathTemplate) {
+		errs = multierr.Append(errs, e.exportLogs(path, part))
+	}
+	return errs
+}
+
+func (e *fileExporter) exportLogs(path string, ld plog.Logs) error {
+	buf, err := e.logsMarshaler.MarshalLogs(ld)
 	if err != nil {
 		return err
 	}
-	return exportMessageAsLine(e, buf)
+	return e.export(path, buf)
 }
 
-func exportMessageAsLine(e *fileExporter, buf []byte) error {
+// export writes the marshaled message to the file of the path.
+func (e *fileExporter) export(path string, buf []byte) error {
+	buf, err := e.compressor(buf)
+	if err != nil {
+		return err
+	}
+
 	// Ensure only one write operation happens at a time.
 	e.mutex.Lock()
 	defer e.mutex.Unlock()
-	if _, err := e.file.Write(buf); err != nil {
-		return err
+	if e.files == nil {
+		return e.exporter(e.file, buf)
 	}
-	if _, err := io.WriteString(e.file, "\n"); err != nil {
+	w, err := e.files.get(path)
+	if w == nil {
 		return err
 	}
-	return nil
+	return multierr.Append(err, e.exporter(w, buf))
+}
+
+func exportMessageAsLine(w io.Writer, buf []byte) error {
+	// The message and the new line are written at once so that
+	// the file is never rotated in between.
+	line := make([]byte, len(buf)+1)
+	copy(line, buf)
+	line[len(buf)] = '\n'
+	_, err := w.Write(line)
+	return err
+}
+
+func exportMessageAsBuffer(w io.Writer, buf []byte) error {
+	// Each message is preceded by its length as a 4 bytes big-endian
+	// unsigned integer, written at once with the message.
+	data := make([]byte, 4+len(buf))
+	binary.BigEndian.PutUint32(data, uint32(len(buf)))
+	copy(data[4:], buf)
+	_, err := w.Write(data)
+	return err
 }
 
 func (e *fileExporter) Start(context.Context, component.Host) error {
+	if e.files != nil {
+		if e.files.idleTimeout > 0 {
+			e.stopCh = make(chan struct{})
+			e.wg.Add(1)
+			go e.closeIdleFiles()
+		}
+		return nil
+	}
+	if e.rotation != nil {
+		e.file = newRotatingFile(e.path, e.rotation)
+		return nil
+	}
+	// The file is appended to, like the rotated and templated files, so that
+	// the data written before a restart is kept.
 	var err error
-	e.file, err = os.OpenFile(e.path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+	e.file, err = os.OpenFile(e.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
 	return err
 }
 
+// openTemplatedFile opens one of the files of a templated path. The files may be closed
+// and opened again while the exporter runs, they are therefore appended to.
+func (e *fileExporter) openTemplatedFile(path string) (io.WriteCloser, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if e.rotation != nil {
+		return newRotatingFile(path, e.rotation), nil
+	}
+	return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+}
+
+func (e *fileExporter) closeIdleFiles() {
+	defer e.wg.Done()
+	ticker := time.NewTicker(e.files.idleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			e.mutex.Lock()
+			// A file failing to close is closed anyway, the error is reported again
+			// by the next write, when the file is opened again.
+			_ = e.files.closeIdle()
+			e.mutex.Unlock()
+		case <-e.stopCh:
+			return
+		}
+	}
+}
+
 // Shutdown stops the exporter and is invoked during shutdown.
 func (e *fileExporter) Shutdown(context.Context) error {
-	return e.file.Close()
+	if e.files == nil {
+		return e.file.Close()
+	}
+	if e.stopCh != nil {
+		close(e.stopCh)
+		e.wg.Wait()
+	}
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.files.closeAll()
 }
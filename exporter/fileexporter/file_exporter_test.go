@@ -14,11 +14,19 @@
 package fileexporter
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
 	"context"
+	"encoding/binary"
 	"errors"
+	"io"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/klauspost/compress/zstd"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
@@ -30,7 +38,7 @@ import (
 )
 
 func TestFileTracesExporter(t *testing.T) {
-	fe := &fileExporter{path: tempFileName(t)}
+	fe := newFileExporter(&Config{Path: tempFileName(t)})
 	require.NotNil(t, fe)
 
 	td := testdata.GenerateTracesTwoSpansSameResource()
@@ -46,10 +54,35 @@ func TestFileTracesExporter(t *testing.T) {
 	assert.EqualValues(t, td, got)
 }
 
+func TestFileExporterAppendsAfterRestart(t *testing.T) {
+	path := tempFileName(t)
+	td := testdata.GenerateTracesTwoSpansSameResource()
+	for i := 0; i < 2; i++ {
+		fe := newFileExporter(&Config{Path: path})
+		require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+		require.NoError(t, fe.ConsumeTraces(context.Background(), td))
+		require.NoError(t, fe.Shutdown(context.Background()))
+	}
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	lines := 0
+	for scanner.Scan() {
+		got, err := ptrace.NewJSONUnmarshaler().UnmarshalTraces(scanner.Bytes())
+		require.NoError(t, err)
+		assert.EqualValues(t, td, got)
+		lines++
+	}
+	assert.Equal(t, 2, lines, "Must keep the data written before the restart")
+}
+
 func TestFileTracesExporterError(t *testing.T) {
 	mf := &errorWriter{}
-	fe := &fileExporter{file: mf}
+	fe := newFileExporter(&Config{})
 	require.NotNil(t, fe)
+	fe.file = mf
 
 	td := testdata.GenerateTracesTwoSpansSameResource()
 	// Cannot call Start since we inject directly the WriterCloser.
@@ -58,7 +91,7 @@ func TestFileTracesExporterError(t *testing.T) {
 }
 
 func TestFileMetricsExporter(t *testing.T) {
-	fe := &fileExporter{path: tempFileName(t)}
+	fe := newFileExporter(&Config{Path: tempFileName(t)})
 	require.NotNil(t, fe)
 
 	md := testdata.GenerateMetricsTwoMetrics()
@@ -76,8 +109,9 @@ func TestFileMetricsExporter(t *testing.T) {
 
 func TestFileMetricsExporterError(t *testing.T) {
 	mf := &errorWriter{}
-	fe := &fileExporter{file: mf}
+	fe := newFileExporter(&Config{})
 	require.NotNil(t, fe)
+	fe.file = mf
 
 	md := testdata.GenerateMetricsTwoMetrics()
 	// Cannot call Start since we inject directly the WriterCloser.
@@ -86,7 +120,7 @@ func TestFileMetricsExporterError(t *testing.T) {
 }
 
 func TestFileLogsExporter(t *testing.T) {
-	fe := &fileExporter{path: tempFileName(t)}
+	fe := newFileExporter(&Config{Path: tempFileName(t)})
 	require.NotNil(t, fe)
 
 	ld := testdata.GenerateLogsTwoLogRecordsSameResource()
@@ -104,8 +138,9 @@ func TestFileLogsExporter(t *testing.T) {
 
 func TestFileLogsExporterErrors(t *testing.T) {
 	mf := &errorWriter{}
-	fe := &fileExporter{file: mf}
+	fe := newFileExporter(&Config{})
 	require.NotNil(t, fe)
+	fe.file = mf
 
 	ld := testdata.GenerateLogsTwoLogRecordsSameResource()
 	// Cannot call Start since we inject directly the WriterCloser.
@@ -113,6 +148,154 @@ func TestFileLogsExporterErrors(t *testing.T) {
 	assert.NoError(t, fe.Shutdown(context.Background()))
 }
 
+func TestFileExporterFormatsAndCompression(t *testing.T) {
+	decompressors := map[string]func(t *testing.T, buf []byte) []byte{
+		"": func(_ *testing.T, buf []byte) []byte {
+			return buf
+		},
+		compressionGZIP: func(t *testing.T, buf []byte) []byte {
+			r, err := gzip.NewReader(bytes.NewReader(buf))
+			require.NoError(t, err)
+			defer r.Close()
+			decompressed, err := io.ReadAll(r)
+			require.NoError(t, err)
+			return decompressed
+		},
+		compressionZSTD: func(t *testing.T, buf []byte) []byte {
+			r, err := zstd.NewReader(nil)
+			require.NoError(t, err)
+			defer r.Close()
+			decompressed, err := r.DecodeAll(buf, nil)
+			require.NoError(t, err)
+			return decompressed
+		},
+	}
+	unmarshalers := map[string]ptrace.Unmarshaler{
+		formatTypeJSON:  ptrace.NewJSONUnmarshaler(),
+		formatTypeProto: ptrace.NewProtoUnmarshaler(),
+	}
+
+	for _, formatType := range []string{formatTypeJSON, formatTypeProto} {
+		for _, compression := range []string{"", compressionGZIP, compressionZSTD} {
+			t.Run(formatType+"_"+compression, func(t *testing.T) {
+				fe := newFileExporter(&Config{Path: tempFileName(t), FormatType: formatType, Compression: compression})
+				td := testdata.GenerateTracesTwoSpansSameResource()
+				assert.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+				assert.NoError(t, fe.ConsumeTraces(context.Background(), td))
+				assert.NoError(t, fe.ConsumeTraces(context.Background(), td))
+				assert.NoError(t, fe.Shutdown(context.Background()))
+
+				var messages [][]byte
+				if formatType == formatTypeJSON && compression == "" {
+					messages = readLines(t, fe.path)
+				} else {
+					messages = readLengthDelimited(t, fe.path)
+				}
+				require.Len(t, messages, 2)
+				for _, msg := range messages {
+					got, err := unmarshalers[formatType].UnmarshalTraces(decompressors[compression](t, msg))
+					assert.NoError(t, err)
+					assert.EqualValues(t, td, got)
+				}
+			})
+		}
+	}
+}
+
+func TestFileExporterRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rotated.json")
+	fe := newFileExporter(&Config{
+		Path:     path,
+		Rotation: &Rotation{MaxMegabytes: 1, MaxAge: time.Hour, MaxBackups: 2},
+	})
+	require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+	rf := fe.file.(*rotatingFile)
+	now := time.Now()
+	rf.now = func() time.Time { return now }
+
+	ld := testdata.GenerateLogsTwoLogRecordsSameResource()
+	assert.NoError(t, fe.ConsumeLogs(context.Background(), ld))
+	// The file is rotated once it has been written to for longer than max_age,
+	// the rotated files are named after the time of the rotation, which must differ.
+	for i := 0; i < 3; i++ {
+		now = now.Add(time.Hour)
+		time.Sleep(2 * time.Millisecond)
+		assert.NoError(t, fe.ConsumeLogs(context.Background(), ld))
+	}
+	assert.NoError(t, fe.Shutdown(context.Background()))
+
+	// The current file and at most max_backups rotated files are kept,
+	// the older rotated files are removed in the background.
+	assert.Eventually(t, func() bool {
+		entries, err := os.ReadDir(filepath.Dir(path))
+		return err == nil && len(entries) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Len(t, readLines(t, path), 1)
+}
+
+func TestFileExporterPathTemplate(t *testing.T) {
+	dir := t.TempDir()
+	fe := newFileExporter(&Config{
+		Path:         filepath.Join(dir, `{{resource.attributes["service.name"]}}`, "logs.json"),
+		MaxOpenFiles: 1,
+		IdleTimeout:  time.Minute,
+	})
+	require.NoError(t, fe.Start(context.Background(), componenttest.NewNopHost()))
+
+	ld := plog.NewLogs()
+	for _, service := range []string{"checkout", "cart", "checkout"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().UpsertString("service.name", service)
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStringVal(service)
+	}
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStringVal("none")
+
+	// Only one file is kept open, the files are reopened and appended to.
+	assert.NoError(t, fe.ConsumeLogs(context.Background(), ld))
+	assert.NoError(t, fe.ConsumeLogs(context.Background(), ld))
+	assert.NoError(t, fe.Shutdown(context.Background()))
+
+	unmarshaler := plog.NewJSONUnmarshaler()
+	for service, records := range map[string]int{"checkout": 2, "cart": 1, "unknown": 1} {
+		lines := readLines(t, filepath.Join(dir, service, "logs.json"))
+		require.Len(t, lines, 2, service)
+		for _, line := range lines {
+			got, err := unmarshaler.UnmarshalLogs(line)
+			require.NoError(t, err)
+			assert.Equal(t, records, got.LogRecordCount(), service)
+		}
+	}
+}
+
+func readLines(t *testing.T, path string) [][]byte {
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	var lines [][]byte
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, append([]byte(nil), scanner.Bytes()...))
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func readLengthDelimited(t *testing.T, path string) [][]byte {
+	buf, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var messages [][]byte
+	for len(buf) > 0 {
+		require.GreaterOrEqual(t, len(buf), 4)
+		size := binary.BigEndian.Uint32(buf)
+		buf = buf[4:]
+		require.GreaterOrEqual(t, len(buf), int(size))
+		messages = append(messages, buf[:size])
+		buf = buf[size:]
+	}
+	return messages
+}
+
 // tempFileName provides a temporary file name for testing.
 func tempFileName(t *testing.T) string {
 	tmpfile, err := os.CreateTemp("", "*.json")
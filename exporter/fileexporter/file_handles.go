@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
+
+import (
+	"container/list"
+	"io"
+	"time"
+
+	"go.uber.org/multierr"
+)
+
+// fileHandles keeps the files of a templated path open, closing the least
+// recently used file when too many are open and the files that stay idle.
+// It is not safe for concurrent use.
+type fileHandles struct {
+	open        func(path string) (io.WriteCloser, error)
+	maxOpen     int
+	idleTimeout time.Duration
+	now         func() time.Time
+
+	// lru holds the *fileHandle of the open files, the most recently used first.
+	lru     *list.List
+	handles map[string]*list.Element
+}
+
+type fileHandle struct {
+	path     string
+	file     io.WriteCloser
+	lastUsed time.Time
+}
+
+func newFileHandles(open func(path string) (io.WriteCloser, error), maxOpen int, idleTimeout time.Duration) *fileHandles {
+	return &fileHandles{
+		open:        open,
+		maxOpen:     maxOpen,
+		idleTimeout: idleTimeout,
+		now:         time.Now,
+		lru:         list.New(),
+		handles:     make(map[string]*list.Element),
+	}
+}
+
+// get returns the open file of the path, opening it if needed.
+func (fh *fileHandles) get(path string) (io.Writer, error) {
+	if elem, ok := fh.handles[path]; ok {
+		handle := elem.Value.(*fileHandle)
+		handle.lastUsed = fh.now()
+		fh.lru.MoveToFront(elem)
+		return handle.file, nil
+	}
+
+	var errs error
+	for fh.lru.Len() >= fh.maxOpen {
+		errs = multierr.Append(errs, fh.close(fh.lru.Back()))
+	}
+	file, err := fh.open(path)
+	if err != nil {
+		return nil, multierr.Append(errs, err)
+	}
+	fh.handles[path] = fh.lru.PushFront(&fileHandle{path: path, file: file, lastUsed: fh.now()})
+	return file, errs
+}
+
+// closeIdle closes the files that have not been used for longer than the idle timeout.
+func (fh *fileHandles) closeIdle() error {
+	var errs error
+	for elem := fh.lru.Back(); elem != nil; elem = fh.lru.Back() {
+		if fh.now().Sub(elem.Value.(*fileHandle).lastUsed) < fh.idleTimeout {
+			break
+		}
+		errs = multierr.Append(errs, fh.close(elem))
+	}
+	return errs
+}
+
+// closeAll closes all the open files.
+func (fh *fileHandles) closeAll() error {
+	var errs error
+	for elem := fh.lru.Back(); elem != nil; elem = fh.lru.Back() {
+		errs = multierr.Append(errs, fh.close(elem))
+	}
+	return errs
+}
+
+func (fh *fileHandles) close(elem *list.Element) error {
+	handle := fh.lru.Remove(elem).(*fileHandle)
+	delete(fh.handles, handle.path)
+	return handle.file.Close()
+}
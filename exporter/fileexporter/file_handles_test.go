@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nopFile struct {
+	closed bool
+}
+
+func (f *nopFile) Write(p []byte) (int, error) {
+	return len(p), nil
+}
+
+func (f *nopFile) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestFileHandles(t *testing.T) {
+	files := make(map[string][]*nopFile)
+	open := func(path string) (io.WriteCloser, error) {
+		f := &nopFile{}
+		files[path] = append(files[path], f)
+		return f, nil
+	}
+	fh := newFileHandles(open, 2, time.Minute)
+	now := time.Now()
+	fh.now = func() time.Time { return now }
+
+	for _, path := range []string{"a", "b", "a"} {
+		_, err := fh.get(path)
+		require.NoError(t, err)
+	}
+	assert.Len(t, files["a"], 1, "an open file must be reused")
+
+	// "b" is the least recently used file.
+	now = now.Add(30 * time.Second)
+	_, err := fh.get("c")
+	require.NoError(t, err)
+	assert.True(t, files["b"][0].closed)
+	assert.False(t, files["a"][0].closed)
+	assert.Equal(t, 2, fh.lru.Len())
+
+	// "a" has been idle for longer than the timeout, "c" has not.
+	now = now.Add(45 * time.Second)
+	require.NoError(t, fh.closeIdle())
+	assert.True(t, files["a"][0].closed)
+	assert.False(t, files["c"][0].closed)
+
+	_, err = fh.get("a")
+	require.NoError(t, err)
+	assert.Len(t, files["a"], 2, "a closed file must be opened again")
+
+	require.NoError(t, fh.closeAll())
+	assert.True(t, files["a"][1].closed)
+	assert.True(t, files["c"][0].closed)
+	assert.Equal(t, 0, fh.lru.Len())
+	assert.Empty(t, fh.handles)
+}
@@ -3,11 +3,14 @@ module github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileex
 go 1.18
 
 require (
+	github.com/klauspost/compress v1.15.9
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal v0.59.0
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent v0.59.0
 	github.com/stretchr/testify v1.8.0
 	go.opentelemetry.io/collector v0.59.1-0.20220913184032-98c787a2ab06
 	go.opentelemetry.io/collector/pdata v0.59.1-0.20220913184032-98c787a2ab06
+	go.uber.org/multierr v1.8.0
+	gopkg.in/natefinch/lumberjack.v2 v2.0.0
 )
 
 require (
@@ -29,7 +32,6 @@ require (
 	go.opentelemetry.io/otel/metric v0.31.0 // indirect
 	go.opentelemetry.io/otel/trace v1.9.0 // indirect
 	go.uber.org/atomic v1.10.0 // indirect
-	go.uber.org/multierr v1.8.0 // indirect
 	go.uber.org/zap v1.23.0 // indirect
 	golang.org/x/net v0.0.0-20220225172249-27dd8689420f // indirect
 	golang.org/x/sys v0.0.0-20220808155132-1c4a2a72c664 // indirect
@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// unknownPathValue replaces the resource attributes missing from a resource in the path.
+const unknownPathValue = "unknown"
+
+var (
+	placeholderRegexp     = regexp.MustCompile(`\{\{[^}]*\}\}`)
+	resourceAttributeExpr = regexp.MustCompile(`^\{\{\s*resource\.attributes\["([^"]+)"\]\s*\}\}$`)
+)
+
+// pathTemplate is a path containing resource attributes placeholders, e.g.:
+// /var/otel/{{resource.attributes["service.name"]}}/traces.json.
+type pathTemplate struct {
+	// literals surround the keys, there is one more literal than there are keys.
+	literals []string
+	keys     []string
+}
+
+// parsePathTemplate parses the placeholders of the path, nil is returned
+// when the path does not contain any placeholder.
+func parsePathTemplate(path string) (*pathTemplate, error) {
+	indexes := placeholderRegexp.FindAllStringIndex(path, -1)
+	if len(indexes) == 0 {
+		return nil, nil
+	}
+	pt := &pathTemplate{}
+	start := 0
+	for _, index := range indexes {
+		placeholder := path[index[0]:index[1]]
+		match := resourceAttributeExpr.FindStringSubmatch(placeholder)
+		if match == nil {
+			return nil, fmt.Errorf("unsupported placeholder %q in path, only resource attributes are supported, "+
+				`e.g.: {{resource.attributes["service.name"]}}`, placeholder)
+		}
+		pt.literals = append(pt.literals, path[start:index[0]])
+		pt.keys = append(pt.keys, match[1])
+		start = index[1]
+	}
+	pt.literals = append(pt.literals, path[start:])
+	return pt, nil
+}
+
+// render returns the path of the resource.
+func (pt *pathTemplate) render(resource pcommon.Resource) string {
+	var sb strings.Builder
+	for i, key := range pt.keys {
+		sb.WriteString(pt.literals[i])
+		value := unknownPathValue
+		if v, ok := resource.Attributes().Get(key); ok {
+			value = sanitizePathValue(v.AsString())
+		}
+		sb.WriteString(value)
+	}
+	sb.WriteString(pt.literals[len(pt.literals)-1])
+	return sb.String()
+}
+
+// sanitizePathValue prevents the attribute values from changing the
+// directory of the files, or from referring to a parent directory.
+func sanitizePathValue(value string) string {
+	value = strings.NewReplacer("/", "_", `\`, "_").Replace(value)
+	switch value {
+	case "":
+		return unknownPathValue
+	case ".", "..":
+		return "_"
+	}
+	return value
+}
@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestParsePathTemplate(t *testing.T) {
+	pt, err := parsePathTemplate("./filename.json")
+	assert.NoError(t, err)
+	assert.Nil(t, pt)
+
+	pt, err = parsePathTemplate(`/var/otel/{{resource.attributes["tenant.id"]}}/{{ resource.attributes["service.name"] }}.json`)
+	require.NoError(t, err)
+	assert.Equal(t, &pathTemplate{
+		literals: []string{"/var/otel/", "/", ".json"},
+		keys:     []string{"tenant.id", "service.name"},
+	}, pt)
+
+	_, err = parsePathTemplate(`/var/otel/{{resource.name}}.json`)
+	assert.Error(t, err)
+}
+
+func TestPathTemplateRender(t *testing.T) {
+	pt, err := parsePathTemplate(`/var/otel/{{resource.attributes["tenant.id"]}}/{{resource.attributes["service.name"]}}.json`)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		attrs    map[string]interface{}
+		expected string
+	}{
+		{
+			name:     "all attributes",
+			attrs:    map[string]interface{}{"tenant.id": "acme", "service.name": "checkout"},
+			expected: "/var/otel/acme/checkout.json",
+		},
+		{
+			name:     "missing attribute",
+			attrs:    map[string]interface{}{"service.name": "checkout"},
+			expected: "/var/otel/unknown/checkout.json",
+		},
+		{
+			name:     "non string attribute",
+			attrs:    map[string]interface{}{"tenant.id": 42, "service.name": ""},
+			expected: "/var/otel/42/unknown.json",
+		},
+		{
+			name:     "path separators and parent directory",
+			attrs:    map[string]interface{}{"tenant.id": "..", "service.name": "../../etc/passwd"},
+			expected: "/var/otel/_/.._.._etc_passwd.json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := pcommon.NewResource()
+			resource.Attributes().FromRaw(tt.attrs)
+			assert.Equal(t, tt.expected, pt.render(resource))
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
+
+import (
+	"io"
+	"time"
+
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// rotatingFile writes to a file rotated by lumberjack once it reaches its maximum size,
+// and rotated by itself once it has been written to for longer than its maximum age.
+type rotatingFile struct {
+	*lumberjack.Logger
+	maxAge    time.Duration
+	now       func() time.Time
+	rotatedAt time.Time
+}
+
+var _ io.WriteCloser = (*rotatingFile)(nil)
+
+func newRotatingFile(path string, rotation *Rotation) *rotatingFile {
+	return &rotatingFile{
+		Logger: &lumberjack.Logger{
+			Filename:   path,
+			MaxSize:    rotation.MaxMegabytes,
+			MaxBackups: rotation.MaxBackups,
+			LocalTime:  rotation.LocalTime,
+		},
+		maxAge:    rotation.MaxAge,
+		now:       time.Now,
+		rotatedAt: time.Now(),
+	}
+}
+
+func (rf *rotatingFile) Write(p []byte) (int, error) {
+	if rf.maxAge > 0 && rf.now().Sub(rf.rotatedAt) >= rf.maxAge {
+		if err := rf.Rotate(); err != nil {
+			return 0, err
+		}
+		rf.rotatedAt = rf.now()
+	}
+	return rf.Logger.Write(p)
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/fileexporter"
+
+import (
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// splitTraces groups the resource spans by the path their resource renders to.
+func splitTraces(td ptrace.Traces, pt *pathTemplate) map[string]ptrace.Traces {
+	parts := make(map[string]ptrace.Traces)
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		path := pt.render(rs.Resource())
+		part, ok := parts[path]
+		if !ok {
+			part = ptrace.NewTraces()
+			parts[path] = part
+		}
+		rs.CopyTo(part.ResourceSpans().AppendEmpty())
+	}
+	return parts
+}
+
+// splitMetrics groups the resource metrics by the path their resource renders to.
+func splitMetrics(md pmetric.Metrics, pt *pathTemplate) map[string]pmetric.Metrics {
+	parts := make(map[string]pmetric.Metrics)
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		path := pt.render(rm.Resource())
+		part, ok := parts[path]
+		if !ok {
+			part = pmetric.NewMetrics()
+			parts[path] = part
+		}
+		rm.CopyTo(part.ResourceMetrics().AppendEmpty())
+	}
+	return parts
+}
+
+// splitLogs groups the resource logs by the path their resource renders to.
+func splitLogs(ld plog.Logs, pt *pathTemplate) map[string]plog.Logs {
+	parts := make(map[string]plog.Logs)
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		path := pt.render(rl.Resource())
+		part, ok := parts[path]
+		if !ok {
+			part = plog.NewLogs()
+			parts[path] = part
+		}
+		rl.CopyTo(part.ResourceLogs().AppendEmpty())
+	}
+	return parts
+}
@@ -37,6 +37,18 @@ type Config struct {
 	// The name of the kafka topic to export to (default otlp_spans for traces, otlp_metrics for metrics)
 	Topic string `mapstructure:"topic"`
 
+	// TopicFromAttribute is the resource attribute whose value is the topic the data of the resource
+	// is exported to. Topic is used when the attribute is missing.
+	TopicFromAttribute string `mapstructure:"topic_from_attribute"`
+
+	// PartitionTracesByID sets the trace ID as the key of the messages, so that the spans of a trace
+	// are sent to the same partition. The traces are split so that each message contains a single trace.
+	PartitionTracesByID bool `mapstructure:"partition_traces_by_id"`
+
+	// PartitionByResourceAttribute is the resource attribute whose value is the key of the messages,
+	// so that the data of the resources with the same value are sent to the same partition.
+	PartitionByResourceAttribute string `mapstructure:"partition_by_resource_attribute"`
+
 	// Encoding of messages (default "otlp_proto")
 	Encoding string `mapstructure:"encoding"`
 
@@ -112,6 +124,10 @@ func (cfg *Config) Validate() error {
 		return err
 	}
 
+	if cfg.PartitionTracesByID && cfg.PartitionByResourceAttribute != "" {
+		return fmt.Errorf("partition_traces_by_id and partition_by_resource_attribute cannot be both set")
+	}
+
 	return nil
 }
 
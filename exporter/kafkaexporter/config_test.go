@@ -56,9 +56,11 @@ func TestLoadConfig(t *testing.T) {
 			NumConsumers: 2,
 			QueueSize:    10,
 		},
-		Topic:    "spans",
-		Encoding: "otlp_proto",
-		Brokers:  []string{"foo:123", "bar:456"},
+		Topic:               "spans",
+		TopicFromAttribute:  "tenant",
+		PartitionTracesByID: true,
+		Encoding:            "otlp_proto",
+		Brokers:             []string{"foo:123", "bar:456"},
 		Authentication: Authentication{
 			PlainText: &PlainTextConfig{
 				Username: "jdoe",
@@ -92,6 +94,19 @@ func TestValidate_err_compression(t *testing.T) {
 	assert.Equal(t, err.Error(), "producer.compression should be one of 'none', 'gzip', 'snappy', 'lz4', or 'zstd'. configured value idk")
 }
 
+func TestValidate_err_partition(t *testing.T) {
+	config := &Config{
+		Producer: Producer{
+			Compression: "none",
+		},
+		PartitionTracesByID:          true,
+		PartitionByResourceAttribute: "service.name",
+	}
+
+	err := config.Validate()
+	assert.EqualError(t, err, "partition_traces_by_id and partition_by_resource_attribute cannot be both set")
+}
+
 func Test_saramaProducerCompressionCodec(t *testing.T) {
 	tests := map[string]struct {
 		compression         string
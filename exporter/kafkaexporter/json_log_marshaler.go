@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// jsonLogsMarshaler writes each log record as a separate JSON object, whose attributes and
+// resource attributes are flattened to dotted keys.
+type jsonLogsMarshaler struct{}
+
+var _ LogsMarshaler = (*jsonLogsMarshaler)(nil)
+
+// jsonLogRecord is the JSON representation of a log record.
+type jsonLogRecord struct {
+	Timestamp          string                 `json:"timestamp,omitempty"`
+	ObservedTimestamp  string                 `json:"observed_timestamp,omitempty"`
+	SeverityText       string                 `json:"severity_text,omitempty"`
+	SeverityNumber     int32                  `json:"severity_number,omitempty"`
+	Body               interface{}            `json:"body,omitempty"`
+	TraceID            string                 `json:"trace_id,omitempty"`
+	SpanID             string                 `json:"span_id,omitempty"`
+	Attributes         map[string]interface{} `json:"attributes,omitempty"`
+	ResourceAttributes map[string]interface{} `json:"resource,omitempty"`
+	Scope              string                 `json:"scope,omitempty"`
+}
+
+func (j jsonLogsMarshaler) Marshal(logs plog.Logs, topic string) ([]*sarama.ProducerMessage, error) {
+	var messages []*sarama.ProducerMessage
+	rls := logs.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		resourceAttributes := flattenAttributes(rls.At(i).Resource().Attributes())
+		sls := rls.At(i).ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			scope := sls.At(j).Scope().Name()
+			records := sls.At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				record := records.At(k)
+				bts, err := json.Marshal(jsonLogRecord{
+					Timestamp:          formatTimestamp(record.Timestamp()),
+					ObservedTimestamp:  formatTimestamp(record.ObservedTimestamp()),
+					SeverityText:       record.SeverityText(),
+					SeverityNumber:     int32(record.SeverityNumber()),
+					Body:               valueAsRaw(record.Body()),
+					TraceID:            record.TraceID().HexString(),
+					SpanID:             record.SpanID().HexString(),
+					Attributes:         flattenAttributes(record.Attributes()),
+					ResourceAttributes: resourceAttributes,
+					Scope:              scope,
+				})
+				if err != nil {
+					return nil, err
+				}
+				messages = append(messages, &sarama.ProducerMessage{
+					Topic: topic,
+					Value: sarama.ByteEncoder(bts),
+				})
+			}
+		}
+	}
+	return messages, nil
+}
+
+func (j jsonLogsMarshaler) Encoding() string {
+	return "json"
+}
+
+func formatTimestamp(ts pcommon.Timestamp) string {
+	if ts == 0 {
+		return ""
+	}
+	return ts.AsTime().UTC().Format(time.RFC3339Nano)
+}
+
+// flattenAttributes returns the attributes as a map whose nested maps are flattened,
+// e.g. {"http": {"method": "GET"}} becomes {"http.method": "GET"}.
+func flattenAttributes(attributes pcommon.Map) map[string]interface{} {
+	if attributes.Len() == 0 {
+		return nil
+	}
+	out := make(map[string]interface{}, attributes.Len())
+	flattenInto(out, "", attributes)
+	return out
+}
+
+func flattenInto(out map[string]interface{}, prefix string, attributes pcommon.Map) {
+	attributes.Range(func(k string, v pcommon.Value) bool {
+		if v.Type() == pcommon.ValueTypeMap {
+			flattenInto(out, prefix+k+".", v.MapVal())
+		} else {
+			out[prefix+k] = valueAsRaw(v)
+		}
+		return true
+	})
+}
+
+// valueAsRaw returns the value as the Go type it is encoded from in JSON.
+func valueAsRaw(v pcommon.Value) interface{} {
+	switch v.Type() {
+	case pcommon.ValueTypeString:
+		return v.StringVal()
+	case pcommon.ValueTypeBool:
+		return v.BoolVal()
+	case pcommon.ValueTypeInt:
+		return v.IntVal()
+	case pcommon.ValueTypeDouble:
+		return v.DoubleVal()
+	case pcommon.ValueTypeBytes:
+		return v.BytesVal().AsRaw()
+	case pcommon.ValueTypeMap:
+		return v.MapVal().AsRaw()
+	case pcommon.ValueTypeSlice:
+		return v.SliceVal().AsRaw()
+	}
+	return nil
+}
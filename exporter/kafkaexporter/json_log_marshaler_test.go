@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestJSONLogsMarshaler(t *testing.T) {
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().UpsertString("service.name", "checkout")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("test-scope")
+	record := sl.LogRecords().AppendEmpty()
+	record.SetTimestamp(pcommon.NewTimestampFromTime(time.Date(2022, 9, 1, 10, 0, 0, 0, time.UTC)))
+	record.SetSeverityText("ERROR")
+	record.SetSeverityNumber(plog.SeverityNumberError)
+	record.SetTraceID(pcommon.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	record.SetSpanID(pcommon.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	record.Body().SetStringVal("payment failed")
+	record.Attributes().UpsertString("user.id", "42")
+	http := record.Attributes().UpsertEmptyMap("http")
+	http.UpsertString("method", "POST")
+	http.UpsertInt("status_code", 500)
+	sl.LogRecords().AppendEmpty().Body().SetStringVal("minimal")
+
+	messages, err := jsonLogsMarshaler{}.Marshal(logs, "topic")
+	require.NoError(t, err)
+	require.Len(t, messages, 2)
+
+	bts, err := messages[0].Value.Encode()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"timestamp": "2022-09-01T10:00:00Z",
+		"severity_text": "ERROR",
+		"severity_number": 17,
+		"body": "payment failed",
+		"trace_id": "0102030405060708090a0b0c0d0e0f10",
+		"span_id": "0102030405060708",
+		"attributes": {"user.id": "42", "http.method": "POST", "http.status_code": 500},
+		"resource": {"service.name": "checkout"},
+		"scope": "test-scope"
+	}`, string(bts))
+
+	bts, err = messages[1].Value.Encode()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"body": "minimal",
+		"resource": {"service.name": "checkout"},
+		"scope": "test-scope"
+	}`, string(bts))
+}
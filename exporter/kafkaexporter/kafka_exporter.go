@@ -25,6 +25,7 @@ import (
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/multierr"
 	"go.uber.org/zap"
 )
 
@@ -33,7 +34,7 @@ var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
 // kafkaTracesProducer uses sarama to produce trace messages to Kafka.
 type kafkaTracesProducer struct {
 	producer  sarama.SyncProducer
-	topic     string
+	router    router
 	marshaler TracesMarshaler
 	logger    *zap.Logger
 }
@@ -48,11 +49,16 @@ func (ke kafkaErrors) Error() string {
 }
 
 func (e *kafkaTracesProducer) tracesPusher(_ context.Context, td ptrace.Traces) error {
-	messages, err := e.marshaler.Marshal(td, e.topic)
-	if err != nil {
-		return consumererror.NewPermanent(err)
+	var messages []*sarama.ProducerMessage
+	for route, part := range e.router.splitTraces(td) {
+		partMessages, err := e.marshaler.Marshal(part, route.topic)
+		if err != nil {
+			return consumererror.NewPermanent(err)
+		}
+		setMessagesKey(partMessages, route.key)
+		messages = append(messages, partMessages...)
 	}
-	err = e.producer.SendMessages(messages)
+	err := e.producer.SendMessages(messages)
 	if err != nil {
 		var prodErr sarama.ProducerErrors
 		if errors.As(err, &prodErr) {
@@ -72,27 +78,40 @@ func (e *kafkaTracesProducer) Close(context.Context) error {
 // kafkaMetricsProducer uses sarama to produce metrics messages to kafka
 type kafkaMetricsProducer struct {
 	producer  sarama.SyncProducer
-	topic     string
+	router    router
 	marshaler MetricsMarshaler
 	logger    *zap.Logger
 }
 
 func (e *kafkaMetricsProducer) metricsDataPusher(_ context.Context, md pmetric.Metrics) error {
-	messages, err := e.marshaler.Marshal(md, e.topic)
-	if err != nil {
-		return consumererror.NewPermanent(err)
+	var messages []*sarama.ProducerMessage
+	var marshalErr error
+	for route, part := range e.router.splitMetrics(md) {
+		// A marshaler may return the messages of the metrics it could serialize
+		// alongside an error, these messages are sent anyway.
+		partMessages, err := e.marshaler.Marshal(part, route.topic)
+		if err != nil {
+			marshalErr = multierr.Append(marshalErr, err)
+		}
+		setMessagesKey(partMessages, route.key)
+		messages = append(messages, partMessages...)
+	}
+	if marshalErr != nil {
+		marshalErr = consumererror.NewPermanent(marshalErr)
+		if len(messages) == 0 {
+			return marshalErr
+		}
 	}
-	err = e.producer.SendMessages(messages)
+	err := e.producer.SendMessages(messages)
 	if err != nil {
 		var prodErr sarama.ProducerErrors
 		if errors.As(err, &prodErr) {
 			if len(prodErr) > 0 {
-				return kafkaErrors{len(prodErr), prodErr[0].Err.Error()}
+				err = kafkaErrors{len(prodErr), prodErr[0].Err.Error()}
 			}
 		}
-		return err
 	}
-	return nil
+	return multierr.Combine(marshalErr, err)
 }
 
 func (e *kafkaMetricsProducer) Close(context.Context) error {
@@ -102,17 +121,22 @@ func (e *kafkaMetricsProducer) Close(context.Context) error {
 // kafkaLogsProducer uses sarama to produce logs messages to kafka
 type kafkaLogsProducer struct {
 	producer  sarama.SyncProducer
-	topic     string
+	router    router
 	marshaler LogsMarshaler
 	logger    *zap.Logger
 }
 
 func (e *kafkaLogsProducer) logsDataPusher(_ context.Context, ld plog.Logs) error {
-	messages, err := e.marshaler.Marshal(ld, e.topic)
-	if err != nil {
-		return consumererror.NewPermanent(err)
+	var messages []*sarama.ProducerMessage
+	for route, part := range e.router.splitLogs(ld) {
+		partMessages, err := e.marshaler.Marshal(part, route.topic)
+		if err != nil {
+			return consumererror.NewPermanent(err)
+		}
+		setMessagesKey(partMessages, route.key)
+		messages = append(messages, partMessages...)
 	}
-	err = e.producer.SendMessages(messages)
+	err := e.producer.SendMessages(messages)
 	if err != nil {
 		var prodErr sarama.ProducerErrors
 		if errors.As(err, &prodErr) {
@@ -180,7 +204,7 @@ func newMetricsExporter(config Config, set component.ExporterCreateSettings, mar
 
 	return &kafkaMetricsProducer{
 		producer:  producer,
-		topic:     config.Topic,
+		router:    newRouter(config),
 		marshaler: marshaler,
 		logger:    set.Logger,
 	}, nil
@@ -199,7 +223,7 @@ func newTracesExporter(config Config, set component.ExporterCreateSettings, mars
 	}
 	return &kafkaTracesProducer{
 		producer:  producer,
-		topic:     config.Topic,
+		router:    newRouter(config),
 		marshaler: marshaler,
 		logger:    set.Logger,
 	}, nil
@@ -217,7 +241,7 @@ func newLogsExporter(config Config, set component.ExporterCreateSettings, marsha
 
 	return &kafkaLogsProducer{
 		producer:  producer,
-		topic:     config.Topic,
+		router:    newRouter(config),
 		marshaler: marshaler,
 		logger:    set.Logger,
 	}, nil
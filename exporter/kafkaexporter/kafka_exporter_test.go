@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/consumer/consumererror"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -151,6 +152,36 @@ func TestTracesPusher(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestTracesPusher_partitionByID(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	var keys []string
+	checkKey := func(msg *sarama.ProducerMessage) error {
+		key, err := msg.Key.Encode()
+		keys = append(keys, string(key))
+		assert.Equal(t, "spans", msg.Topic)
+		return err
+	}
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(checkKey)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(checkKey)
+
+	p := kafkaTracesProducer{
+		producer:  producer,
+		router:    newRouter(Config{Topic: "spans", PartitionTracesByID: true}),
+		marshaler: newPdataTracesMarshaler(ptrace.NewProtoMarshaler(), defaultEncoding),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	spans.AppendEmpty().SetTraceID(testTraceID1)
+	spans.AppendEmpty().SetTraceID(testTraceID2)
+	err := p.tracesPusher(context.Background(), td)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{testTraceID1.HexString(), testTraceID2.HexString()}, keys)
+}
+
 func TestTracesPusher_err(t *testing.T) {
 	c := sarama.NewConfig()
 	producer := mocks.NewSyncProducer(t, c)
@@ -229,6 +260,30 @@ func TestMetricsDataPusher_marshal_error(t *testing.T) {
 	assert.Contains(t, err.Error(), expErr.Error())
 }
 
+func TestMetricsDataPusher_partial_marshal_error(t *testing.T) {
+	c := sarama.NewConfig()
+	producer := mocks.NewSyncProducer(t, c)
+	producer.ExpectSendMessageAndSucceed()
+
+	expErr := fmt.Errorf("failed to marshal some metrics")
+	p := kafkaMetricsProducer{
+		producer: producer,
+		marshaler: &metricsErrorMarshaler{
+			err:      expErr,
+			messages: []*sarama.ProducerMessage{{Topic: "topic", Value: sarama.ByteEncoder("partial")}},
+		},
+		logger: zap.NewNop(),
+	}
+	t.Cleanup(func() {
+		require.NoError(t, p.Close(context.Background()))
+	})
+	md := testdata.GenerateMetricsTwoMetrics()
+	err := p.metricsDataPusher(context.Background(), md)
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPermanent(err))
+	assert.Contains(t, err.Error(), expErr.Error())
+}
+
 func TestLogsDataPusher(t *testing.T) {
 	c := sarama.NewConfig()
 	producer := mocks.NewSyncProducer(t, c)
@@ -281,7 +336,8 @@ type tracesErrorMarshaler struct {
 }
 
 type metricsErrorMarshaler struct {
-	err error
+	err      error
+	messages []*sarama.ProducerMessage
 }
 
 type logsErrorMarshaler struct {
@@ -289,7 +345,7 @@ type logsErrorMarshaler struct {
 }
 
 func (e metricsErrorMarshaler) Marshal(_ pmetric.Metrics, _ string) ([]*sarama.ProducerMessage, error) {
-	return nil, e.err
+	return e.messages, e.err
 }
 
 func (e metricsErrorMarshaler) Encoding() string {
@@ -32,7 +32,8 @@ type TracesMarshaler interface {
 
 // MetricsMarshaler marshals metrics into Message array
 type MetricsMarshaler interface {
-	// Marshal serializes metrics into sarama's ProducerMessages
+	// Marshal serializes metrics into sarama's ProducerMessages. The messages
+	// returned alongside an error hold the metrics that could be serialized.
 	Marshal(metrics pmetric.Metrics, topic string) ([]*sarama.ProducerMessage, error)
 
 	// Encoding returns encoding name
@@ -66,9 +67,11 @@ func tracesMarshalers() map[string]TracesMarshaler {
 func metricsMarshalers() map[string]MetricsMarshaler {
 	otlpPb := newPdataMetricsMarshaler(pmetric.NewProtoMarshaler(), defaultEncoding)
 	otlpJSON := newPdataMetricsMarshaler(pmetric.NewJSONMarshaler(), "otlp_json")
+	prometheusRemoteWrite := prometheusRemoteWriteMarshaler{}
 	return map[string]MetricsMarshaler{
-		otlpPb.Encoding():   otlpPb,
-		otlpJSON.Encoding(): otlpJSON,
+		otlpPb.Encoding():                otlpPb,
+		otlpJSON.Encoding():              otlpJSON,
+		prometheusRemoteWrite.Encoding(): prometheusRemoteWrite,
 	}
 }
 
@@ -76,8 +79,12 @@ func metricsMarshalers() map[string]MetricsMarshaler {
 func logsMarshalers() map[string]LogsMarshaler {
 	otlpPb := newPdataLogsMarshaler(plog.NewProtoMarshaler(), defaultEncoding)
 	otlpJSON := newPdataLogsMarshaler(plog.NewJSONMarshaler(), "otlp_json")
+	raw := rawLogsMarshaler{}
+	jsonRecords := jsonLogsMarshaler{}
 	return map[string]LogsMarshaler{
-		otlpPb.Encoding():   otlpPb,
-		otlpJSON.Encoding(): otlpJSON,
+		otlpPb.Encoding():      otlpPb,
+		otlpJSON.Encoding():    otlpJSON,
+		raw.Encoding():         raw,
+		jsonRecords.Encoding(): jsonRecords,
 	}
 }
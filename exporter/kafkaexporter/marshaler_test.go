@@ -48,6 +48,7 @@ func TestDefaultMetricsMarshalers(t *testing.T) {
 	expectedEncodings := []string{
 		"otlp_proto",
 		"otlp_json",
+		"prometheus_remote_write",
 	}
 	marshalers := metricsMarshalers()
 	assert.Equal(t, len(expectedEncodings), len(marshalers))
@@ -64,6 +65,8 @@ func TestDefaultLogsMarshalers(t *testing.T) {
 	expectedEncodings := []string{
 		"otlp_proto",
 		"otlp_json",
+		"raw",
+		"json",
 	}
 	marshalers := logsMarshalers()
 	assert.Equal(t, len(expectedEncodings), len(marshalers))
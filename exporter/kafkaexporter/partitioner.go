@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"github.com/Shopify/sarama"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// messageRoute is the topic and the key of the messages a part of the data is sent with.
+type messageRoute struct {
+	topic string
+	key   string
+}
+
+// router splits the data into the parts that are sent to the same topic with the same message key.
+type router struct {
+	topic               string
+	topicAttribute      string
+	keyAttribute        string
+	partitionTracesByID bool
+}
+
+func newRouter(config Config) router {
+	return router{
+		topic:               config.Topic,
+		topicAttribute:      config.TopicFromAttribute,
+		keyAttribute:        config.PartitionByResourceAttribute,
+		partitionTracesByID: config.PartitionTracesByID,
+	}
+}
+
+// isStatic returns whether all the data is sent to the configured topic without message key.
+func (r router) isStatic() bool {
+	return r.topicAttribute == "" && r.keyAttribute == "" && !r.partitionTracesByID
+}
+
+// route returns the route of the data of the resource. The topic is the value of the topic attribute,
+// or the configured topic when it is missing, and the key is the value of the key attribute.
+func (r router) route(resource pcommon.Resource) messageRoute {
+	route := messageRoute{topic: r.topic}
+	if r.topicAttribute != "" {
+		if v, ok := resource.Attributes().Get(r.topicAttribute); ok && v.AsString() != "" {
+			route.topic = v.AsString()
+		}
+	}
+	if r.keyAttribute != "" {
+		if v, ok := resource.Attributes().Get(r.keyAttribute); ok {
+			route.key = v.AsString()
+		}
+	}
+	return route
+}
+
+// splitTraces groups the resource spans by route. When the traces are partitioned by ID, the spans
+// are grouped by trace, along with copies of their resource and scope.
+func (r router) splitTraces(td ptrace.Traces) map[messageRoute]ptrace.Traces {
+	if r.isStatic() {
+		return map[messageRoute]ptrace.Traces{{topic: r.topic}: td}
+	}
+
+	parts := make(map[messageRoute]ptrace.Traces)
+	getPart := func(route messageRoute) ptrace.Traces {
+		part, ok := parts[route]
+		if !ok {
+			part = ptrace.NewTraces()
+			parts[route] = part
+		}
+		return part
+	}
+
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		route := r.route(rs.Resource())
+		if !r.partitionTracesByID {
+			rs.CopyTo(getPart(route).ResourceSpans().AppendEmpty())
+			continue
+		}
+
+		// The resource and scope of the spans of a trace are only copied once per trace.
+		resourceSpansByTrace := make(map[pcommon.TraceID]ptrace.ResourceSpans)
+		sss := rs.ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			ss := sss.At(j)
+			scopeSpansByTrace := make(map[pcommon.TraceID]ptrace.ScopeSpans)
+			spans := ss.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				traceID := span.TraceID()
+				dest, ok := scopeSpansByTrace[traceID]
+				if !ok {
+					destResource, ok := resourceSpansByTrace[traceID]
+					if !ok {
+						route.key = traceID.HexString()
+						destResource = getPart(route).ResourceSpans().AppendEmpty()
+						rs.Resource().CopyTo(destResource.Resource())
+						destResource.SetSchemaUrl(rs.SchemaUrl())
+						resourceSpansByTrace[traceID] = destResource
+					}
+					dest = destResource.ScopeSpans().AppendEmpty()
+					ss.Scope().CopyTo(dest.Scope())
+					dest.SetSchemaUrl(ss.SchemaUrl())
+					scopeSpansByTrace[traceID] = dest
+				}
+				span.CopyTo(dest.Spans().AppendEmpty())
+			}
+		}
+	}
+	return parts
+}
+
+// splitMetrics groups the resource metrics by route.
+func (r router) splitMetrics(md pmetric.Metrics) map[messageRoute]pmetric.Metrics {
+	if r.isStatic() {
+		return map[messageRoute]pmetric.Metrics{{topic: r.topic}: md}
+	}
+
+	parts := make(map[messageRoute]pmetric.Metrics)
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		route := r.route(rm.Resource())
+		part, ok := parts[route]
+		if !ok {
+			part = pmetric.NewMetrics()
+			parts[route] = part
+		}
+		rm.CopyTo(part.ResourceMetrics().AppendEmpty())
+	}
+	return parts
+}
+
+// splitLogs groups the resource logs by route.
+func (r router) splitLogs(ld plog.Logs) map[messageRoute]plog.Logs {
+	if r.isStatic() {
+		return map[messageRoute]plog.Logs{{topic: r.topic}: ld}
+	}
+
+	parts := make(map[messageRoute]plog.Logs)
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		route := r.route(rl.Resource())
+		part, ok := parts[route]
+		if !ok {
+			part = plog.NewLogs()
+			parts[route] = part
+		}
+		rl.CopyTo(part.ResourceLogs().AppendEmpty())
+	}
+	return parts
+}
+
+// setMessagesKey sets the key of the messages of a route, the key set by the marshaler is kept
+// when the route has no key.
+func setMessagesKey(messages []*sarama.ProducerMessage, key string) {
+	if key == "" {
+		return
+	}
+	for _, message := range messages {
+		message.Key = sarama.ByteEncoder(key)
+	}
+}
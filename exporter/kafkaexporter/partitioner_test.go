@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var (
+	testTraceID1 = pcommon.NewTraceID([16]byte{1})
+	testTraceID2 = pcommon.NewTraceID([16]byte{2})
+)
+
+func TestRouter_Static(t *testing.T) {
+	r := newRouter(Config{Topic: "otlp_spans"})
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().Resource().Attributes().UpsertString("tenant", "acme")
+
+	parts := r.splitTraces(td)
+	require.Len(t, parts, 1)
+	assert.Equal(t, td, parts[messageRoute{topic: "otlp_spans"}])
+}
+
+func TestRouter_TopicFromAttribute(t *testing.T) {
+	r := newRouter(Config{Topic: "otlp_logs", TopicFromAttribute: "tenant"})
+	ld := plog.NewLogs()
+	for _, tenant := range []string{"acme", "", "acme", "globex"} {
+		rl := ld.ResourceLogs().AppendEmpty()
+		if tenant != "" {
+			rl.Resource().Attributes().UpsertString("tenant", tenant)
+		}
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	}
+
+	parts := r.splitLogs(ld)
+	require.Len(t, parts, 3)
+	assert.Equal(t, 2, parts[messageRoute{topic: "acme"}].LogRecordCount())
+	assert.Equal(t, 1, parts[messageRoute{topic: "globex"}].LogRecordCount())
+	assert.Equal(t, 1, parts[messageRoute{topic: "otlp_logs"}].LogRecordCount())
+}
+
+func TestRouter_PartitionByResourceAttribute(t *testing.T) {
+	r := newRouter(Config{Topic: "otlp_metrics", PartitionByResourceAttribute: "service.name"})
+	md := pmetric.NewMetrics()
+	for _, service := range []string{"checkout", "cart", "checkout"} {
+		rm := md.ResourceMetrics().AppendEmpty()
+		rm.Resource().Attributes().UpsertString("service.name", service)
+		rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetName("requests")
+	}
+
+	parts := r.splitMetrics(md)
+	require.Len(t, parts, 2)
+	assert.Equal(t, 2, parts[messageRoute{topic: "otlp_metrics", key: "checkout"}].ResourceMetrics().Len())
+	assert.Equal(t, 1, parts[messageRoute{topic: "otlp_metrics", key: "cart"}].ResourceMetrics().Len())
+}
+
+func TestRouter_PartitionTracesByID(t *testing.T) {
+	r := newRouter(Config{Topic: "otlp_spans", PartitionTracesByID: true})
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().UpsertString("service.name", "checkout")
+	for _, scope := range []string{"first", "second"} {
+		ss := rs.ScopeSpans().AppendEmpty()
+		ss.Scope().SetName(scope)
+		for _, traceID := range []pcommon.TraceID{testTraceID1, testTraceID2, testTraceID1} {
+			span := ss.Spans().AppendEmpty()
+			span.SetTraceID(traceID)
+			span.SetName(scope)
+		}
+	}
+
+	parts := r.splitTraces(td)
+	require.Len(t, parts, 2)
+
+	trace1 := parts[messageRoute{topic: "otlp_spans", key: testTraceID1.HexString()}]
+	require.Equal(t, 1, trace1.ResourceSpans().Len())
+	assert.Equal(t, map[string]interface{}{"service.name": "checkout"}, trace1.ResourceSpans().At(0).Resource().Attributes().AsRaw())
+	scopes := trace1.ResourceSpans().At(0).ScopeSpans()
+	require.Equal(t, 2, scopes.Len())
+	for i, scope := range []string{"first", "second"} {
+		assert.Equal(t, scope, scopes.At(i).Scope().Name())
+		assert.Equal(t, 2, scopes.At(i).Spans().Len())
+	}
+
+	trace2 := parts[messageRoute{topic: "otlp_spans", key: testTraceID2.HexString()}]
+	assert.Equal(t, 2, trace2.SpanCount())
+	assert.Equal(t, 2, trace2.ResourceSpans().At(0).ScopeSpans().Len())
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"sort"
+
+	"github.com/Shopify/sarama"
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+)
+
+// prometheusRemoteWriteMarshaler writes the metrics as a single message, whose payload is the
+// snappy compressed Protobuf `WriteRequest` sent by Prometheus remote write.
+type prometheusRemoteWriteMarshaler struct{}
+
+var _ MetricsMarshaler = (*prometheusRemoteWriteMarshaler)(nil)
+
+func (p prometheusRemoteWriteMarshaler) Marshal(metrics pmetric.Metrics, topic string) ([]*sarama.ProducerMessage, error) {
+	// The metrics that cannot be converted, such as delta sums, are reported in
+	// convErr, the time series of the other metrics are sent anyway.
+	tsMap, convErr := prometheusremotewrite.FromMetrics(metrics, prometheusremotewrite.Settings{})
+	if len(tsMap) == 0 {
+		return nil, convErr
+	}
+
+	// Sort the time series so that the payload of the same metrics is always the same.
+	keys := make([]string, 0, len(tsMap))
+	for key := range tsMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	request := &prompb.WriteRequest{Timeseries: make([]prompb.TimeSeries, 0, len(keys))}
+	for _, key := range keys {
+		request.Timeseries = append(request.Timeseries, *tsMap[key])
+	}
+
+	bts, err := proto.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	return []*sarama.ProducerMessage{
+		{
+			Topic: topic,
+			Value: sarama.ByteEncoder(snappy.Encode(nil, bts)),
+		},
+	}, convErr
+}
+
+func (p prometheusRemoteWriteMarshaler) Encoding() string {
+	return "prometheus_remote_write"
+}
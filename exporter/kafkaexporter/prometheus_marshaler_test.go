@@ -0,0 +1,109 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestPrometheusRemoteWriteMarshaler(t *testing.T) {
+	ts := time.Date(2022, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	metrics := pmetric.NewMetrics()
+	rm := metrics.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().UpsertString("service.name", "checkout")
+	m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("queue_size")
+	m.SetDataType(pmetric.MetricDataTypeGauge)
+	dp := m.Gauge().DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	dp.SetIntVal(7)
+	dp.Attributes().UpsertString("queue", "orders")
+
+	messages, err := prometheusRemoteWriteMarshaler{}.Marshal(metrics, "topic")
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "topic", messages[0].Topic)
+
+	compressed, err := messages[0].Value.Encode()
+	require.NoError(t, err)
+	bts, err := snappy.Decode(nil, compressed)
+	require.NoError(t, err)
+	var request prompb.WriteRequest
+	require.NoError(t, proto.Unmarshal(bts, &request))
+
+	var series *prompb.TimeSeries
+	for i := range request.Timeseries {
+		for _, label := range request.Timeseries[i].Labels {
+			if label.Name == "__name__" && label.Value == "queue_size" {
+				series = &request.Timeseries[i]
+			}
+		}
+	}
+	require.NotNil(t, series)
+	assert.Contains(t, series.Labels, prompb.Label{Name: "queue", Value: "orders"})
+	assert.Contains(t, series.Labels, prompb.Label{Name: "job", Value: "checkout"})
+	assert.Equal(t, []prompb.Sample{{Value: 7, Timestamp: ts.UnixMilli()}}, series.Samples)
+}
+
+func TestPrometheusRemoteWriteMarshaler_Empty(t *testing.T) {
+	messages, err := prometheusRemoteWriteMarshaler{}.Marshal(pmetric.NewMetrics(), "topic")
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+func TestPrometheusRemoteWriteMarshaler_PartialConversion(t *testing.T) {
+	metrics := pmetric.NewMetrics()
+	ms := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	gauge := ms.AppendEmpty()
+	gauge.SetName("queue_size")
+	gauge.SetDataType(pmetric.MetricDataTypeGauge)
+	gauge.Gauge().DataPoints().AppendEmpty().SetIntVal(7)
+	delta := ms.AppendEmpty()
+	delta.SetName("requests")
+	delta.SetDataType(pmetric.MetricDataTypeSum)
+	delta.Sum().SetAggregationTemporality(pmetric.MetricAggregationTemporalityDelta)
+	delta.Sum().DataPoints().AppendEmpty().SetIntVal(3)
+
+	messages, err := prometheusRemoteWriteMarshaler{}.Marshal(metrics, "topic")
+	assert.Error(t, err)
+	require.Len(t, messages, 1)
+
+	compressed, err := messages[0].Value.Encode()
+	require.NoError(t, err)
+	bts, err := snappy.Decode(nil, compressed)
+	require.NoError(t, err)
+	var request prompb.WriteRequest
+	require.NoError(t, proto.Unmarshal(bts, &request))
+
+	var names []string
+	for _, series := range request.Timeseries {
+		for _, label := range series.Labels {
+			if label.Name == "__name__" {
+				names = append(names, label.Value)
+			}
+		}
+	}
+	assert.Equal(t, []string{"queue_size"}, names)
+}
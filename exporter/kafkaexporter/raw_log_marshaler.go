@@ -0,0 +1,76 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/Shopify/sarama"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+var errUnsupportedLogBodyType = errors.New("unsupported log body type")
+
+// rawLogsMarshaler writes the body of each log record as a separate message.
+type rawLogsMarshaler struct{}
+
+var _ LogsMarshaler = (*rawLogsMarshaler)(nil)
+
+func (r rawLogsMarshaler) Marshal(logs plog.Logs, topic string) ([]*sarama.ProducerMessage, error) {
+	var messages []*sarama.ProducerMessage
+	rls := logs.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		sls := rls.At(i).ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			records := sls.At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				bts, err := r.marshalBody(records.At(k).Body())
+				if err != nil {
+					return nil, err
+				}
+				messages = append(messages, &sarama.ProducerMessage{
+					Topic: topic,
+					Value: sarama.ByteEncoder(bts),
+				})
+			}
+		}
+	}
+	return messages, nil
+}
+
+// marshalBody returns the bytes of a bytes body and the text of a string body as is, the other
+// types of body are serialized to JSON.
+func (r rawLogsMarshaler) marshalBody(body pcommon.Value) ([]byte, error) {
+	switch body.Type() {
+	case pcommon.ValueTypeEmpty:
+		// A nil value would be a tombstone on compacted topics.
+		return []byte{}, nil
+	case pcommon.ValueTypeString:
+		return []byte(body.StringVal()), nil
+	case pcommon.ValueTypeBytes:
+		return body.BytesVal().AsRaw(), nil
+	case pcommon.ValueTypeBool, pcommon.ValueTypeInt, pcommon.ValueTypeDouble:
+		return []byte(body.AsString()), nil
+	case pcommon.ValueTypeMap, pcommon.ValueTypeSlice:
+		return json.Marshal(valueAsRaw(body))
+	}
+	return nil, errUnsupportedLogBodyType
+}
+
+func (r rawLogsMarshaler) Encoding() string {
+	return "raw"
+}
@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkaexporter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestRawLogsMarshaler(t *testing.T) {
+	tests := map[string]struct {
+		body func(pcommon.Value)
+		want string
+	}{
+		"string": {
+			body: func(v pcommon.Value) { v.SetStringVal("hello world") },
+			want: "hello world",
+		},
+		"bytes": {
+			body: func(v pcommon.Value) { v.SetBytesVal(pcommon.NewImmutableByteSlice([]byte{0x01, 0x02})) },
+			want: "\x01\x02",
+		},
+		"int": {
+			body: func(v pcommon.Value) { v.SetIntVal(42) },
+			want: "42",
+		},
+		"map": {
+			body: func(v pcommon.Value) {
+				m := v.SetEmptyMapVal()
+				m.UpsertString("message", "hello")
+				m.UpsertInt("count", 1)
+			},
+			want: `{"count":1,"message":"hello"}`,
+		},
+		"empty": {
+			body: func(v pcommon.Value) {},
+			want: "",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			logs := plog.NewLogs()
+			record := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+			test.body(record.Body())
+
+			messages, err := rawLogsMarshaler{}.Marshal(logs, "topic")
+			require.NoError(t, err)
+			require.Len(t, messages, 1)
+			assert.Equal(t, "topic", messages[0].Topic)
+			bts, err := messages[0].Value.Encode()
+			require.NoError(t, err)
+			assert.NotNil(t, bts)
+			assert.Equal(t, test.want, string(bts))
+		})
+	}
+}
+
+func TestRawLogsMarshaler_MessagePerRecord(t *testing.T) {
+	logs := plog.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	records.AppendEmpty().Body().SetStringVal("first")
+	records.AppendEmpty().Body().SetStringVal("second")
+	logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStringVal("third")
+
+	messages, err := rawLogsMarshaler{}.Marshal(logs, "topic")
+	require.NoError(t, err)
+	require.Len(t, messages, 3)
+	for i, want := range []string{"first", "second", "third"} {
+		bts, err := messages[i].Value.Encode()
+		require.NoError(t, err)
+		assert.Equal(t, want, string(bts))
+	}
+}
@@ -19,6 +19,8 @@ import (
 
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
 )
 
 type routingKey int
@@ -26,6 +28,7 @@ type routingKey int
 const (
 	traceIDRouting routingKey = iota
 	svcRouting
+	seriesRouting
 )
 
 // Config defines configuration for the exporter.
@@ -45,6 +48,7 @@ type Protocol struct {
 type ResolverSettings struct {
 	Static *StaticResolver `mapstructure:"static"`
 	DNS    *DNSResolver    `mapstructure:"dns"`
+	K8sSvc *K8sSvcResolver `mapstructure:"k8s"`
 }
 
 // StaticResolver defines the configuration for the resolver providing a fixed list of backends
@@ -59,3 +63,14 @@ type DNSResolver struct {
 	Interval time.Duration `mapstructure:"interval"`
 	Timeout  time.Duration `mapstructure:"timeout"`
 }
+
+// K8sSvcResolver defines the configuration for the resolver watching the endpoints of a Kubernetes service
+type K8sSvcResolver struct {
+	k8sconfig.APIConfig `mapstructure:",squash"`
+
+	// Service is the name of the service to watch, optionally followed by its namespace, e.g. "lb-svc.observability".
+	// When the namespace isn't specified, the "default" namespace is used.
+	Service string `mapstructure:"service"`
+	// Ports are the ports to export to on each of the service endpoints. When not specified, the default port 4317 is used.
+	Ports []int32 `mapstructure:"ports"`
+}
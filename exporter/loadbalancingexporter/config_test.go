@@ -21,6 +21,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/service/servicetest"
 )
 
@@ -33,4 +34,7 @@ func TestLoadConfig(t *testing.T) {
 	cfg, err := servicetest.LoadConfigAndValidate(filepath.Join("testdata", "config.yaml"), factories)
 	require.NoError(t, err)
 	require.NotNil(t, cfg)
+
+	k8sCfg := cfg.Exporters[config.NewComponentIDWithName(typeStr, "4")].(*Config)
+	assert.Equal(t, &K8sSvcResolver{Service: "lb-svc.observability", Ports: []int32{4317, 55690}}, k8sCfg.Resolver.K8sSvc)
 }
@@ -25,6 +25,8 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
 )
 
 func TestNewLoadBalancerNoResolver(t *testing.T) {
@@ -153,6 +155,44 @@ func TestMultipleResolvers(t *testing.T) {
 	assert.Equal(t, errMultipleResolversProvided, err)
 }
 
+func TestMultipleResolversWithK8s(t *testing.T) {
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			DNS: &DNSResolver{
+				Hostname: "service-1",
+			},
+			K8sSvc: &K8sSvcResolver{
+				Service: "lb-svc",
+			},
+		},
+	}
+
+	// test
+	p, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, nil)
+
+	// verify
+	assert.Nil(t, p)
+	assert.Equal(t, errMultipleResolversProvided, err)
+}
+
+func TestK8sResolverWithInvalidAuthType(t *testing.T) {
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			K8sSvc: &K8sSvcResolver{
+				APIConfig: k8sconfig.APIConfig{AuthType: "invalid"},
+				Service:   "lb-svc",
+			},
+		},
+	}
+
+	// test
+	p, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), cfg, nil)
+
+	// verify
+	assert.Nil(t, p)
+	assert.EqualError(t, err, "invalid authType for kubernetes: invalid")
+}
+
 func TestStartFailureStaticResolver(t *testing.T) {
 	// prepare
 	cfg := simpleConfig()
@@ -0,0 +1,311 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/multierr"
+)
+
+var _ component.MetricsExporter = (*metricExporterImp)(nil)
+
+type metricExporterImp struct {
+	loadBalancer loadBalancer
+	routingKey   routingKey
+
+	stopped    bool
+	shutdownWg sync.WaitGroup
+}
+
+// Create new metrics exporter
+func newMetricsExporter(params component.ExporterCreateSettings, cfg config.Exporter) (*metricExporterImp, error) {
+	exporterFactory := otlpexporter.NewFactory()
+
+	lb, err := newLoadBalancer(params, cfg, func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		oCfg := buildExporterConfig(cfg.(*Config), endpoint)
+		return exporterFactory.CreateMetricsExporter(ctx, params, &oCfg)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metricExporter := metricExporterImp{loadBalancer: lb, routingKey: seriesRouting}
+
+	switch cfg.(*Config).RoutingKey {
+	case "service":
+		metricExporter.routingKey = svcRouting
+	case "":
+	default:
+		return nil, fmt.Errorf("unsupported routing_key for metrics: %s", cfg.(*Config).RoutingKey)
+	}
+	return &metricExporter, nil
+}
+
+func (e *metricExporterImp) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+func (e *metricExporterImp) Start(ctx context.Context, host component.Host) error {
+	return e.loadBalancer.Start(ctx, host)
+}
+
+func (e *metricExporterImp) Shutdown(context.Context) error {
+	e.stopped = true
+	e.shutdownWg.Wait()
+	return nil
+}
+
+func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	var batches map[string]pmetric.Metrics
+	if e.routingKey == svcRouting {
+		var err error
+		if batches, err = splitMetricsByService(md, e.loadBalancer.Endpoint); err != nil {
+			return err
+		}
+	} else {
+		batches = splitMetricsBySeries(md, e.loadBalancer.Endpoint)
+	}
+
+	var errs error
+	for endpoint, batch := range batches {
+		errs = multierr.Append(errs, e.consumeMetric(ctx, endpoint, batch))
+	}
+	return errs
+}
+
+func (e *metricExporterImp) consumeMetric(ctx context.Context, endpoint string, md pmetric.Metrics) error {
+	exp, err := e.loadBalancer.Exporter(endpoint)
+	if err != nil {
+		return err
+	}
+
+	me, ok := exp.(component.MetricsExporter)
+	if !ok {
+		expectType := (*component.MetricsExporter)(nil)
+		return fmt.Errorf("unable to export metrics, unexpected exporter type: expected %T but got %T", expectType, exp)
+	}
+
+	start := time.Now()
+	err = me.ConsumeMetrics(ctx, md)
+	duration := time.Since(start)
+	if err == nil {
+		_ = stats.RecordWithTags(
+			ctx,
+			[]tag.Mutator{tag.Upsert(endpointTagKey, endpoint), successTrueMutator},
+			mBackendLatency.M(duration.Milliseconds()))
+	} else {
+		_ = stats.RecordWithTags(
+			ctx,
+			[]tag.Mutator{tag.Upsert(endpointTagKey, endpoint), successFalseMutator},
+			mBackendLatency.M(duration.Milliseconds()))
+	}
+
+	return err
+}
+
+// splitMetricsByService groups the resource metrics by the endpoint of their service name.
+func splitMetricsByService(md pmetric.Metrics, endpointFor func(identifier []byte) string) (map[string]pmetric.Metrics, error) {
+	batches := make(map[string]pmetric.Metrics)
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		svc, ok := rm.Resource().Attributes().Get("service.name")
+		if !ok {
+			return nil, errors.New("unable to get service name")
+		}
+		endpoint := endpointFor([]byte(svc.StringVal()))
+		batch, ok := batches[endpoint]
+		if !ok {
+			batch = pmetric.NewMetrics()
+			batches[endpoint] = batch
+		}
+		rm.CopyTo(batch.ResourceMetrics().AppendEmpty())
+	}
+	return batches, nil
+}
+
+// splitMetricsBySeries groups the data points by the endpoint of their time series,
+// identified by the attributes of their resource, the name of their metric and their
+// own attributes, so that the data points of a time series always go to the same endpoint.
+func splitMetricsBySeries(md pmetric.Metrics, endpointFor func(identifier []byte) string) map[string]pmetric.Metrics {
+	sb := newSeriesBatches()
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resourceID := appendAttributes(nil, rm.Resource().Attributes())
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			ms := sms.At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				m := ms.At(k)
+				metricID := append(append(resourceID[:len(resourceID):len(resourceID)], m.Name()...), 0)
+				endpoint := func(attrs pcommon.Map) string {
+					return endpointFor(appendAttributes(metricID[:len(metricID):len(metricID)], attrs))
+				}
+				loc := seriesLocation{resource: i, scope: j, metric: k}
+				switch m.DataType() {
+				case pmetric.MetricDataTypeGauge:
+					dps := m.Gauge().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						dp.CopyTo(sb.metric(endpoint(dp.Attributes()), md, loc).Gauge().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricDataTypeSum:
+					dps := m.Sum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						dp.CopyTo(sb.metric(endpoint(dp.Attributes()), md, loc).Sum().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricDataTypeHistogram:
+					dps := m.Histogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						dp.CopyTo(sb.metric(endpoint(dp.Attributes()), md, loc).Histogram().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricDataTypeExponentialHistogram:
+					dps := m.ExponentialHistogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						dp.CopyTo(sb.metric(endpoint(dp.Attributes()), md, loc).ExponentialHistogram().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricDataTypeSummary:
+					dps := m.Summary().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dp := dps.At(l)
+						dp.CopyTo(sb.metric(endpoint(dp.Attributes()), md, loc).Summary().DataPoints().AppendEmpty())
+					}
+				}
+			}
+		}
+	}
+	return sb.batches
+}
+
+// seriesLocation is the position of a metric in the original data.
+type seriesLocation struct {
+	resource, scope, metric int
+}
+
+// seriesBatches holds the data of each endpoint, with the same resources, scopes and
+// metrics as the original data, but only the data points of the endpoint.
+type seriesBatches struct {
+	batches   map[string]pmetric.Metrics
+	resources map[string]map[int]pmetric.ResourceMetrics
+	scopes    map[string]map[[2]int]pmetric.ScopeMetrics
+	metrics   map[string]map[seriesLocation]pmetric.Metric
+}
+
+func newSeriesBatches() *seriesBatches {
+	return &seriesBatches{
+		batches:   make(map[string]pmetric.Metrics),
+		resources: make(map[string]map[int]pmetric.ResourceMetrics),
+		scopes:    make(map[string]map[[2]int]pmetric.ScopeMetrics),
+		metrics:   make(map[string]map[seriesLocation]pmetric.Metric),
+	}
+}
+
+// metric returns the metric of the endpoint at the location, without any data point,
+// copying the resource, the scope and the description of the metric on first use.
+func (sb *seriesBatches) metric(endpoint string, md pmetric.Metrics, loc seriesLocation) pmetric.Metric {
+	if m, ok := sb.metrics[endpoint][loc]; ok {
+		return m
+	}
+
+	batch, ok := sb.batches[endpoint]
+	if !ok {
+		batch = pmetric.NewMetrics()
+		sb.batches[endpoint] = batch
+		sb.resources[endpoint] = make(map[int]pmetric.ResourceMetrics)
+		sb.scopes[endpoint] = make(map[[2]int]pmetric.ScopeMetrics)
+		sb.metrics[endpoint] = make(map[seriesLocation]pmetric.Metric)
+	}
+
+	srcRM := md.ResourceMetrics().At(loc.resource)
+	rm, ok := sb.resources[endpoint][loc.resource]
+	if !ok {
+		rm = batch.ResourceMetrics().AppendEmpty()
+		srcRM.Resource().CopyTo(rm.Resource())
+		rm.SetSchemaUrl(srcRM.SchemaUrl())
+		sb.resources[endpoint][loc.resource] = rm
+	}
+
+	srcSM := srcRM.ScopeMetrics().At(loc.scope)
+	scopeKey := [2]int{loc.resource, loc.scope}
+	sm, ok := sb.scopes[endpoint][scopeKey]
+	if !ok {
+		sm = rm.ScopeMetrics().AppendEmpty()
+		srcSM.Scope().CopyTo(sm.Scope())
+		sm.SetSchemaUrl(srcSM.SchemaUrl())
+		sb.scopes[endpoint][scopeKey] = sm
+	}
+
+	m := sm.Metrics().AppendEmpty()
+	copyMetricDescription(srcSM.Metrics().At(loc.metric), m)
+	sb.metrics[endpoint][loc] = m
+	return m
+}
+
+// copyMetricDescription copies everything but the data points of the metric.
+func copyMetricDescription(src, dest pmetric.Metric) {
+	dest.SetName(src.Name())
+	dest.SetDescription(src.Description())
+	dest.SetUnit(src.Unit())
+	switch src.DataType() {
+	case pmetric.MetricDataTypeGauge:
+		dest.SetEmptyGauge()
+	case pmetric.MetricDataTypeSum:
+		dest.SetEmptySum().SetAggregationTemporality(src.Sum().AggregationTemporality())
+		dest.Sum().SetIsMonotonic(src.Sum().IsMonotonic())
+	case pmetric.MetricDataTypeHistogram:
+		dest.SetEmptyHistogram().SetAggregationTemporality(src.Histogram().AggregationTemporality())
+	case pmetric.MetricDataTypeExponentialHistogram:
+		dest.SetEmptyExponentialHistogram().SetAggregationTemporality(src.ExponentialHistogram().AggregationTemporality())
+	case pmetric.MetricDataTypeSummary:
+		dest.SetEmptySummary()
+	}
+}
+
+// appendAttributes appends the attributes sorted by key, so that the
+// identifier of a time series does not depend on the attributes order.
+func appendAttributes(dest []byte, attrs pcommon.Map) []byte {
+	keys := make([]string, 0, attrs.Len())
+	attrs.Range(func(k string, _ pcommon.Value) bool {
+		keys = append(keys, k)
+		return true
+	})
+	sort.Strings(keys)
+	for _, k := range keys {
+		v, _ := attrs.Get(k)
+		dest = append(dest, k...)
+		dest = append(dest, '=')
+		dest = append(dest, v.AsString()...)
+		dest = append(dest, 0)
+	}
+	return dest
+}
@@ -0,0 +1,396 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestNewMetricsExporter(t *testing.T) {
+	for _, tt := range []struct {
+		desc       string
+		config     *Config
+		routingKey routingKey
+		err        error
+	}{
+		{
+			"simple",
+			simpleConfig(),
+			seriesRouting,
+			nil,
+		},
+		{
+			"service",
+			serviceBasedRoutingConfig(),
+			svcRouting,
+			nil,
+		},
+		{
+			"unsupported routing key",
+			&Config{
+				ExporterSettings: config.NewExporterSettings(config.NewComponentID(typeStr)),
+				Resolver: ResolverSettings{
+					Static: &StaticResolver{Hostnames: []string{"endpoint-1"}},
+				},
+				RoutingKey: "traceID",
+			},
+			seriesRouting,
+			fmt.Errorf("unsupported routing_key for metrics: traceID"),
+		},
+		{
+			"empty",
+			&Config{
+				ExporterSettings: config.NewExporterSettings(config.NewComponentID(typeStr)),
+			},
+			seriesRouting,
+			errNoResolver,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// test
+			p, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), tt.config)
+
+			// verify
+			require.Equal(t, tt.err, err)
+			if err == nil {
+				assert.Equal(t, tt.routingKey, p.routingKey)
+			}
+		})
+	}
+}
+
+func TestMetricExporterShutdown(t *testing.T) {
+	p, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), simpleConfig())
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	// test
+	res := p.Shutdown(context.Background())
+
+	// verify
+	assert.Nil(t, res)
+}
+
+func TestConsumeMetricsUnexpectedExporterType(t *testing.T) {
+	componentFactory := func(ctx context.Context, endpoint string) (component.Exporter, error) {
+		return newNopMockExporter(), nil
+	}
+	lb, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), simpleConfig(), componentFactory)
+	require.NotNil(t, lb)
+	require.NoError(t, err)
+
+	p, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), simpleConfig())
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	// pre-load an exporter here, so that we don't use the actual OTLP exporter
+	lb.addMissingExporters(context.Background(), []string{"endpoint-1"})
+	lb.res = &mockResolver{
+		triggerCallbacks: true,
+		onResolve: func(ctx context.Context) ([]string, error) {
+			return []string{"endpoint-1"}, nil
+		},
+	}
+	p.loadBalancer = lb
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	// test
+	res := p.ConsumeMetrics(context.Background(), seriesMetrics(1))
+
+	// verify
+	assert.EqualError(t, res, fmt.Sprintf("unable to export metrics, unexpected exporter type: expected *component.MetricsExporter but got %T", newNopMockExporter()))
+}
+
+func TestConsumeMetricsBySeries(t *testing.T) {
+	sinks := newEndpointMetricsSinks()
+	lb, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), simpleConfig(), sinks.componentFactory)
+	require.NotNil(t, lb)
+	require.NoError(t, err)
+
+	p, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), simpleConfig())
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	lb.res = &mockResolver{
+		triggerCallbacks: true,
+		onResolve: func(ctx context.Context) ([]string, error) {
+			return []string{"endpoint-1", "endpoint-2", "endpoint-3"}, nil
+		},
+	}
+	p.loadBalancer = lb
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	// test
+	require.NoError(t, p.ConsumeMetrics(context.Background(), seriesMetrics(50)))
+	require.NoError(t, p.ConsumeMetrics(context.Background(), seriesMetrics(50)))
+
+	// verify
+	assert.Len(t, sinks.endpoints(), 3, "the series should be spread over all endpoints")
+	first := sinks.seriesEndpoints(t)
+	assert.Len(t, first, 50)
+
+	// the resolver now returns one more endpoint: some series move, and they still
+	// go to a single endpoint each
+	sinks.reset()
+	lb.onBackendChanges([]string{"endpoint-1", "endpoint-2", "endpoint-3", "endpoint-4"})
+	require.NoError(t, p.ConsumeMetrics(context.Background(), seriesMetrics(50)))
+	require.NoError(t, p.ConsumeMetrics(context.Background(), seriesMetrics(50)))
+
+	second := sinks.seriesEndpoints(t)
+	assert.Len(t, second, 50)
+	moved := 0
+	for series, endpoint := range second {
+		if first[series] != endpoint {
+			assert.Equal(t, "endpoint-4:4317", endpoint, "series should only move to the new endpoint")
+			moved++
+		}
+	}
+	assert.Greater(t, moved, 0)
+	assert.Less(t, moved, 50)
+}
+
+func TestConsumeMetricsByService(t *testing.T) {
+	sinks := newEndpointMetricsSinks()
+	lb, err := newLoadBalancer(componenttest.NewNopExporterCreateSettings(), serviceBasedRoutingConfig(), sinks.componentFactory)
+	require.NotNil(t, lb)
+	require.NoError(t, err)
+
+	p, err := newMetricsExporter(componenttest.NewNopExporterCreateSettings(), serviceBasedRoutingConfig())
+	require.NotNil(t, p)
+	require.NoError(t, err)
+
+	lb.res = &mockResolver{
+		triggerCallbacks: true,
+		onResolve: func(ctx context.Context) ([]string, error) {
+			return []string{"endpoint-1", "endpoint-2", "endpoint-3"}, nil
+		},
+	}
+	p.loadBalancer = lb
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	// test
+	require.NoError(t, p.ConsumeMetrics(context.Background(), seriesMetrics(50)))
+
+	// verify
+	assert.Len(t, sinks.endpoints(), 1, "all the series of a service should go to the same endpoint")
+	assert.Len(t, sinks.seriesEndpoints(t), 50)
+
+	noService := pmetric.NewMetrics()
+	noService.ResourceMetrics().AppendEmpty()
+	assert.EqualError(t, p.ConsumeMetrics(context.Background(), noService), "unable to get service name")
+}
+
+func TestSplitMetricsBySeries(t *testing.T) {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.SetSchemaUrl("https://opentelemetry.io/schemas/1.9.0")
+	rm.Resource().Attributes().UpsertString("service.name", "svc")
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("scope")
+
+	sum := sm.Metrics().AppendEmpty()
+	sum.SetName("requests")
+	sum.SetUnit("1")
+	sum.SetDataType(pmetric.MetricDataTypeSum)
+	sum.Sum().SetIsMonotonic(true)
+	sum.Sum().SetAggregationTemporality(pmetric.MetricAggregationTemporalityCumulative)
+	for _, route := range []string{"/a", "/b", "/a"} {
+		dp := sum.Sum().DataPoints().AppendEmpty()
+		dp.Attributes().UpsertString("route", route)
+		dp.SetIntVal(1)
+	}
+
+	hist := sm.Metrics().AppendEmpty()
+	hist.SetName("latency")
+	hist.SetDataType(pmetric.MetricDataTypeHistogram)
+	hist.Histogram().SetAggregationTemporality(pmetric.MetricAggregationTemporalityDelta)
+	hist.Histogram().DataPoints().AppendEmpty().Attributes().UpsertString("route", "/a")
+
+	// each series gets its own endpoint
+	endpointFor := func(identifier []byte) string {
+		return string(identifier)
+	}
+
+	// test
+	batches := splitMetricsBySeries(md, endpointFor)
+
+	// verify
+	require.Len(t, batches, 3)
+	for endpoint, batch := range batches {
+		require.Equal(t, 1, batch.ResourceMetrics().Len(), endpoint)
+		gotRM := batch.ResourceMetrics().At(0)
+		assert.Equal(t, rm.SchemaUrl(), gotRM.SchemaUrl())
+		assert.Equal(t, rm.Resource().Attributes().AsRaw(), gotRM.Resource().Attributes().AsRaw())
+		require.Equal(t, 1, gotRM.ScopeMetrics().Len())
+		assert.Equal(t, "scope", gotRM.ScopeMetrics().At(0).Scope().Name())
+		require.Equal(t, 1, gotRM.ScopeMetrics().At(0).Metrics().Len())
+
+		m := gotRM.ScopeMetrics().At(0).Metrics().At(0)
+		switch m.Name() {
+		case "requests":
+			assert.Equal(t, "1", m.Unit())
+			assert.True(t, m.Sum().IsMonotonic())
+			assert.Equal(t, pmetric.MetricAggregationTemporalityCumulative, m.Sum().AggregationTemporality())
+			route, _ := m.Sum().DataPoints().At(0).Attributes().Get("route")
+			if route.StringVal() == "/a" {
+				assert.Equal(t, 2, m.Sum().DataPoints().Len())
+			} else {
+				assert.Equal(t, 1, m.Sum().DataPoints().Len())
+			}
+		case "latency":
+			assert.Equal(t, pmetric.MetricAggregationTemporalityDelta, m.Histogram().AggregationTemporality())
+			assert.Equal(t, 1, m.Histogram().DataPoints().Len())
+		default:
+			t.Errorf("unexpected metric %q", m.Name())
+		}
+	}
+}
+
+func TestSeriesIdentifierIgnoresAttributesOrder(t *testing.T) {
+	first := pcommon.NewMap()
+	first.UpsertString("a", "1")
+	first.UpsertString("b", "2")
+
+	second := pcommon.NewMap()
+	second.UpsertString("b", "2")
+	second.UpsertString("a", "1")
+
+	third := pcommon.NewMap()
+	third.UpsertString("a", "12")
+
+	assert.Equal(t, appendAttributes(nil, first), appendAttributes(nil, second))
+	assert.NotEqual(t, appendAttributes(nil, first), appendAttributes(nil, third))
+}
+
+// seriesMetrics returns a single gauge with the given number of series.
+func seriesMetrics(series int) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().UpsertString("service.name", "service-1")
+	m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("gauge")
+	m.SetDataType(pmetric.MetricDataTypeGauge)
+	for i := 0; i < series; i++ {
+		dp := m.Gauge().DataPoints().AppendEmpty()
+		dp.Attributes().UpsertInt("series", int64(i))
+		dp.SetIntVal(int64(i))
+	}
+	return md
+}
+
+// endpointMetricsSinks records the metrics received by each endpoint.
+type endpointMetricsSinks struct {
+	mu       sync.Mutex
+	received map[string][]pmetric.Metrics
+}
+
+func newEndpointMetricsSinks() *endpointMetricsSinks {
+	return &endpointMetricsSinks{received: map[string][]pmetric.Metrics{}}
+}
+
+func (s *endpointMetricsSinks) componentFactory(_ context.Context, endpoint string) (component.Exporter, error) {
+	return newMockMetricsExporter(func(_ context.Context, md pmetric.Metrics) error {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.received[endpoint] = append(s.received[endpoint], md)
+		return nil
+	}), nil
+}
+
+func (s *endpointMetricsSinks) endpoints() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var endpoints []string
+	for endpoint := range s.received {
+		endpoints = append(endpoints, endpoint)
+	}
+	return endpoints
+}
+
+func (s *endpointMetricsSinks) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.received = map[string][]pmetric.Metrics{}
+}
+
+// seriesEndpoints returns the endpoint of each series, failing if a series was sent to more than one endpoint.
+func (s *endpointMetricsSinks) seriesEndpoints(t *testing.T) map[int64]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	endpoints := map[int64]string{}
+	for endpoint, batches := range s.received {
+		for _, md := range batches {
+			dps := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0).Gauge().DataPoints()
+			for i := 0; i < dps.Len(); i++ {
+				series, _ := dps.At(i).Attributes().Get("series")
+				if previous, ok := endpoints[series.IntVal()]; ok {
+					assert.Equal(t, previous, endpoint, "series %d sent to more than one endpoint", series.IntVal())
+				}
+				endpoints[series.IntVal()] = endpoint
+			}
+		}
+	}
+	return endpoints
+}
+
+type mockMetricsExporter struct {
+	component.Component
+	consumeMetricsFn func(ctx context.Context, md pmetric.Metrics) error
+}
+
+func (e *mockMetricsExporter) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{MutatesData: false}
+}
+
+func (e *mockMetricsExporter) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	if e.consumeMetricsFn == nil {
+		return nil
+	}
+	return e.consumeMetricsFn(ctx, md)
+}
+
+func newMockMetricsExporter(consumeMetricsFn func(ctx context.Context, md pmetric.Metrics) error) component.MetricsExporter {
+	return &mockMetricsExporter{
+		Component:        mockComponent{},
+		consumeMetricsFn: consumeMetricsFn,
+	}
+}
@@ -0,0 +1,224 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+var _ resolver = (*k8sResolver)(nil)
+
+const (
+	defaultK8sNamespace   = "default"
+	defaultK8sSyncTimeout = 5 * time.Second
+)
+
+var (
+	errNoSvc = errors.New("no service specified to resolve the backends")
+
+	k8sResolverMutator = tag.Upsert(tag.MustNewKey("resolver"), "k8s")
+
+	k8sResolverSuccessTrueMutators  = []tag.Mutator{k8sResolverMutator, successTrueMutator}
+	k8sResolverSuccessFalseMutators = []tag.Mutator{k8sResolverMutator, successFalseMutator}
+)
+
+// k8sResolver watches the Endpoints object of a Kubernetes service, so that the list of
+// backends is updated as soon as the pods behind the service change.
+type k8sResolver struct {
+	logger *zap.Logger
+
+	client      k8s.Interface
+	svcName     string
+	svcNs       string
+	ports       []int32
+	syncTimeout time.Duration
+
+	endpoints         []string
+	onChangeCallbacks []func([]string)
+
+	stopCh             chan (struct{})
+	updateLock         sync.RWMutex
+	shutdownWg         sync.WaitGroup
+	changeCallbackLock sync.RWMutex
+}
+
+func newK8sResolver(client k8s.Interface, logger *zap.Logger, service string, ports []int32) (*k8sResolver, error) {
+	if len(service) == 0 {
+		return nil, errNoSvc
+	}
+
+	name, namespace := service, defaultK8sNamespace
+	if idx := strings.Index(service, "."); idx != -1 {
+		name, namespace = service[:idx], service[idx+1:]
+	}
+
+	return &k8sResolver{
+		logger:      logger,
+		client:      client,
+		svcName:     name,
+		svcNs:       namespace,
+		ports:       ports,
+		syncTimeout: defaultK8sSyncTimeout,
+		stopCh:      make(chan struct{}),
+	}, nil
+}
+
+func (r *k8sResolver) start(ctx context.Context) error {
+	selector := fields.OneTermEqualSelector("metadata.name", r.svcName).String()
+	listWatch := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.FieldSelector = selector
+			return r.client.CoreV1().Endpoints(r.svcNs).List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.FieldSelector = selector
+			return r.client.CoreV1().Endpoints(r.svcNs).Watch(context.Background(), options)
+		},
+	}
+
+	_, controller := cache.NewInformer(listWatch, &corev1.Endpoints{}, 0, cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			r.onEndpoints(obj)
+		},
+		UpdateFunc: func(_, obj interface{}) {
+			r.onEndpoints(obj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if endpoints, ok := obj.(*corev1.Endpoints); ok && endpoints.Name != r.svcName {
+				return
+			}
+			r.update(nil)
+		},
+	})
+
+	r.shutdownWg.Add(1)
+	go func() {
+		defer r.shutdownWg.Done()
+		controller.Run(r.stopCh)
+	}()
+
+	// wait for the initial list of endpoints, so that the first batches aren't rejected
+	syncCtx, cancel := context.WithTimeout(ctx, r.syncTimeout)
+	defer cancel()
+	if !cache.WaitForCacheSync(syncCtx.Done(), controller.HasSynced) {
+		r.logger.Warn("failed to sync the endpoints of the service before the timeout, the backends will be updated once they are available",
+			zap.String("service", r.svcName), zap.String("namespace", r.svcNs))
+	}
+
+	r.logger.Debug("k8s service resolver started",
+		zap.String("service", r.svcName), zap.String("namespace", r.svcNs), zap.Int32s("ports", r.ports))
+	return nil
+}
+
+func (r *k8sResolver) shutdown(ctx context.Context) error {
+	r.changeCallbackLock.Lock()
+	r.onChangeCallbacks = nil
+	r.changeCallbackLock.Unlock()
+
+	close(r.stopCh)
+	r.shutdownWg.Wait()
+	return nil
+}
+
+func (r *k8sResolver) onEndpoints(obj interface{}) {
+	endpoints, ok := obj.(*corev1.Endpoints)
+	if !ok {
+		_ = stats.RecordWithTags(context.Background(), k8sResolverSuccessFalseMutators, mNumResolutions.M(1))
+		r.logger.Warn("unexpected object received from the k8s API", zap.String("type", fmt.Sprintf("%T", obj)))
+		return
+	}
+	if endpoints.Name != r.svcName {
+		return
+	}
+	_ = stats.RecordWithTags(context.Background(), k8sResolverSuccessTrueMutators, mNumResolutions.M(1))
+
+	var backends []string
+	for _, subset := range endpoints.Subsets {
+		// only the ready addresses are used, the not ready ones would reject the data
+		for _, addr := range subset.Addresses {
+			host := addr.IP
+			if strings.Contains(host, ":") {
+				// it's an IPv6 address
+				host = fmt.Sprintf("[%s]", host)
+			}
+			if len(r.ports) == 0 {
+				backends = append(backends, host)
+				continue
+			}
+			for _, port := range r.ports {
+				backends = append(backends, net.JoinHostPort(addr.IP, strconv.FormatInt(int64(port), 10)))
+			}
+		}
+	}
+	r.update(backends)
+}
+
+// update replaces the list of backends, and propagates it if it has changed.
+func (r *k8sResolver) update(backends []string) {
+	// keep it always in the same order
+	sort.Strings(backends)
+
+	r.updateLock.Lock()
+	if equalStringSlice(r.endpoints, backends) {
+		r.updateLock.Unlock()
+		return
+	}
+
+	// the list has changed!
+	r.endpoints = backends
+	r.updateLock.Unlock()
+	_ = stats.RecordWithTags(context.Background(), k8sResolverSuccessTrueMutators, mNumBackends.M(int64(len(backends))))
+
+	// propagate the change
+	r.changeCallbackLock.RLock()
+	for _, callback := range r.onChangeCallbacks {
+		callback(backends)
+	}
+	r.changeCallbackLock.RUnlock()
+}
+
+func (r *k8sResolver) resolve(context.Context) ([]string, error) {
+	r.updateLock.RLock()
+	defer r.updateLock.RUnlock()
+	return r.endpoints, nil
+}
+
+func (r *k8sResolver) onChange(f func([]string)) {
+	r.changeCallbackLock.Lock()
+	defer r.changeCallbackLock.Unlock()
+	r.onChangeCallbacks = append(r.onChangeCallbacks, f)
+}
@@ -0,0 +1,192 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewK8sResolver(t *testing.T) {
+	for _, tt := range []struct {
+		desc      string
+		service   string
+		name      string
+		namespace string
+		err       error
+	}{
+		{
+			desc:    "no service",
+			service: "",
+			err:     errNoSvc,
+		},
+		{
+			desc:      "default namespace",
+			service:   "lb",
+			name:      "lb",
+			namespace: "default",
+		},
+		{
+			desc:      "with namespace",
+			service:   "lb.observability",
+			name:      "lb",
+			namespace: "observability",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			res, err := newK8sResolver(fake.NewSimpleClientset(), zap.NewNop(), tt.service, nil)
+			require.Equal(t, tt.err, err)
+			if err == nil {
+				assert.Equal(t, tt.name, res.svcName)
+				assert.Equal(t, tt.namespace, res.svcNs)
+			}
+		})
+	}
+}
+
+func TestInitialK8sResolution(t *testing.T) {
+	// prepare
+	client := fake.NewSimpleClientset(
+		endpointsObject("lb", "observability", []string{"10.0.0.2", "10.0.0.1", "fd00::1"}, []string{"10.0.0.3"}),
+		endpointsObject("other", "observability", []string{"10.0.1.1"}, nil),
+	)
+	res, err := newK8sResolver(client, zap.NewNop(), "lb.observability", nil)
+	require.NoError(t, err)
+
+	var resolved []string
+	var mu sync.Mutex
+	res.onChange(func(endpoints []string) {
+		mu.Lock()
+		defer mu.Unlock()
+		resolved = endpoints
+	})
+
+	// test
+	require.NoError(t, res.start(context.Background()))
+	defer func() {
+		require.NoError(t, res.shutdown(context.Background()))
+	}()
+
+	// verify
+	expected := []string{"10.0.0.1", "10.0.0.2", "[fd00::1]"}
+	mu.Lock()
+	assert.Equal(t, expected, resolved)
+	mu.Unlock()
+	endpoints, err := res.resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, expected, endpoints)
+}
+
+func TestK8sResolutionWithPorts(t *testing.T) {
+	// prepare
+	client := fake.NewSimpleClientset(endpointsObject("lb", "default", []string{"10.0.0.1", "fd00::1"}, nil))
+	res, err := newK8sResolver(client, zap.NewNop(), "lb", []int32{4317, 55690})
+	require.NoError(t, err)
+
+	// test
+	require.NoError(t, res.start(context.Background()))
+	defer func() {
+		require.NoError(t, res.shutdown(context.Background()))
+	}()
+
+	// verify
+	endpoints, err := res.resolve(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1:4317", "10.0.0.1:55690", "[fd00::1]:4317", "[fd00::1]:55690"}, endpoints)
+}
+
+func TestK8sResolverWatchesChanges(t *testing.T) {
+	// prepare
+	client := fake.NewSimpleClientset(endpointsObject("lb", "default", []string{"10.0.0.1"}, nil))
+	res, err := newK8sResolver(client, zap.NewNop(), "lb", nil)
+	require.NoError(t, err)
+
+	changes := make(chan []string, 10)
+	res.onChange(func(endpoints []string) {
+		changes <- endpoints
+	})
+	require.NoError(t, res.start(context.Background()))
+	defer func() {
+		require.NoError(t, res.shutdown(context.Background()))
+	}()
+	assert.Equal(t, []string{"10.0.0.1"}, <-changes)
+
+	// test
+	updated := endpointsObject("lb", "default", []string{"10.0.0.1", "10.0.0.2"}, nil)
+	_, err = client.CoreV1().Endpoints("default").Update(context.Background(), updated, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// verify
+	select {
+	case endpoints := <-changes:
+		assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, endpoints)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the update of the endpoints wasn't propagated")
+	}
+
+	// test
+	require.NoError(t, client.CoreV1().Endpoints("default").Delete(context.Background(), "lb", metav1.DeleteOptions{}))
+
+	// verify
+	select {
+	case endpoints := <-changes:
+		assert.Empty(t, endpoints)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the deletion of the endpoints wasn't propagated")
+	}
+}
+
+func TestK8sResolverNoCallbacksAfterShutdown(t *testing.T) {
+	// prepare
+	client := fake.NewSimpleClientset()
+	res, err := newK8sResolver(client, zap.NewNop(), "lb", nil)
+	require.NoError(t, err)
+
+	var count int
+	res.onChange(func([]string) {
+		count++
+	})
+	require.NoError(t, res.start(context.Background()))
+
+	// test
+	require.NoError(t, res.shutdown(context.Background()))
+	res.update([]string{"10.0.0.1"})
+
+	// verify
+	assert.Equal(t, 0, count)
+}
+
+func endpointsObject(name, namespace string, ready []string, notReady []string) *corev1.Endpoints {
+	subset := corev1.EndpointSubset{}
+	for _, ip := range ready {
+		subset.Addresses = append(subset.Addresses, corev1.EndpointAddress{IP: ip})
+	}
+	for _, ip := range notReady {
+		subset.NotReadyAddresses = append(subset.NotReadyAddresses, corev1.EndpointAddress{IP: ip})
+	}
+	return &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Subsets:    []corev1.EndpointSubset{subset},
+	}
+}
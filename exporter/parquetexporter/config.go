@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquetexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/parquetexporter"
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/collector/config"
+)
+
+const (
+	compressionNone   = "none"
+	compressionSnappy = "snappy"
+	compressionGzip   = "gzip"
+	compressionZstd   = "zstd"
+)
+
+// Config defines configuration for the Parquet exporter.
+type Config struct {
+	config.ExporterSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+
+	// Path is the directory the Parquet files are written to. It is created if it does not exist.
+	Path string `mapstructure:"path"`
+
+	// Compression is the codec used for the column chunks, one of "none", "snappy", "gzip" or "zstd".
+	Compression string `mapstructure:"compression"`
+
+	// MaxFileSize is the approximate size in bytes after which the current file is closed and a new one is started.
+	MaxFileSize int64 `mapstructure:"max_file_size"`
+
+	// MaxFileAge is the duration after which the current file is closed and a new one is started,
+	// regardless of its size. Files only become readable once they are closed.
+	MaxFileAge time.Duration `mapstructure:"max_file_age"`
+
+	// RowGroupSize is the approximate size in bytes of the row groups buffered in memory before they are written.
+	RowGroupSize int64 `mapstructure:"row_group_size"`
+}
+
+var _ config.Exporter = (*Config)(nil)
+
+// Validate checks if the exporter configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.Path == "" {
+		return errors.New("path must be non-empty")
+	}
+	switch cfg.Compression {
+	case compressionNone, compressionSnappy, compressionGzip, compressionZstd:
+	default:
+		return fmt.Errorf("unsupported compression %q", cfg.Compression)
+	}
+	if cfg.MaxFileSize <= 0 {
+		return errors.New("max_file_size must be positive")
+	}
+	if cfg.MaxFileAge <= 0 {
+		return errors.New("max_file_age must be positive")
+	}
+	if cfg.RowGroupSize <= 0 {
+		return errors.New("row_group_size must be positive")
+	}
+	if cfg.RowGroupSize > cfg.MaxFileSize {
+		return errors.New("row_group_size must not be larger than max_file_size")
+	}
+	return nil
+}
@@ -16,33 +16,76 @@ package parquetexporter // import "github.com/open-telemetry/opentelemetry-colle
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
 )
 
+// maxRollCheckInterval is the longest interval between two checks of the age of the current file.
+const maxRollCheckInterval = time.Second
+
 type parquetExporter struct {
-	path string
+	logger *zap.Logger
+	cfg    *Config
+	writer *rollingWriter
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
 }
 
-func (e parquetExporter) start(ctx context.Context, host component.Host) error {
-	return nil
+func newParquetExporter(cfg *Config, logger *zap.Logger, prefix string, schema interface{}) *parquetExporter {
+	return &parquetExporter{
+		logger: logger,
+		cfg:    cfg,
+		writer: newRollingWriter(cfg, prefix, schema),
+		stopCh: make(chan struct{}),
+	}
 }
 
-func (e parquetExporter) shutdown(ctx context.Context) error {
+func (e *parquetExporter) start(_ context.Context, _ component.Host) error {
+	interval := e.cfg.MaxFileAge
+	if interval > maxRollCheckInterval {
+		interval = maxRollCheckInterval
+	}
+
+	e.wg.Add(1)
+	go func() {
+		defer e.wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := e.writer.rollIfExpired(); err != nil {
+					e.logger.Error("Failed to close Parquet file", zap.Error(err))
+				}
+			case <-e.stopCh:
+				return
+			}
+		}
+	}()
 	return nil
 }
 
-func (e parquetExporter) consumeMetrics(ctx context.Context, ld pmetric.Metrics) error {
-	return nil
+func (e *parquetExporter) shutdown(_ context.Context) error {
+	close(e.stopCh)
+	e.wg.Wait()
+	return e.writer.close()
 }
 
-func (e parquetExporter) consumeTraces(ctx context.Context, ld ptrace.Traces) error {
-	return nil
+func (e *parquetExporter) consumeMetrics(_ context.Context, md pmetric.Metrics) error {
+	return e.writer.write(metricsToRows(md))
 }
 
-func (e parquetExporter) consumeLogs(ctx context.Context, ld plog.Logs) error {
-	return nil
+func (e *parquetExporter) consumeTraces(_ context.Context, td ptrace.Traces) error {
+	return e.writer.write(tracesToRows(td))
+}
+
+func (e *parquetExporter) consumeLogs(_ context.Context, ld plog.Logs) error {
+	return e.writer.write(logsToRows(ld))
 }
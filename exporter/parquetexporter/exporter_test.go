@@ -0,0 +1,283 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquetexporter
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xitongsys/parquet-go-source/local"
+	"github.com/xitongsys/parquet-go/reader"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+func testConfig(t *testing.T) *Config {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = t.TempDir()
+	return cfg
+}
+
+func parquetFiles(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*"+fileExtension))
+	require.NoError(t, err)
+	return files
+}
+
+func readRows[T any](t *testing.T, path string) []T {
+	fr, err := local.NewLocalFileReader(path)
+	require.NoError(t, err)
+	defer fr.Close()
+
+	pr, err := reader.NewParquetReader(fr, new(T), 1)
+	require.NoError(t, err)
+	defer pr.ReadStop()
+
+	rows := make([]T, pr.GetNumRows())
+	require.NoError(t, pr.Read(&rows))
+	return rows
+}
+
+func TestConsumeTraces(t *testing.T) {
+	cfg := testConfig(t)
+	exp := newParquetExporter(cfg, zap.NewNop(), "traces", new(spanRow))
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().UpsertString("service.name", "checkout")
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Scope().SetName("tracer")
+	span := ss.Spans().AppendEmpty()
+	span.SetTraceID(pcommon.NewTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}))
+	span.SetSpanID(pcommon.NewSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8}))
+	span.SetName("GET /cart")
+	span.SetKind(ptrace.SpanKindServer)
+	span.SetStartTimestamp(1000)
+	span.SetEndTimestamp(3500)
+	span.Attributes().UpsertInt("http.status_code", 200)
+	span.Status().SetCode(ptrace.StatusCodeOk)
+	event := span.Events().AppendEmpty()
+	event.SetName("cache miss")
+	event.SetTimestamp(2000)
+	link := span.Links().AppendEmpty()
+	link.SetTraceID(pcommon.NewTraceID([16]byte{16}))
+	link.SetSpanID(pcommon.NewSpanID([8]byte{8}))
+
+	require.NoError(t, exp.consumeTraces(context.Background(), td))
+	require.NoError(t, exp.shutdown(context.Background()))
+
+	files := parquetFiles(t, cfg.Path)
+	require.Len(t, files, 1)
+	rows := readRows[spanRow](t, files[0])
+	require.Len(t, rows, 1)
+	row := rows[0]
+	assert.Equal(t, map[string]string{"service.name": "checkout"}, row.ResourceAttributes)
+	assert.Equal(t, "tracer", row.ScopeName)
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", row.TraceID)
+	assert.Equal(t, "0102030405060708", row.SpanID)
+	assert.Equal(t, "", row.ParentSpanID)
+	assert.Equal(t, "GET /cart", row.Name)
+	assert.Equal(t, "SPAN_KIND_SERVER", row.Kind)
+	assert.Equal(t, int64(1000), row.StartTime)
+	assert.Equal(t, int64(3500), row.EndTime)
+	assert.Equal(t, int64(2500), row.DurationNanos)
+	assert.Equal(t, "STATUS_CODE_OK", row.StatusCode)
+	assert.Equal(t, map[string]string{"http.status_code": "200"}, row.Attributes)
+	require.Len(t, row.Events, 1)
+	assert.Equal(t, "cache miss", row.Events[0].Name)
+	assert.Equal(t, int64(2000), row.Events[0].Time)
+	require.Len(t, row.Links, 1)
+	assert.Equal(t, "10000000000000000000000000000000", row.Links[0].TraceID)
+}
+
+func TestConsumeMetrics(t *testing.T) {
+	cfg := testConfig(t)
+	exp := newParquetExporter(cfg, zap.NewNop(), "metrics", new(metricRow))
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().UpsertString("host.name", "edge-1")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("cpu.utilization")
+	gauge.SetEmptyGauge().DataPoints().AppendEmpty().SetDoubleVal(0.5)
+
+	sum := metrics.AppendEmpty()
+	sum.SetName("requests")
+	sum.SetEmptySum().SetIsMonotonic(true)
+	sum.Sum().SetAggregationTemporality(pmetric.MetricAggregationTemporalityCumulative)
+	sdp := sum.Sum().DataPoints().AppendEmpty()
+	sdp.SetIntVal(42)
+	sdp.Attributes().UpsertString("method", "GET")
+
+	histogram := metrics.AppendEmpty()
+	histogram.SetName("latency")
+	hdp := histogram.SetEmptyHistogram().DataPoints().AppendEmpty()
+	hdp.SetCount(3)
+	hdp.SetSum(12)
+	hdp.BucketCounts().FromRaw([]uint64{1, 2})
+	hdp.ExplicitBounds().FromRaw([]float64{5})
+
+	summary := metrics.AppendEmpty()
+	summary.SetName("gc.pause")
+	qv := summary.SetEmptySummary().DataPoints().AppendEmpty().QuantileValues().AppendEmpty()
+	qv.SetQuantile(0.99)
+	qv.SetValue(7)
+
+	require.NoError(t, exp.consumeMetrics(context.Background(), md))
+	require.NoError(t, exp.shutdown(context.Background()))
+
+	files := parquetFiles(t, cfg.Path)
+	require.Len(t, files, 1)
+	rows := readRows[metricRow](t, files[0])
+	require.Len(t, rows, 4)
+
+	assert.Equal(t, "cpu.utilization", rows[0].MetricName)
+	assert.Equal(t, "Gauge", rows[0].MetricType)
+	require.NotNil(t, rows[0].ValueDouble)
+	assert.Equal(t, 0.5, *rows[0].ValueDouble)
+	assert.Nil(t, rows[0].ValueInt)
+	assert.Nil(t, rows[0].AggregationTemporality)
+
+	assert.Equal(t, "requests", rows[1].MetricName)
+	require.NotNil(t, rows[1].ValueInt)
+	assert.Equal(t, int64(42), *rows[1].ValueInt)
+	require.NotNil(t, rows[1].IsMonotonic)
+	assert.True(t, *rows[1].IsMonotonic)
+	require.NotNil(t, rows[1].AggregationTemporality)
+	assert.Equal(t, "AGGREGATION_TEMPORALITY_CUMULATIVE", *rows[1].AggregationTemporality)
+	assert.Equal(t, map[string]string{"method": "GET"}, rows[1].Attributes)
+
+	assert.Equal(t, "latency", rows[2].MetricName)
+	require.NotNil(t, rows[2].Count)
+	assert.Equal(t, int64(3), *rows[2].Count)
+	require.NotNil(t, rows[2].Sum)
+	assert.Equal(t, 12.0, *rows[2].Sum)
+	assert.Nil(t, rows[2].Min)
+	assert.Equal(t, []int64{1, 2}, rows[2].BucketCounts)
+	assert.Equal(t, []float64{5}, rows[2].ExplicitBounds)
+
+	assert.Equal(t, "gc.pause", rows[3].MetricName)
+	assert.Equal(t, []quantileRow{{Quantile: 0.99, Value: 7}}, rows[3].Quantiles)
+
+	for _, row := range rows {
+		assert.Equal(t, map[string]string{"host.name": "edge-1"}, row.ResourceAttributes)
+	}
+}
+
+func TestConsumeLogs(t *testing.T) {
+	cfg := testConfig(t)
+	exp := newParquetExporter(cfg, zap.NewNop(), "logs", new(logRow))
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().UpsertString("service.name", "checkout")
+	logs := rl.ScopeLogs().AppendEmpty().LogRecords()
+	lr := logs.AppendEmpty()
+	lr.SetTimestamp(1234)
+	lr.SetSeverityNumber(plog.SeverityNumberWarn)
+	lr.SetSeverityText("WARN")
+	lr.Body().SetStringVal("disk almost full")
+	lr.Attributes().UpsertBool("retry", true)
+	mapBody := logs.AppendEmpty()
+	mapBody.Body().SetEmptyMapVal().UpsertString("event", "login")
+
+	require.NoError(t, exp.consumeLogs(context.Background(), ld))
+	require.NoError(t, exp.shutdown(context.Background()))
+
+	files := parquetFiles(t, cfg.Path)
+	require.Len(t, files, 1)
+	rows := readRows[logRow](t, files[0])
+	require.Len(t, rows, 2)
+	assert.Equal(t, int64(1234), rows[0].Time)
+	assert.Equal(t, int32(plog.SeverityNumberWarn), rows[0].SeverityNumber)
+	assert.Equal(t, "WARN", rows[0].SeverityText)
+	assert.Equal(t, "disk almost full", rows[0].Body)
+	assert.Equal(t, map[string]string{"retry": "true"}, rows[0].Attributes)
+	assert.Equal(t, map[string]string{"service.name": "checkout"}, rows[0].ResourceAttributes)
+	assert.Equal(t, `{"event":"login"}`, rows[1].Body)
+}
+
+func TestRollOnSize(t *testing.T) {
+	cfg := testConfig(t)
+	cfg.MaxFileSize = 1
+	cfg.RowGroupSize = 1
+	exp := newParquetExporter(cfg, zap.NewNop(), "logs", new(logRow))
+	require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+
+	for i := 0; i < 3; i++ {
+		ld := plog.NewLogs()
+		ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStringVal("line")
+		require.NoError(t, exp.consumeLogs(context.Background(), ld))
+	}
+	assert.Len(t, parquetFiles(t, cfg.Path), 3)
+
+	require.NoError(t, exp.shutdown(context.Background()))
+	assert.Len(t, parquetFiles(t, cfg.Path), 3)
+}
+
+func TestRollOnAge(t *testing.T) {
+	cfg := testConfig(t)
+	exp := newParquetExporter(cfg, zap.NewNop(), "logs", new(logRow))
+	now := time.Now()
+	exp.writer.now = func() time.Time { return now }
+
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStringVal("line")
+	require.NoError(t, exp.consumeLogs(context.Background(), ld))
+
+	require.NoError(t, exp.writer.rollIfExpired())
+	assert.Empty(t, parquetFiles(t, cfg.Path), "file should be in progress until it expires")
+
+	now = now.Add(cfg.MaxFileAge)
+	require.NoError(t, exp.writer.rollIfExpired())
+	files := parquetFiles(t, cfg.Path)
+	require.Len(t, files, 1)
+	assert.Len(t, readRows[logRow](t, files[0]), 1)
+}
+
+func TestDoNotOverwritePreviousFiles(t *testing.T) {
+	cfg := testConfig(t)
+	now := time.Now()
+	ld := plog.NewLogs()
+	ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStringVal("line")
+
+	// Two processes started within the same second write to the same directory.
+	for i := 0; i < 2; i++ {
+		exp := newParquetExporter(cfg, zap.NewNop(), "logs", new(logRow))
+		exp.writer.now = func() time.Time { return now }
+		require.NoError(t, exp.start(context.Background(), componenttest.NewNopHost()))
+		require.NoError(t, exp.consumeLogs(context.Background(), ld))
+		require.NoError(t, exp.shutdown(context.Background()))
+	}
+
+	files := parquetFiles(t, cfg.Path)
+	require.Len(t, files, 2)
+	for _, file := range files {
+		assert.Len(t, readRows[logRow](t, file), 1)
+	}
+}
@@ -16,6 +16,7 @@ package parquetexporter // import "github.com/open-telemetry/opentelemetry-colle
 
 import (
 	"context"
+	"time"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config"
@@ -27,12 +28,11 @@ const (
 	typeStr = "parquet"
 	// The stability level of the exporter.
 	stability = component.StabilityLevelInDevelopment
-)
 
-type Config struct {
-	config.ExporterSettings `mapstructure:",squash"`
-	Path                    string `mapstructure:"path"`
-}
+	defaultMaxFileSize  = 128 * 1024 * 1024
+	defaultMaxFileAge   = 5 * time.Minute
+	defaultRowGroupSize = 32 * 1024 * 1024
+)
 
 // NewFactory creates a factory for the Parquet exporter.
 func NewFactory() component.ExporterFactory {
@@ -47,6 +47,10 @@ func NewFactory() component.ExporterFactory {
 func createDefaultConfig() config.Exporter {
 	return &Config{
 		ExporterSettings: config.NewExporterSettings(config.NewComponentID(typeStr)),
+		Compression:      compressionSnappy,
+		MaxFileSize:      defaultMaxFileSize,
+		MaxFileAge:       defaultMaxFileAge,
+		RowGroupSize:     defaultRowGroupSize,
 	}
 }
 
@@ -55,7 +59,7 @@ func createTracesExporter(
 	set component.ExporterCreateSettings,
 	cfg config.Exporter,
 ) (component.TracesExporter, error) {
-	fe := &parquetExporter{path: cfg.(*Config).Path}
+	fe := newParquetExporter(cfg.(*Config), set.Logger, "traces", new(spanRow))
 	return exporterhelper.NewTracesExporter(
 		ctx,
 		set,
@@ -71,7 +75,7 @@ func createMetricsExporter(
 	set component.ExporterCreateSettings,
 	cfg config.Exporter,
 ) (component.MetricsExporter, error) {
-	fe := &parquetExporter{path: cfg.(*Config).Path}
+	fe := newParquetExporter(cfg.(*Config), set.Logger, "metrics", new(metricRow))
 	return exporterhelper.NewMetricsExporter(
 		ctx,
 		set,
@@ -87,7 +91,7 @@ func createLogsExporter(
 	set component.ExporterCreateSettings,
 	cfg config.Exporter,
 ) (component.LogsExporter, error) {
-	fe := &parquetExporter{path: cfg.(*Config).Path}
+	fe := newParquetExporter(cfg.(*Config), set.Logger, "logs", new(logRow))
 	return exporterhelper.NewLogsExporter(
 		ctx,
 		set,
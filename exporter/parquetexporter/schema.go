@@ -0,0 +1,410 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquetexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/parquetexporter"
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// The row types below define the Parquet schema of each signal. Every row is
+// self-contained: resource and instrumentation scope information is repeated
+// on each row so files can be queried without joins. Attribute values are
+// stored as their string representation, see pcommon.Value.AsString.
+
+// spanRow is the schema of the traces files, one row per span.
+type spanRow struct {
+	ResourceAttributes     map[string]string `parquet:"name=resource_attributes, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	ScopeName              string            `parquet:"name=scope_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ScopeVersion           string            `parquet:"name=scope_version, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TraceID                string            `parquet:"name=trace_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	SpanID                 string            `parquet:"name=span_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ParentSpanID           string            `parquet:"name=parent_span_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TraceState             string            `parquet:"name=trace_state, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Name                   string            `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Kind                   string            `parquet:"name=kind, type=BYTE_ARRAY, convertedtype=UTF8"`
+	StartTime              int64             `parquet:"name=start_time, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=NANOS"`
+	EndTime                int64             `parquet:"name=end_time, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=NANOS"`
+	DurationNanos          int64             `parquet:"name=duration_nanos, type=INT64"`
+	StatusCode             string            `parquet:"name=status_code, type=BYTE_ARRAY, convertedtype=UTF8"`
+	StatusMessage          string            `parquet:"name=status_message, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Attributes             map[string]string `parquet:"name=attributes, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	DroppedAttributesCount int64             `parquet:"name=dropped_attributes_count, type=INT64"`
+	Events                 []spanEventRow    `parquet:"name=events, type=LIST"`
+	DroppedEventsCount     int64             `parquet:"name=dropped_events_count, type=INT64"`
+	Links                  []spanLinkRow     `parquet:"name=links, type=LIST"`
+	DroppedLinksCount      int64             `parquet:"name=dropped_links_count, type=INT64"`
+}
+
+// spanEventRow is the schema of the elements of the spanRow.Events list.
+type spanEventRow struct {
+	Time       int64             `parquet:"name=time, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=NANOS"`
+	Name       string            `parquet:"name=name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Attributes map[string]string `parquet:"name=attributes, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+}
+
+// spanLinkRow is the schema of the elements of the spanRow.Links list.
+type spanLinkRow struct {
+	TraceID    string            `parquet:"name=trace_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	SpanID     string            `parquet:"name=span_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	TraceState string            `parquet:"name=trace_state, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Attributes map[string]string `parquet:"name=attributes, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+}
+
+// metricRow is the schema of the metrics files, one row per data point.
+// Columns that do not apply to the metric type of a row are null.
+type metricRow struct {
+	ResourceAttributes     map[string]string `parquet:"name=resource_attributes, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	ScopeName              string            `parquet:"name=scope_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ScopeVersion           string            `parquet:"name=scope_version, type=BYTE_ARRAY, convertedtype=UTF8"`
+	MetricName             string            `parquet:"name=metric_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	MetricDescription      string            `parquet:"name=metric_description, type=BYTE_ARRAY, convertedtype=UTF8"`
+	MetricUnit             string            `parquet:"name=metric_unit, type=BYTE_ARRAY, convertedtype=UTF8"`
+	MetricType             string            `parquet:"name=metric_type, type=BYTE_ARRAY, convertedtype=UTF8"`
+	AggregationTemporality *string           `parquet:"name=aggregation_temporality, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	IsMonotonic            *bool             `parquet:"name=is_monotonic, type=BOOLEAN, repetitiontype=OPTIONAL"`
+	StartTime              int64             `parquet:"name=start_time, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=NANOS"`
+	Time                   int64             `parquet:"name=time, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=NANOS"`
+	Attributes             map[string]string `parquet:"name=attributes, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	Flags                  int64             `parquet:"name=flags, type=INT64"`
+	ValueDouble            *float64          `parquet:"name=value_double, type=DOUBLE, repetitiontype=OPTIONAL"`
+	ValueInt               *int64            `parquet:"name=value_int, type=INT64, repetitiontype=OPTIONAL"`
+	Count                  *int64            `parquet:"name=count, type=INT64, repetitiontype=OPTIONAL"`
+	Sum                    *float64          `parquet:"name=sum, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Min                    *float64          `parquet:"name=min, type=DOUBLE, repetitiontype=OPTIONAL"`
+	Max                    *float64          `parquet:"name=max, type=DOUBLE, repetitiontype=OPTIONAL"`
+	BucketCounts           []int64           `parquet:"name=bucket_counts, type=LIST, valuetype=INT64"`
+	ExplicitBounds         []float64         `parquet:"name=explicit_bounds, type=LIST, valuetype=DOUBLE"`
+	Scale                  *int32            `parquet:"name=scale, type=INT32, repetitiontype=OPTIONAL"`
+	ZeroCount              *int64            `parquet:"name=zero_count, type=INT64, repetitiontype=OPTIONAL"`
+	PositiveOffset         *int32            `parquet:"name=positive_offset, type=INT32, repetitiontype=OPTIONAL"`
+	PositiveBucketCounts   []int64           `parquet:"name=positive_bucket_counts, type=LIST, valuetype=INT64"`
+	NegativeOffset         *int32            `parquet:"name=negative_offset, type=INT32, repetitiontype=OPTIONAL"`
+	NegativeBucketCounts   []int64           `parquet:"name=negative_bucket_counts, type=LIST, valuetype=INT64"`
+	Quantiles              []quantileRow     `parquet:"name=quantiles, type=LIST"`
+}
+
+// quantileRow is the schema of the elements of the metricRow.Quantiles list.
+type quantileRow struct {
+	Quantile float64 `parquet:"name=quantile, type=DOUBLE"`
+	Value    float64 `parquet:"name=value, type=DOUBLE"`
+}
+
+// logRow is the schema of the logs files, one row per log record.
+type logRow struct {
+	ResourceAttributes     map[string]string `parquet:"name=resource_attributes, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	ScopeName              string            `parquet:"name=scope_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ScopeVersion           string            `parquet:"name=scope_version, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Time                   int64             `parquet:"name=time, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=NANOS"`
+	ObservedTime           int64             `parquet:"name=observed_time, type=INT64, logicaltype=TIMESTAMP, logicaltype.isadjustedtoutc=true, logicaltype.unit=NANOS"`
+	TraceID                string            `parquet:"name=trace_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	SpanID                 string            `parquet:"name=span_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Flags                  int64             `parquet:"name=flags, type=INT64"`
+	SeverityText           string            `parquet:"name=severity_text, type=BYTE_ARRAY, convertedtype=UTF8"`
+	SeverityNumber         int32             `parquet:"name=severity_number, type=INT32"`
+	Body                   string            `parquet:"name=body, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Attributes             map[string]string `parquet:"name=attributes, type=MAP, convertedtype=MAP, keytype=BYTE_ARRAY, keyconvertedtype=UTF8, valuetype=BYTE_ARRAY, valueconvertedtype=UTF8"`
+	DroppedAttributesCount int64             `parquet:"name=dropped_attributes_count, type=INT64"`
+}
+
+func attributesToMap(attrs pcommon.Map) map[string]string {
+	m := make(map[string]string, attrs.Len())
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		m[k] = v.AsString()
+		return true
+	})
+	return m
+}
+
+func traceIDToString(id pcommon.TraceID) string {
+	if id.IsEmpty() {
+		return ""
+	}
+	return id.HexString()
+}
+
+func spanIDToString(id pcommon.SpanID) string {
+	if id.IsEmpty() {
+		return ""
+	}
+	return id.HexString()
+}
+
+func uint64SliceToRow(s pcommon.UInt64Slice) []int64 {
+	r := make([]int64, s.Len())
+	for i := 0; i < s.Len(); i++ {
+		r[i] = int64(s.At(i))
+	}
+	return r
+}
+
+func tracesToRows(td ptrace.Traces) []interface{} {
+	var rows []interface{}
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		resourceAttrs := attributesToMap(rs.Resource().Attributes())
+		ilss := rs.ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			ils := ilss.At(j)
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				rows = append(rows, spanToRow(resourceAttrs, ils.Scope(), spans.At(k)))
+			}
+		}
+	}
+	return rows
+}
+
+func spanToRow(resourceAttrs map[string]string, scope pcommon.InstrumentationScope, span ptrace.Span) spanRow {
+	row := spanRow{
+		ResourceAttributes:     resourceAttrs,
+		ScopeName:              scope.Name(),
+		ScopeVersion:           scope.Version(),
+		TraceID:                traceIDToString(span.TraceID()),
+		SpanID:                 spanIDToString(span.SpanID()),
+		ParentSpanID:           spanIDToString(span.ParentSpanID()),
+		TraceState:             span.TraceStateStruct().AsRaw(),
+		Name:                   span.Name(),
+		Kind:                   span.Kind().String(),
+		StartTime:              int64(span.StartTimestamp()),
+		EndTime:                int64(span.EndTimestamp()),
+		DurationNanos:          int64(span.EndTimestamp()) - int64(span.StartTimestamp()),
+		StatusCode:             span.Status().Code().String(),
+		StatusMessage:          span.Status().Message(),
+		Attributes:             attributesToMap(span.Attributes()),
+		DroppedAttributesCount: int64(span.DroppedAttributesCount()),
+		DroppedEventsCount:     int64(span.DroppedEventsCount()),
+		DroppedLinksCount:      int64(span.DroppedLinksCount()),
+	}
+	events := span.Events()
+	row.Events = make([]spanEventRow, events.Len())
+	for i := 0; i < events.Len(); i++ {
+		event := events.At(i)
+		row.Events[i] = spanEventRow{
+			Time:       int64(event.Timestamp()),
+			Name:       event.Name(),
+			Attributes: attributesToMap(event.Attributes()),
+		}
+	}
+	links := span.Links()
+	row.Links = make([]spanLinkRow, links.Len())
+	for i := 0; i < links.Len(); i++ {
+		link := links.At(i)
+		row.Links[i] = spanLinkRow{
+			TraceID:    traceIDToString(link.TraceID()),
+			SpanID:     spanIDToString(link.SpanID()),
+			TraceState: link.TraceStateStruct().AsRaw(),
+			Attributes: attributesToMap(link.Attributes()),
+		}
+	}
+	return row
+}
+
+func logsToRows(ld plog.Logs) []interface{} {
+	var rows []interface{}
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		resourceAttrs := attributesToMap(rl.Resource().Attributes())
+		slls := rl.ScopeLogs()
+		for j := 0; j < slls.Len(); j++ {
+			sl := slls.At(j)
+			logs := sl.LogRecords()
+			for k := 0; k < logs.Len(); k++ {
+				rows = append(rows, logToRow(resourceAttrs, sl.Scope(), logs.At(k)))
+			}
+		}
+	}
+	return rows
+}
+
+func logToRow(resourceAttrs map[string]string, scope pcommon.InstrumentationScope, lr plog.LogRecord) logRow {
+	return logRow{
+		ResourceAttributes:     resourceAttrs,
+		ScopeName:              scope.Name(),
+		ScopeVersion:           scope.Version(),
+		Time:                   int64(lr.Timestamp()),
+		ObservedTime:           int64(lr.ObservedTimestamp()),
+		TraceID:                traceIDToString(lr.TraceID()),
+		SpanID:                 spanIDToString(lr.SpanID()),
+		Flags:                  int64(lr.Flags()),
+		SeverityText:           lr.SeverityText(),
+		SeverityNumber:         int32(lr.SeverityNumber()),
+		Body:                   lr.Body().AsString(),
+		Attributes:             attributesToMap(lr.Attributes()),
+		DroppedAttributesCount: int64(lr.DroppedAttributesCount()),
+	}
+}
+
+func metricsToRows(md pmetric.Metrics) []interface{} {
+	var rows []interface{}
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		resourceAttrs := attributesToMap(rm.Resource().Attributes())
+		smss := rm.ScopeMetrics()
+		for j := 0; j < smss.Len(); j++ {
+			sm := smss.At(j)
+			metrics := sm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				base := metricRow{
+					ResourceAttributes: resourceAttrs,
+					ScopeName:          sm.Scope().Name(),
+					ScopeVersion:       sm.Scope().Version(),
+				}
+				rows = appendMetricRows(rows, base, metrics.At(k))
+			}
+		}
+	}
+	return rows
+}
+
+// appendMetricRows appends one row per data point of the metric to rows,
+// using base for the resource and scope columns.
+func appendMetricRows(rows []interface{}, base metricRow, metric pmetric.Metric) []interface{} {
+	base.MetricName = metric.Name()
+	base.MetricDescription = metric.Description()
+	base.MetricUnit = metric.Unit()
+	base.MetricType = metric.DataType().String()
+
+	switch metric.DataType() {
+	case pmetric.MetricDataTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			rows = append(rows, numberDataPointToRow(base, dps.At(i)))
+		}
+	case pmetric.MetricDataTypeSum:
+		temporality := metric.Sum().AggregationTemporality().String()
+		monotonic := metric.Sum().IsMonotonic()
+		base.AggregationTemporality = &temporality
+		base.IsMonotonic = &monotonic
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			rows = append(rows, numberDataPointToRow(base, dps.At(i)))
+		}
+	case pmetric.MetricDataTypeHistogram:
+		temporality := metric.Histogram().AggregationTemporality().String()
+		base.AggregationTemporality = &temporality
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			rows = append(rows, histogramDataPointToRow(base, dps.At(i)))
+		}
+	case pmetric.MetricDataTypeExponentialHistogram:
+		temporality := metric.ExponentialHistogram().AggregationTemporality().String()
+		base.AggregationTemporality = &temporality
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			rows = append(rows, exponentialHistogramDataPointToRow(base, dps.At(i)))
+		}
+	case pmetric.MetricDataTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			rows = append(rows, summaryDataPointToRow(base, dps.At(i)))
+		}
+	}
+	return rows
+}
+
+func numberDataPointToRow(row metricRow, dp pmetric.NumberDataPoint) metricRow {
+	row.StartTime = int64(dp.StartTimestamp())
+	row.Time = int64(dp.Timestamp())
+	row.Attributes = attributesToMap(dp.Attributes())
+	row.Flags = int64(dp.Flags())
+	switch dp.ValueType() {
+	case pmetric.NumberDataPointValueTypeDouble:
+		v := dp.DoubleVal()
+		row.ValueDouble = &v
+	case pmetric.NumberDataPointValueTypeInt:
+		v := dp.IntVal()
+		row.ValueInt = &v
+	}
+	return row
+}
+
+func histogramDataPointToRow(row metricRow, dp pmetric.HistogramDataPoint) metricRow {
+	row.StartTime = int64(dp.StartTimestamp())
+	row.Time = int64(dp.Timestamp())
+	row.Attributes = attributesToMap(dp.Attributes())
+	row.Flags = int64(dp.Flags())
+	count := int64(dp.Count())
+	row.Count = &count
+	if dp.HasSum() {
+		v := dp.Sum()
+		row.Sum = &v
+	}
+	if dp.HasMin() {
+		v := dp.Min()
+		row.Min = &v
+	}
+	if dp.HasMax() {
+		v := dp.Max()
+		row.Max = &v
+	}
+	row.BucketCounts = uint64SliceToRow(dp.BucketCounts())
+	row.ExplicitBounds = dp.ExplicitBounds().AsRaw()
+	return row
+}
+
+func exponentialHistogramDataPointToRow(row metricRow, dp pmetric.ExponentialHistogramDataPoint) metricRow {
+	row.StartTime = int64(dp.StartTimestamp())
+	row.Time = int64(dp.Timestamp())
+	row.Attributes = attributesToMap(dp.Attributes())
+	row.Flags = int64(dp.Flags())
+	count := int64(dp.Count())
+	row.Count = &count
+	if dp.HasSum() {
+		v := dp.Sum()
+		row.Sum = &v
+	}
+	if dp.HasMin() {
+		v := dp.Min()
+		row.Min = &v
+	}
+	if dp.HasMax() {
+		v := dp.Max()
+		row.Max = &v
+	}
+	scale := dp.Scale()
+	zeroCount := int64(dp.ZeroCount())
+	positiveOffset := dp.Positive().Offset()
+	negativeOffset := dp.Negative().Offset()
+	row.Scale = &scale
+	row.ZeroCount = &zeroCount
+	row.PositiveOffset = &positiveOffset
+	row.PositiveBucketCounts = uint64SliceToRow(dp.Positive().BucketCounts())
+	row.NegativeOffset = &negativeOffset
+	row.NegativeBucketCounts = uint64SliceToRow(dp.Negative().BucketCounts())
+	return row
+}
+
+func summaryDataPointToRow(row metricRow, dp pmetric.SummaryDataPoint) metricRow {
+	row.StartTime = int64(dp.StartTimestamp())
+	row.Time = int64(dp.Timestamp())
+	row.Attributes = attributesToMap(dp.Attributes())
+	row.Flags = int64(dp.Flags())
+	count := int64(dp.Count())
+	sum := dp.Sum()
+	row.Count = &count
+	row.Sum = &sum
+	qvs := dp.QuantileValues()
+	row.Quantiles = make([]quantileRow, qvs.Len())
+	for i := 0; i < qvs.Len(); i++ {
+		row.Quantiles[i] = quantileRow{Quantile: qvs.At(i).Quantile(), Value: qvs.At(i).Value()}
+	}
+	return row
+}
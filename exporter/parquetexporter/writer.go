@@ -0,0 +1,181 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parquetexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/parquetexporter"
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+	"go.uber.org/multierr"
+)
+
+const (
+	fileExtension = ".parquet"
+	// inProgressSuffix is appended to the name of files that are still being
+	// written. Parquet files are only readable once their footer is written,
+	// so files are renamed to their final name when they are closed.
+	inProgressSuffix = ".tmp"
+)
+
+var compressionCodecs = map[string]parquet.CompressionCodec{
+	compressionNone:   parquet.CompressionCodec_UNCOMPRESSED,
+	compressionSnappy: parquet.CompressionCodec_SNAPPY,
+	compressionGzip:   parquet.CompressionCodec_GZIP,
+	compressionZstd:   parquet.CompressionCodec_ZSTD,
+}
+
+// rollingWriter writes rows of a single schema to a sequence of Parquet files
+// in a directory, starting a new file whenever the current one exceeds the
+// configured size or age.
+type rollingWriter struct {
+	dir    string
+	prefix string
+	schema interface{}
+	cfg    *Config
+	now    func() time.Time
+
+	mu       sync.Mutex
+	file     *os.File
+	pw       *writer.ParquetWriter
+	openedAt time.Time
+	seq      int
+}
+
+func newRollingWriter(cfg *Config, prefix string, schema interface{}) *rollingWriter {
+	return &rollingWriter{
+		dir:    cfg.Path,
+		prefix: prefix,
+		schema: schema,
+		cfg:    cfg,
+		now:    time.Now,
+	}
+}
+
+// write appends rows to the current file, opening a new one first if needed.
+func (w *rollingWriter) write(rows []interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.pw == nil {
+		if err := w.open(); err != nil {
+			return err
+		}
+	}
+	for _, row := range rows {
+		if err := w.pw.Write(row); err != nil {
+			return err
+		}
+	}
+	if w.size() >= w.cfg.MaxFileSize {
+		return w.closeFile()
+	}
+	return nil
+}
+
+// rollIfExpired closes the current file if it is older than the configured
+// maximum file age.
+func (w *rollingWriter) rollIfExpired() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.pw == nil || w.now().Sub(w.openedAt) < w.cfg.MaxFileAge {
+		return nil
+	}
+	return w.closeFile()
+}
+
+// close closes the current file, if any.
+func (w *rollingWriter) close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.pw == nil {
+		return nil
+	}
+	return w.closeFile()
+}
+
+// size returns an estimate of the size of the current file, including the
+// rows that are still buffered in memory.
+func (w *rollingWriter) size() int64 {
+	return w.pw.Offset + w.pw.Size + w.pw.ObjsSize
+}
+
+func (w *rollingWriter) open() error {
+	if err := os.MkdirAll(w.dir, 0755); err != nil {
+		return err
+	}
+
+	w.openedAt = w.now()
+	f, err := w.createFile()
+	if err != nil {
+		return err
+	}
+
+	pw, err := writer.NewParquetWriterFromWriter(f, w.schema, 1)
+	if err != nil {
+		return multierr.Append(err, f.Close())
+	}
+	pw.CompressionType = compressionCodecs[w.cfg.Compression]
+	pw.RowGroupSize = w.cfg.RowGroupSize
+
+	w.file = f
+	w.pw = pw
+	return nil
+}
+
+// createFile creates the next file of the sequence. The sequence restarts
+// with the process, so names already used by a previous process, either in
+// progress or closed, are skipped rather than overwritten.
+func (w *rollingWriter) createFile() (*os.File, error) {
+	for {
+		w.seq++
+		name := filepath.Join(w.dir, fmt.Sprintf("%s-%s-%d%s",
+			w.prefix, w.openedAt.UTC().Format("20060102T150405Z"), w.seq, fileExtension))
+		if _, err := os.Lstat(name); err == nil {
+			continue
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+		f, err := os.OpenFile(name+inProgressSuffix, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if os.IsExist(err) {
+			continue
+		}
+		return f, err
+	}
+}
+
+func (w *rollingWriter) closeFile() error {
+	f, pw := w.file, w.pw
+	w.file, w.pw = nil, nil
+
+	if err := pw.WriteStop(); err != nil {
+		return multierr.Append(err, f.Close())
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	name := f.Name()
+	return os.Rename(name, name[:len(name)-len(inProgressSuffix)])
+}
@@ -0,0 +1,393 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tracestore keeps the batches of spans of the traces held by a processor in a
+// storage extension, so that they neither use memory nor get lost on restart.
+package tracestore // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/tracestore"
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+const (
+	traceKeyPrefix = "trace_"
+	indexKeyPrefix = "index_"
+	// numIndexBuckets is the number of keys the index of the stored trace IDs is split into,
+	// so that adding or removing a trace only rewrites a small part of the index.
+	numIndexBuckets = 256
+	// indexEntrySize is the size of an index entry, the trace ID.
+	indexEntrySize = 16
+	// metadataSize is the size of the encoded description of a trace: its arrival time in
+	// nanoseconds, its number of batches, its number of spans and its size.
+	metadataSize = 8 + 4 + 4 + 8
+)
+
+var (
+	// ErrFull is returned when storing a batch would exceed the maximum size of the store.
+	ErrFull = errors.New("the trace storage is full")
+	// ErrClosed is returned when the store is used before Start or after Shutdown.
+	ErrClosed = errors.New("the trace storage is not started or already shut down")
+	// ErrCorrupt is returned when batches of a trace cannot be read from the storage.
+	ErrCorrupt = errors.New("the trace cannot be read from the storage")
+)
+
+// Trace describes a stored trace.
+type Trace struct {
+	// ArrivalTime is the arrival time given with the first batch of the trace.
+	ArrivalTime time.Time
+	NumBatches  uint32
+	SpanCount   uint32
+	// Size is the size of the encoded batches of the trace.
+	Size int64
+}
+
+// Store keeps batches of spans grouped by trace in a storage extension.
+//
+// Each batch is stored under its own key, next to a small key describing the trace, so that
+// appending a batch to a known trace only writes these two keys. As the storage client cannot
+// list its keys, the trace IDs are also recorded in an index split into buckets by the first
+// byte of the ID. A bucket is only rewritten when a trace is added or removed, and each bucket
+// has its own lock so that traces of different buckets are written concurrently.
+type Store struct {
+	storageID   config.ComponentID
+	componentID config.ComponentID
+	maxSize     int64
+	marshaler   ptrace.Marshaler
+	unmarshaler ptrace.Unmarshaler
+
+	// size is the total size of the stored batches, it is updated atomically.
+	size int64
+
+	// mu guards the client, it is held for writing only when opening and closing the client.
+	mu      sync.RWMutex
+	client  storage.Client
+	buckets [numIndexBuckets]bucket
+}
+
+type bucket struct {
+	sync.Mutex
+	traces map[pcommon.TraceID]Trace
+}
+
+// New creates a Store using the client of the storage extension storageID for the component
+// componentID. The store must be started before use. A maxSize of zero means no limit.
+func New(storageID, componentID config.ComponentID, maxSize int64) *Store {
+	s := &Store{
+		storageID:   storageID,
+		componentID: componentID,
+		maxSize:     maxSize,
+		marshaler:   ptrace.NewProtoMarshaler(),
+		unmarshaler: ptrace.NewProtoUnmarshaler(),
+	}
+	for i := range s.buckets {
+		s.buckets[i].traces = make(map[pcommon.TraceID]Trace)
+	}
+	return s
+}
+
+// Start opens the storage client and loads the traces stored by a previous run.
+func (s *Store) Start(ctx context.Context, host component.Host) error {
+	ext, ok := host.GetExtensions()[s.storageID]
+	if !ok {
+		return fmt.Errorf("storage extension '%s' not found", s.storageID)
+	}
+	storageExt, ok := ext.(storage.Extension)
+	if !ok {
+		return fmt.Errorf("non-storage extension '%s' found", s.storageID)
+	}
+	client, err := storageExt.GetClient(ctx, component.KindProcessor, s.componentID, "")
+	if err != nil {
+		return err
+	}
+	return s.open(ctx, client)
+}
+
+func (s *Store) open(ctx context.Context, client storage.Client) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var size int64
+	for i := range s.buckets {
+		data, err := client.Get(ctx, indexKey(byte(i)))
+		if err != nil {
+			return err
+		}
+		traces := make(map[pcommon.TraceID]Trace, len(data)/indexEntrySize)
+		for ; len(data) >= indexEntrySize; data = data[indexEntrySize:] {
+			var traceID pcommon.TraceID
+			copy(traceID[:], data[:indexEntrySize])
+			metadata, err := client.Get(ctx, metadataKey(traceID))
+			if err != nil {
+				return err
+			}
+			// a trace without description is dropped from the index when its bucket is next rewritten
+			if len(metadata) < metadataSize {
+				continue
+			}
+			t := decodeMetadata(metadata)
+			traces[traceID] = t
+			size += t.Size
+		}
+		s.buckets[i].traces = traces
+	}
+
+	atomic.StoreInt64(&s.size, size)
+	s.client = client
+	return nil
+}
+
+// Shutdown closes the storage client, the stored traces are kept in the storage.
+func (s *Store) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.client == nil {
+		return nil
+	}
+	err := s.client.Close(ctx)
+	s.client = nil
+	return err
+}
+
+// Append stores a batch of spans of the trace.
+func (s *Store) Append(traceID pcommon.TraceID, arrivalTime time.Time, td ptrace.Traces) error {
+	data, err := s.marshaler.MarshalTraces(td)
+	if err != nil {
+		return err
+	}
+	size := int64(len(data))
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.client == nil {
+		return ErrClosed
+	}
+	if total := atomic.AddInt64(&s.size, size); s.maxSize > 0 && total > s.maxSize {
+		atomic.AddInt64(&s.size, -size)
+		return ErrFull
+	}
+
+	b := &s.buckets[traceID[0]]
+	b.Lock()
+	defer b.Unlock()
+
+	previous, exists := b.traces[traceID]
+	current := previous
+	if !exists {
+		current.ArrivalTime = arrivalTime
+	}
+	current.NumBatches++
+	current.SpanCount += uint32(td.SpanCount())
+	current.Size += size
+
+	b.traces[traceID] = current
+	ops := []storage.Operation{
+		storage.SetOperation(batchKey(traceID, previous.NumBatches), data),
+		storage.SetOperation(metadataKey(traceID), encodeMetadata(current)),
+	}
+	if !exists {
+		ops = append(ops, storage.SetOperation(indexKey(traceID[0]), encodeIndex(b.traces)))
+	}
+	if err = s.client.Batch(context.Background(), ops...); err != nil {
+		if exists {
+			b.traces[traceID] = previous
+		} else {
+			delete(b.traces, traceID)
+		}
+		atomic.AddInt64(&s.size, -size)
+		return err
+	}
+	return nil
+}
+
+// Get returns the description of a stored trace.
+func (s *Store) Get(traceID pcommon.TraceID) (Trace, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	b := &s.buckets[traceID[0]]
+	b.Lock()
+	defer b.Unlock()
+	t, ok := b.traces[traceID]
+	return t, ok
+}
+
+// Load returns the stored batches of the trace. When some batches cannot be read, the
+// readable ones are returned along with an error wrapping ErrCorrupt.
+func (s *Store) Load(traceID pcommon.TraceID) ([]ptrace.Traces, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.client == nil {
+		return nil, ErrClosed
+	}
+	b := &s.buckets[traceID[0]]
+	b.Lock()
+	defer b.Unlock()
+
+	t, ok := b.traces[traceID]
+	if !ok {
+		return nil, nil
+	}
+	return s.read(context.Background(), traceID, t)
+}
+
+// Take returns the stored batches of the trace and removes the trace from the storage.
+// A trace whose batches cannot all be read is removed too: the readable batches are
+// returned along with an error wrapping ErrCorrupt.
+func (s *Store) Take(traceID pcommon.TraceID) ([]ptrace.Traces, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.client == nil {
+		return nil, ErrClosed
+	}
+	b := &s.buckets[traceID[0]]
+	b.Lock()
+	defer b.Unlock()
+
+	t, ok := b.traces[traceID]
+	if !ok {
+		return nil, nil
+	}
+	ctx := context.Background()
+	batches, readErr := s.read(ctx, traceID, t)
+	if readErr != nil && !errors.Is(readErr, ErrCorrupt) {
+		return nil, readErr
+	}
+
+	delete(b.traces, traceID)
+	ops := make([]storage.Operation, 0, t.NumBatches+2)
+	for i := uint32(0); i < t.NumBatches; i++ {
+		ops = append(ops, storage.DeleteOperation(batchKey(traceID, i)))
+	}
+	ops = append(ops, storage.DeleteOperation(metadataKey(traceID)))
+	if len(b.traces) == 0 {
+		ops = append(ops, storage.DeleteOperation(indexKey(traceID[0])))
+	} else {
+		ops = append(ops, storage.SetOperation(indexKey(traceID[0]), encodeIndex(b.traces)))
+	}
+	if err := s.client.Batch(ctx, ops...); err != nil {
+		b.traces[traceID] = t
+		return nil, err
+	}
+
+	atomic.AddInt64(&s.size, -t.Size)
+	return batches, readErr
+}
+
+// Traces returns the descriptions of the stored traces.
+func (s *Store) Traces() map[pcommon.TraceID]Trace {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	traces := make(map[pcommon.TraceID]Trace)
+	for i := range s.buckets {
+		b := &s.buckets[i]
+		b.Lock()
+		for traceID, t := range b.traces {
+			traces[traceID] = t
+		}
+		b.Unlock()
+	}
+	return traces
+}
+
+// Count returns the number of stored traces.
+func (s *Store) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	count := 0
+	for i := range s.buckets {
+		b := &s.buckets[i]
+		b.Lock()
+		count += len(b.traces)
+		b.Unlock()
+	}
+	return count
+}
+
+// Size returns the total size of the stored batches.
+func (s *Store) Size() int64 {
+	return atomic.LoadInt64(&s.size)
+}
+
+// read reads the batches of a trace. Batches that are missing or cannot be decoded are
+// skipped and reported with an error wrapping ErrCorrupt, other errors abort the read.
+func (s *Store) read(ctx context.Context, traceID pcommon.TraceID, t Trace) ([]ptrace.Traces, error) {
+	batches := make([]ptrace.Traces, 0, t.NumBatches)
+	var corruptErr error
+	for i := uint32(0); i < t.NumBatches; i++ {
+		data, err := s.client.Get(ctx, batchKey(traceID, i))
+		if err != nil {
+			return nil, err
+		}
+		if data == nil {
+			corruptErr = fmt.Errorf("%w: batch %d of trace %q is missing", ErrCorrupt, i, traceID.HexString())
+			continue
+		}
+		td, err := s.unmarshaler.UnmarshalTraces(data)
+		if err != nil {
+			corruptErr = fmt.Errorf("%w: batch %d of trace %q: %v", ErrCorrupt, i, traceID.HexString(), err)
+			continue
+		}
+		batches = append(batches, td)
+	}
+	return batches, corruptErr
+}
+
+func metadataKey(traceID pcommon.TraceID) string {
+	return traceKeyPrefix + traceID.HexString()
+}
+
+func batchKey(traceID pcommon.TraceID, n uint32) string {
+	return fmt.Sprintf("%s%s_%d", traceKeyPrefix, traceID.HexString(), n)
+}
+
+func indexKey(bucket byte) string {
+	return fmt.Sprintf("%s%02x", indexKeyPrefix, bucket)
+}
+
+func encodeIndex(traces map[pcommon.TraceID]Trace) []byte {
+	data := make([]byte, 0, len(traces)*indexEntrySize)
+	for traceID := range traces {
+		data = append(data, traceID[:]...)
+	}
+	return data
+}
+
+func encodeMetadata(t Trace) []byte {
+	data := make([]byte, metadataSize)
+	binary.BigEndian.PutUint64(data[0:8], uint64(t.ArrivalTime.UnixNano()))
+	binary.BigEndian.PutUint32(data[8:12], t.NumBatches)
+	binary.BigEndian.PutUint32(data[12:16], t.SpanCount)
+	binary.BigEndian.PutUint64(data[16:24], uint64(t.Size))
+	return data
+}
+
+func decodeMetadata(data []byte) Trace {
+	return Trace{
+		ArrivalTime: time.Unix(0, int64(binary.BigEndian.Uint64(data[0:8]))),
+		NumBatches:  binary.BigEndian.Uint32(data[8:12]),
+		SpanCount:   binary.BigEndian.Uint32(data[12:16]),
+		Size:        int64(binary.BigEndian.Uint64(data[16:24])),
+	}
+}
@@ -0,0 +1,246 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracestore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+// fakeClient is an in-memory storage client recording the keys written by each batch.
+type fakeClient struct {
+	mu       sync.Mutex
+	data     map[string][]byte
+	batches  [][]string
+	batchErr error
+}
+
+var _ storage.Client = (*fakeClient)(nil)
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{data: make(map[string][]byte)}
+}
+
+func (c *fakeClient) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.data[key], nil
+}
+
+func (c *fakeClient) Set(ctx context.Context, key string, value []byte) error {
+	return c.Batch(ctx, storage.SetOperation(key, value))
+}
+
+func (c *fakeClient) Delete(ctx context.Context, key string) error {
+	return c.Batch(ctx, storage.DeleteOperation(key))
+}
+
+func (c *fakeClient) Batch(_ context.Context, ops ...storage.Operation) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.batchErr != nil {
+		return c.batchErr
+	}
+	keys := make([]string, 0, len(ops))
+	for _, op := range ops {
+		switch op.Type {
+		case storage.Set:
+			c.data[op.Key] = op.Value
+		case storage.Delete:
+			delete(c.data, op.Key)
+		}
+		keys = append(keys, op.Key)
+	}
+	c.batches = append(c.batches, keys)
+	return nil
+}
+
+func (c *fakeClient) Close(context.Context) error {
+	return nil
+}
+
+func newOpenStore(t *testing.T, client storage.Client, maxSize int64) *Store {
+	s := New(config.NewComponentID("storage"), config.NewComponentID("processor"), maxSize)
+	require.NoError(t, s.open(context.Background(), client))
+	return s
+}
+
+func tracesWithID(traceID pcommon.TraceID) ptrace.Traces {
+	td := ptrace.NewTraces()
+	span := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+	span.SetTraceID(traceID)
+	span.SetName("span")
+	return td
+}
+
+func encodedSize(t *testing.T, td ptrace.Traces) int64 {
+	data, err := ptrace.NewProtoMarshaler().MarshalTraces(td)
+	require.NoError(t, err)
+	return int64(len(data))
+}
+
+func TestAppendAndTake(t *testing.T) {
+	s := newOpenStore(t, newFakeClient(), 0)
+	defer func() { assert.NoError(t, s.Shutdown(context.Background())) }()
+
+	first := pcommon.TraceID([16]byte{1, 2, 3})
+	second := pcommon.TraceID([16]byte{2, 3, 4})
+	arrival := time.Unix(100, 0)
+	require.NoError(t, s.Append(first, arrival, tracesWithID(first)))
+	require.NoError(t, s.Append(first, arrival.Add(time.Second), tracesWithID(first)))
+	require.NoError(t, s.Append(second, arrival, tracesWithID(second)))
+
+	size := encodedSize(t, tracesWithID(first))
+	stored, ok := s.Get(first)
+	require.True(t, ok)
+	assert.Equal(t, Trace{ArrivalTime: arrival, NumBatches: 2, SpanCount: 2, Size: 2 * size}, stored)
+	assert.Equal(t, 2, s.Count())
+	assert.Len(t, s.Traces(), 2)
+	assert.Equal(t, 3*size, s.Size())
+
+	loaded, err := s.Load(first)
+	require.NoError(t, err)
+	assert.Equal(t, []ptrace.Traces{tracesWithID(first), tracesWithID(first)}, loaded)
+
+	taken, err := s.Take(first)
+	require.NoError(t, err)
+	assert.Equal(t, loaded, taken)
+	_, ok = s.Get(first)
+	assert.False(t, ok, "taken traces must be removed from the store")
+	assert.Equal(t, size, s.Size())
+
+	taken, err = s.Take(first)
+	require.NoError(t, err)
+	assert.Empty(t, taken)
+}
+
+func TestAppendToKnownTraceKeepsIndex(t *testing.T) {
+	client := newFakeClient()
+	s := newOpenStore(t, client, 0)
+
+	traceID := pcommon.TraceID([16]byte{1, 2, 3})
+	require.NoError(t, s.Append(traceID, time.Now(), tracesWithID(traceID)))
+	require.NoError(t, s.Append(traceID, time.Now(), tracesWithID(traceID)))
+
+	require.Len(t, client.batches, 2)
+	assert.Contains(t, client.batches[0], indexKey(1), "a new trace must be added to the index")
+	assert.Equal(t, []string{batchKey(traceID, 1), metadataKey(traceID)}, client.batches[1])
+}
+
+func TestRestoreAfterRestart(t *testing.T) {
+	client := newFakeClient()
+	s := newOpenStore(t, client, 0)
+
+	kept := pcommon.TraceID([16]byte{1, 2, 3})
+	taken := pcommon.TraceID([16]byte{1, 4, 5})
+	arrival := time.Unix(0, 1234567890)
+	require.NoError(t, s.Append(kept, arrival, tracesWithID(kept)))
+	require.NoError(t, s.Append(taken, arrival, tracesWithID(taken)))
+	_, err := s.Take(taken)
+	require.NoError(t, err)
+	require.NoError(t, s.Shutdown(context.Background()))
+
+	_, err = s.Take(kept)
+	assert.ErrorIs(t, err, ErrClosed)
+	assert.ErrorIs(t, s.Append(kept, arrival, tracesWithID(kept)), ErrClosed)
+
+	s = newOpenStore(t, client, 0)
+	size := encodedSize(t, tracesWithID(kept))
+	assert.Equal(t, map[pcommon.TraceID]Trace{
+		kept: {ArrivalTime: arrival, NumBatches: 1, SpanCount: 1, Size: size},
+	}, s.Traces())
+	assert.Equal(t, size, s.Size())
+
+	batches, err := s.Take(kept)
+	require.NoError(t, err)
+	assert.Equal(t, []ptrace.Traces{tracesWithID(kept)}, batches)
+	assert.Empty(t, client.data, "taken traces must not leave keys behind")
+}
+
+func TestTakeCorruptTrace(t *testing.T) {
+	client := newFakeClient()
+	s := newOpenStore(t, client, 0)
+
+	traceID := pcommon.TraceID([16]byte{1, 2, 3})
+	require.NoError(t, s.Append(traceID, time.Now(), tracesWithID(traceID)))
+	require.NoError(t, s.Append(traceID, time.Now(), tracesWithID(traceID)))
+	require.NoError(t, client.Delete(context.Background(), batchKey(traceID, 0)))
+
+	loaded, errLoad := s.Load(traceID)
+	taken, errTake := s.Take(traceID)
+
+	assert.ErrorIs(t, errLoad, ErrCorrupt)
+	assert.ErrorIs(t, errTake, ErrCorrupt)
+	assert.Equal(t, []ptrace.Traces{tracesWithID(traceID)}, loaded)
+	assert.Equal(t, loaded, taken)
+	assert.Zero(t, s.Count(), "unreadable traces must be removed")
+	assert.Zero(t, s.Size())
+}
+
+func TestStoreFull(t *testing.T) {
+	traceID := pcommon.TraceID([16]byte{1, 2, 3})
+	s := newOpenStore(t, newFakeClient(), encodedSize(t, tracesWithID(traceID)))
+
+	require.NoError(t, s.Append(traceID, time.Now(), tracesWithID(traceID)))
+	other := pcommon.TraceID([16]byte{2, 3, 4})
+	assert.ErrorIs(t, s.Append(traceID, time.Now(), tracesWithID(traceID)), ErrFull)
+	assert.ErrorIs(t, s.Append(other, time.Now(), tracesWithID(other)), ErrFull)
+	assert.Equal(t, 1, s.Count())
+
+	// taking the trace frees the space
+	_, err := s.Take(traceID)
+	require.NoError(t, err)
+	assert.NoError(t, s.Append(other, time.Now(), tracesWithID(other)))
+}
+
+func TestAppendFailureIsRolledBack(t *testing.T) {
+	client := newFakeClient()
+	s := newOpenStore(t, client, 0)
+
+	traceID := pcommon.TraceID([16]byte{1, 2, 3})
+	require.NoError(t, s.Append(traceID, time.Now(), tracesWithID(traceID)))
+	stored, _ := s.Get(traceID)
+	size := s.Size()
+
+	client.batchErr = errors.New("batch failed")
+	other := pcommon.TraceID([16]byte{2, 3, 4})
+	assert.Error(t, s.Append(traceID, time.Now(), tracesWithID(traceID)))
+	assert.Error(t, s.Append(other, time.Now(), tracesWithID(other)))
+	_, err := s.Take(traceID)
+	assert.Error(t, err)
+
+	current, _ := s.Get(traceID)
+	assert.Equal(t, stored, current)
+	assert.Equal(t, 1, s.Count())
+	assert.Equal(t, size, s.Size())
+}
+
+func TestStartWithoutExtension(t *testing.T) {
+	s := New(config.NewComponentID("storage"), config.NewComponentID("processor"), 0)
+	assert.Error(t, s.Start(context.Background(), componenttest.NewNopHost()))
+	assert.ErrorIs(t, s.Append(pcommon.TraceID([16]byte{1}), time.Now(), ptrace.NewTraces()), ErrClosed)
+	assert.NoError(t, s.Shutdown(context.Background()))
+}
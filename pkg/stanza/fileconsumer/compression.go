@@ -0,0 +1,89 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+const (
+	compressionNone = ""
+	compressionGzip = "gzip"
+	compressionAuto = "auto"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+func validateCompression(compression string) error {
+	switch compression {
+	case compressionNone, compressionGzip, compressionAuto:
+		return nil
+	default:
+		return fmt.Errorf("invalid compression '%s'", compression)
+	}
+}
+
+// isGzip reports whether the file must be decompressed. In auto mode a file
+// is considered compressed if it has a .gz extension or starts with the gzip
+// magic bytes.
+func isGzip(file *os.File, compression string) bool {
+	switch compression {
+	case compressionGzip:
+		return true
+	case compressionAuto:
+		if filepath.Ext(file.Name()) == ".gz" {
+			return true
+		}
+		magic := make([]byte, len(gzipMagic))
+		n, _ := file.ReadAt(magic, 0)
+		return bytes.Equal(magic[:n], gzipMagic)
+	default:
+		return false
+	}
+}
+
+// newGzipReader returns a reader of the decompressed content of the file,
+// starting from its beginning regardless of the current file offset.
+func newGzipReader(file *os.File) (*gzip.Reader, error) {
+	return gzip.NewReader(io.NewSectionReader(file, 0, math.MaxInt64))
+}
+
+// newGzipFingerprint creates a fingerprint from the first bytes of the
+// decompressed content of the file.
+func newGzipFingerprint(file *os.File, size int) (*Fingerprint, error) {
+	gz, err := newGzipReader(file)
+	if errors.Is(err, io.EOF) {
+		// The gzip header is not written yet.
+		return &Fingerprint{FirstBytes: []byte{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading gzip header: %w", err)
+	}
+	defer gz.Close()
+
+	buf := make([]byte, size)
+	n, err := io.ReadFull(gz, buf)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, fmt.Errorf("reading fingerprint bytes: %w", err)
+	}
+	return &Fingerprint{FirstBytes: buf[:n]}, nil
+}
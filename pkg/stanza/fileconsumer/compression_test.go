@@ -0,0 +1,172 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func gzipBytes(t *testing.T, content string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestIsGzip(t *testing.T) {
+	tempDir := t.TempDir()
+	compressed := openFile(t, filepath.Join(tempDir, "compressed"))
+	_, err := compressed.Write(gzipBytes(t, "testlog\n"))
+	require.NoError(t, err)
+	plain := openFile(t, filepath.Join(tempDir, "plain"))
+	writeString(t, plain, "testlog\n")
+	extension := openFile(t, filepath.Join(tempDir, "empty.gz"))
+
+	require.False(t, isGzip(compressed, compressionNone))
+	require.True(t, isGzip(compressed, compressionAuto))
+	require.True(t, isGzip(compressed, compressionGzip))
+	require.False(t, isGzip(plain, compressionAuto))
+	require.True(t, isGzip(plain, compressionGzip))
+	require.True(t, isGzip(extension, compressionAuto))
+}
+
+func TestGzipFingerprint(t *testing.T) {
+	tempDir := t.TempDir()
+	compressed := openFile(t, filepath.Join(tempDir, "compressed"))
+	_, err := compressed.Write(gzipBytes(t, "testlog1\ntestlog2\n"))
+	require.NoError(t, err)
+
+	fp, err := newGzipFingerprint(compressed, 12)
+	require.NoError(t, err)
+	require.Equal(t, []byte("testlog1\ntes"), fp.FirstBytes)
+
+	fp, err = newGzipFingerprint(compressed, DefaultFingerprintSize)
+	require.NoError(t, err)
+	require.Equal(t, []byte("testlog1\ntestlog2\n"), fp.FirstBytes)
+
+	empty := openFile(t, filepath.Join(tempDir, "empty.gz"))
+	fp, err = newGzipFingerprint(empty, DefaultFingerprintSize)
+	require.NoError(t, err)
+	require.Empty(t, fp.FirstBytes)
+}
+
+// TestReadGzipFile tests that compressed files are decompressed, and that
+// they are not read again once fully consumed
+func TestReadGzipFile(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Compression = "auto"
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	temp := openFile(t, filepath.Join(tempDir, "app.log.1.gz"))
+	_, err := temp.Write(gzipBytes(t, "testlog1\ntestlog2\n"))
+	require.NoError(t, err)
+
+	operator.poll(context.Background())
+	waitForTokens(t, emitCalls, [][]byte{[]byte("testlog1"), []byte("testlog2")})
+
+	operator.poll(context.Background())
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+}
+
+// TestReadGzipFileStartAtEnd tests that the end of a compressed file is
+// located in its decompressed content
+func TestReadGzipFileStartAtEnd(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.Compression = "gzip"
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	temp := openFile(t, filepath.Join(tempDir, "app.log.gz"))
+	_, err := temp.Write(gzipBytes(t, "testlog1\n"))
+	require.NoError(t, err)
+
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+
+	// Rewrite the file as a longer stream with the same beginning.
+	require.NoError(t, temp.Truncate(0))
+	_, err = temp.WriteAt(gzipBytes(t, "testlog1\ntestlog2\n"), 0)
+	require.NoError(t, err)
+
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog2"))
+	expectNoTokens(t, emitCalls)
+}
+
+// TestReadRotatedGzipFile tests that a file compressed after being rotated
+// is recognized by its decompressed content and only its new logs are read
+func TestReadRotatedGzipFile(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.Compression = "auto"
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	path := filepath.Join(tempDir, "app.log")
+	temp := openFile(t, path)
+	writeString(t, temp, "testlog1\n")
+
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog1"))
+
+	// Rotate and compress the file after a last log was written.
+	writeString(t, temp, "testlog2\n")
+	require.NoError(t, temp.Close())
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, os.WriteFile(path+".1.gz", gzipBytes(t, "testlog1\ntestlog2\n"), 0600))
+
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog2"))
+	expectNoTokens(t, emitCalls)
+}
+
+func TestBuildInvalidCompression(t *testing.T) {
+	cfg := NewConfig().includeDir(t.TempDir())
+	cfg.Compression = "zip"
+	_, err := cfg.Build(testutil.Logger(t), func(_ context.Context, _ *FileAttributes, _ []byte) {})
+	require.EqualError(t, err, "invalid compression 'zip'")
+}
@@ -57,6 +57,8 @@ type Config struct {
 	FingerprintSize         helper.ByteSize       `mapstructure:"fingerprint_size,omitempty"               json:"fingerprint_size,omitempty"              yaml:"fingerprint_size,omitempty"`
 	MaxLogSize              helper.ByteSize       `mapstructure:"max_log_size,omitempty"                   json:"max_log_size,omitempty"                  yaml:"max_log_size,omitempty"`
 	MaxConcurrentFiles      int                   `mapstructure:"max_concurrent_files,omitempty"           json:"max_concurrent_files,omitempty"          yaml:"max_concurrent_files,omitempty"`
+	Compression             string                `mapstructure:"compression,omitempty"                    json:"compression,omitempty"                   yaml:"compression,omitempty"`
+	DeleteAfterRead         DeleteAfterRead       `mapstructure:"delete_after_read,omitempty"              json:"delete_after_read,omitempty"             yaml:"delete_after_read,omitempty"`
 	Splitter                helper.SplitterConfig `mapstructure:",squash,omitempty"                        json:",inline,omitempty"                       yaml:",inline,omitempty"`
 }
 
@@ -86,6 +88,11 @@ func (c Config) Build(logger *zap.SugaredLogger, emit EmitFunc) (*Manager, error
 		}
 	}
 
+	ordering, err := c.OrderingCriteria.build()
+	if err != nil {
+		return nil, err
+	}
+
 	if c.MaxLogSize <= 0 {
 		return nil, fmt.Errorf("`max_log_size` must be positive")
 	}
@@ -100,8 +107,12 @@ func (c Config) Build(logger *zap.SugaredLogger, emit EmitFunc) (*Manager, error
 		return nil, fmt.Errorf("`fingerprint_size` must be at least %d bytes", MinFingerprintSize)
 	}
 
+	if err := validateCompression(c.Compression); err != nil {
+		return nil, err
+	}
+
 	// Ensure that splitter is buildable
-	_, err := c.Splitter.Build(false, int(c.MaxLogSize))
+	_, err = c.Splitter.Build(false, int(c.MaxLogSize))
 	if err != nil {
 		return nil, err
 	}
@@ -116,6 +127,13 @@ func (c Config) Build(logger *zap.SugaredLogger, emit EmitFunc) (*Manager, error
 		return nil, fmt.Errorf("invalid start_at location '%s'", c.StartAt)
 	}
 
+	if err := c.DeleteAfterRead.validate(startAtBeginning); err != nil {
+		return nil, err
+	}
+	if c.DeleteAfterRead.MinIdle == 0 {
+		c.DeleteAfterRead.MinIdle = defaultDeleteMinIdle
+	}
+
 	return &Manager{
 		SugaredLogger: logger.With("component", "fileconsumer"),
 		cancel:        func() {},
@@ -124,16 +142,19 @@ func (c Config) Build(logger *zap.SugaredLogger, emit EmitFunc) (*Manager, error
 			readerConfig: &readerConfig{
 				fingerprintSize: int(c.FingerprintSize),
 				maxLogSize:      int(c.MaxLogSize),
+				compression:     c.Compression,
 				emit:            emit,
 			},
 			fromBeginning:  startAtBeginning,
 			splitterConfig: c.Splitter,
 		},
-		finder:        c.Finder,
-		roller:        newRoller(),
-		pollInterval:  c.PollInterval,
-		maxBatchFiles: c.MaxConcurrentFiles / 2,
-		knownFiles:    make([]*Reader, 0, 10),
-		seenPaths:     make(map[string]struct{}, 100),
+		finder:          c.Finder,
+		ordering:        ordering,
+		roller:          newRoller(),
+		pollInterval:    c.PollInterval,
+		maxBatchFiles:   c.MaxConcurrentFiles / 2,
+		deleteAfterRead: c.DeleteAfterRead,
+		knownFiles:      make([]*Reader, 0, 10),
+		seenPaths:       make(map[string]struct{}, 100),
 	}, nil
 }
@@ -320,6 +320,46 @@ func TestUnmarshal(t *testing.T) {
 				return cfg
 			}(),
 		},
+		{
+			Name:      "delete_after_read",
+			ExpectErr: false,
+			Expect: func() *Config {
+				cfg := NewConfig()
+				cfg.StartAt = "beginning"
+				cfg.DeleteAfterRead = DeleteAfterRead{
+					Enabled:    true,
+					ArchiveDir: "/var/spool/app/done",
+					MinIdle:    5 * time.Second,
+				}
+				return cfg
+			}(),
+		},
+		{
+			Name:      "ordering_criteria",
+			ExpectErr: false,
+			Expect: func() *Config {
+				cfg := NewConfig()
+				cfg.Include = append(cfg.Include, "/var/log/app-*.log")
+				cfg.OrderingCriteria = OrderingCriteria{
+					Regex:   `^app-(?P<service>\w+)-(?P<ts>\d{10})\.log$`,
+					TopN:    2,
+					GroupBy: "service",
+					SortBy: []SortRule{
+						{RegexKey: "ts", SortType: "timestamp", Layout: "%Y%m%d%H", Location: "UTC"},
+					},
+				}
+				return cfg
+			}(),
+		},
+		{
+			Name:      "compression_auto",
+			ExpectErr: false,
+			Expect: func() *Config {
+				cfg := NewConfig()
+				cfg.Compression = "auto"
+				return cfg
+			}(),
+		},
 		{
 			Name:      "max_concurrent_large",
 			ExpectErr: false,
@@ -434,6 +474,27 @@ func TestBuild(t *testing.T) {
 			require.Error,
 			nil,
 		},
+		{
+			"OrderingCriteria",
+			func(f *Config) {
+				f.OrderingCriteria = OrderingCriteria{
+					Regex:  `^testpath\.(?P<n>\d+)$`,
+					SortBy: []SortRule{{RegexKey: "n", SortType: "numeric"}},
+				}
+			},
+			require.NoError,
+			func(t *testing.T, f *Manager) {
+				require.NotNil(t, f.ordering)
+			},
+		},
+		{
+			"InvalidOrderingCriteria",
+			func(f *Config) {
+				f.OrderingCriteria = OrderingCriteria{TopN: 2}
+			},
+			require.Error,
+			nil,
+		},
 		{
 			"MultilineConfiguredStartAndEndPatterns",
 			func(f *Config) {
@@ -0,0 +1,180 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+const defaultDeleteMinIdle = 5 * time.Minute
+
+// DeleteAfterRead removes files once their whole content has been emitted.
+type DeleteAfterRead struct {
+	Enabled bool `mapstructure:"enabled,omitempty" json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	// ArchiveDir is the directory into which files are moved instead of
+	// being deleted.
+	ArchiveDir string `mapstructure:"archive_dir,omitempty" json:"archive_dir,omitempty" yaml:"archive_dir,omitempty"`
+	// MinIdle is how long a file must have been left unmodified, and its
+	// last entries emitted, before it is removed, so that files still being
+	// written are not removed and their entries are flushed downstream. It
+	// defaults to five minutes.
+	MinIdle time.Duration `mapstructure:"min_idle,omitempty" json:"min_idle,omitempty" yaml:"min_idle,omitempty"`
+}
+
+func (d DeleteAfterRead) validate(startAtBeginning bool) error {
+	if !d.Enabled {
+		if d.ArchiveDir != "" {
+			return fmt.Errorf("`delete_after_read.archive_dir` requires `delete_after_read.enabled`")
+		}
+		return nil
+	}
+
+	if !startAtBeginning {
+		return fmt.Errorf("`delete_after_read` requires `start_at: beginning`")
+	}
+
+	if d.MinIdle < 0 {
+		return fmt.Errorf("`delete_after_read.min_idle` must not be negative")
+	}
+
+	if d.ArchiveDir != "" {
+		info, err := os.Stat(d.ArchiveDir)
+		if err != nil {
+			return fmt.Errorf("`delete_after_read.archive_dir`: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("`delete_after_read.archive_dir` '%s' is not a directory", d.ArchiveDir)
+		}
+	}
+	return nil
+}
+
+// removeReadFiles removes the files that have been read to their end and
+// have not been modified since, and returns the readers whose file is still
+// open. A file is only removed in a poll after the one that emitted its last
+// entries, once min_idle has passed, so that the pipeline has flushed them.
+// The readers of removed files are kept by the caller so that their
+// fingerprint remains known.
+func (m *Manager) removeReadFiles(readers []*Reader, pollStart time.Time) []*Reader {
+	open := make([]*Reader, 0, len(readers))
+	for _, reader := range readers {
+		if !m.isReadFile(reader, pollStart) {
+			open = append(open, reader)
+			continue
+		}
+
+		// The file must be closed before it is removed on windows.
+		path := reader.file.Name()
+		reader.Close()
+		if err := m.removeFile(path); err != nil {
+			m.Errorw("Failed to remove file", "path", path, zap.Error(err))
+			if reader.file, err = os.Open(path); err != nil { // #nosec - operator must read in files defined by user
+				m.Errorw("Failed to reopen file", "path", path, zap.Error(err))
+				reader.file = nil
+				continue
+			}
+			open = append(open, reader)
+			continue
+		}
+		reader.file = nil
+		delete(m.seenPaths, path)
+	}
+	return open
+}
+
+func (m *Manager) isReadFile(reader *Reader, pollStart time.Time) bool {
+	if !reader.readToEOF || !reader.eofTime.Before(pollStart) || time.Since(reader.eofTime) < m.deleteAfterRead.MinIdle {
+		return false
+	}
+	info, err := reader.file.Stat()
+	if err != nil {
+		m.Debugw("Failed to stat file", "path", reader.file.Name(), zap.Error(err))
+		return false
+	}
+	return info.Size() == reader.eofSize && time.Since(info.ModTime()) >= m.deleteAfterRead.MinIdle
+}
+
+func (m *Manager) removeFile(path string) error {
+	if m.deleteAfterRead.ArchiveDir == "" {
+		m.Infow("Deleting file read to the end", "path", path)
+		return os.Remove(path)
+	}
+
+	target := filepath.Join(m.deleteAfterRead.ArchiveDir, filepath.Base(path))
+	if _, err := os.Lstat(target); err == nil {
+		return fmt.Errorf("archive '%s' already exists", target)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	m.Infow("Archiving file read to the end", "path", path, "archive", target)
+	if err := os.Rename(path, target); err != nil {
+		// Files cannot be renamed across filesystems, they are copied instead.
+		if copyErr := copyFile(path, target); copyErr != nil {
+			return multierr.Append(err, copyErr)
+		}
+		if err = os.Remove(path); err != nil {
+			return multierr.Append(err, os.Remove(target))
+		}
+	}
+	return nil
+}
+
+// removeEmptyFile removes a file that has been left empty for long enough.
+// Empty files are not read, as they have no fingerprint yet.
+func (m *Manager) removeEmptyFile(path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		m.Debugw("Failed to stat file", "path", path, zap.Error(err))
+		return
+	}
+	if info.Size() != 0 || time.Since(info.ModTime()) < m.deleteAfterRead.MinIdle {
+		return
+	}
+	if err = m.removeFile(path); err != nil {
+		m.Errorw("Failed to remove file", "path", path, zap.Error(err))
+		return
+	}
+	delete(m.seenPaths, path)
+}
+
+func copyFile(src, dst string) (err error) {
+	in, err := os.Open(src) // #nosec - operator must read in files defined by user
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600) // #nosec - the archive directory is defined by user
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err == nil {
+		err = out.Sync()
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return multierr.Append(err, os.Remove(dst))
+	}
+	return nil
+}
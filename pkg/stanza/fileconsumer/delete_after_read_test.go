@@ -0,0 +1,326 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/testutil"
+)
+
+func deleteAfterReadConfig(tempDir string) *Config {
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.DeleteAfterRead = DeleteAfterRead{Enabled: true, MinIdle: time.Nanosecond}
+	return cfg
+}
+
+// setEOFTime pretends that the last entries of the known files were
+// emitted at eofTime.
+func setEOFTime(m *Manager, eofTime time.Time) {
+	for _, reader := range m.knownFiles {
+		reader.eofTime = eofTime
+	}
+}
+
+// TestDeleteAfterRead tests that a file is deleted in the poll following
+// the one that emitted its content, and that a new file with the same name
+// is read again
+func TestDeleteAfterRead(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	operator, emitCalls := buildTestManager(t, deleteAfterReadConfig(tempDir))
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	path := filepath.Join(tempDir, "batch.log")
+	require.NoError(t, os.WriteFile(path, []byte("testlog1\ntestlog2\n"), 0600))
+
+	operator.poll(context.Background())
+	waitForTokens(t, emitCalls, [][]byte{[]byte("testlog1"), []byte("testlog2")})
+	require.FileExists(t, path)
+
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+	require.NoFileExists(t, path)
+
+	require.NoError(t, os.WriteFile(path, []byte("testlog3\n"), 0600))
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog3"))
+	require.FileExists(t, path)
+
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+	require.NoFileExists(t, path)
+}
+
+// TestDeleteAfterReadArchive tests that a file is moved to the archive
+// directory once its content has been emitted
+func TestDeleteAfterReadArchive(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	archiveDir := t.TempDir()
+	cfg := deleteAfterReadConfig(tempDir)
+	cfg.DeleteAfterRead.ArchiveDir = archiveDir
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	path := filepath.Join(tempDir, "batch.log")
+	require.NoError(t, os.WriteFile(path, []byte("testlog1\n"), 0600))
+
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog1"))
+	operator.poll(context.Background())
+	require.NoFileExists(t, path)
+
+	content, err := os.ReadFile(filepath.Join(archiveDir, "batch.log"))
+	require.NoError(t, err)
+	require.Equal(t, []byte("testlog1\n"), content)
+
+	// An archive that already exists is not overwritten.
+	require.NoError(t, os.WriteFile(path, []byte("testlog2\n"), 0600))
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog2"))
+	operator.poll(context.Background())
+	require.FileExists(t, path)
+
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+}
+
+// TestDeleteAfterReadEmpty tests that an empty file is deleted once it has
+// been left unmodified for long enough
+func TestDeleteAfterReadEmpty(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := deleteAfterReadConfig(tempDir)
+	cfg.DeleteAfterRead.MinIdle = time.Hour
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	path := filepath.Join(tempDir, "batch.log")
+	require.NoError(t, os.WriteFile(path, nil, 0600))
+
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+	require.FileExists(t, path)
+
+	past := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(path, past, past))
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+	require.NoFileExists(t, path)
+}
+
+// TestDeleteAfterReadCopyFile tests the copy used to archive files across
+// filesystems
+func TestDeleteAfterReadCopyFile(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	src := filepath.Join(tempDir, "src.log")
+	dst := filepath.Join(tempDir, "dst.log")
+	require.NoError(t, os.WriteFile(src, []byte("testlog1\n"), 0600))
+
+	require.NoError(t, copyFile(src, dst))
+	content, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	require.Equal(t, []byte("testlog1\n"), content)
+
+	// An existing file is not overwritten.
+	require.NoError(t, os.WriteFile(src, []byte("testlog2\n"), 0600))
+	require.Error(t, copyFile(src, dst))
+	content, err = os.ReadFile(dst)
+	require.NoError(t, err)
+	require.Equal(t, []byte("testlog1\n"), content)
+}
+
+// TestDeleteAfterReadMinIdle tests that a file is only deleted once it has
+// been left unmodified, and its last entries emitted, for long enough
+func TestDeleteAfterReadMinIdle(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := deleteAfterReadConfig(tempDir)
+	cfg.DeleteAfterRead.MinIdle = time.Hour
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	path := filepath.Join(tempDir, "batch.log")
+	temp := openFile(t, path)
+	writeString(t, temp, "testlog1\n")
+
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog1"))
+	require.FileExists(t, path)
+
+	writeString(t, temp, "testlog2\n")
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog2"))
+	require.FileExists(t, path)
+
+	past := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(path, past, past))
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+	require.FileExists(t, path)
+
+	setEOFTime(operator, past)
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+	require.NoFileExists(t, path)
+}
+
+// TestDeleteAfterReadUnterminated tests that a file whose last entry is
+// not terminated is not deleted
+func TestDeleteAfterReadUnterminated(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := deleteAfterReadConfig(tempDir)
+	cfg.Splitter.Flusher.Period = 0
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	path := filepath.Join(tempDir, "batch.log")
+	temp := openFile(t, path)
+	writeString(t, temp, "testlog1\ntestlog2")
+
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog1"))
+	operator.poll(context.Background())
+	expectNoTokens(t, emitCalls)
+	require.FileExists(t, path)
+
+	writeString(t, temp, "\n")
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog2"))
+	require.FileExists(t, path)
+
+	operator.poll(context.Background())
+	require.NoFileExists(t, path)
+}
+
+// TestDeleteAfterReadGzip tests that a compressed file is deleted once its
+// decompressed content has been emitted
+func TestDeleteAfterReadGzip(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := deleteAfterReadConfig(tempDir)
+	cfg.Compression = "auto"
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	path := filepath.Join(tempDir, "batch.log.gz")
+	require.NoError(t, os.WriteFile(path, gzipBytes(t, "testlog1\ntestlog2\n"), 0600))
+
+	operator.poll(context.Background())
+	waitForTokens(t, emitCalls, [][]byte{[]byte("testlog1"), []byte("testlog2")})
+	operator.poll(context.Background())
+	require.NoFileExists(t, path)
+}
+
+func TestBuildDeleteAfterRead(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	notDir := filepath.Join(tempDir, "file")
+	require.NoError(t, os.WriteFile(notDir, nil, 0600))
+
+	cases := []struct {
+		name   string
+		modify func(*Config)
+		err    string
+	}{
+		{
+			name:   "Enabled",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name:   "Archive",
+			modify: func(cfg *Config) { cfg.DeleteAfterRead.ArchiveDir = tempDir },
+		},
+		{
+			name:   "StartAtEnd",
+			modify: func(cfg *Config) { cfg.StartAt = "end" },
+			err:    "`delete_after_read` requires `start_at: beginning`",
+		},
+		{
+			name: "ArchiveWithoutEnabled",
+			modify: func(cfg *Config) {
+				cfg.DeleteAfterRead.Enabled = false
+				cfg.DeleteAfterRead.ArchiveDir = tempDir
+			},
+			err: "`delete_after_read.archive_dir` requires `delete_after_read.enabled`",
+		},
+		{
+			name:   "DefaultMinIdle",
+			modify: func(cfg *Config) { cfg.DeleteAfterRead.MinIdle = 0 },
+		},
+		{
+			name:   "NegativeMinIdle",
+			modify: func(cfg *Config) { cfg.DeleteAfterRead.MinIdle = -time.Second },
+			err:    "`delete_after_read.min_idle` must not be negative",
+		},
+		{
+			name:   "ArchiveNotDirectory",
+			modify: func(cfg *Config) { cfg.DeleteAfterRead.ArchiveDir = notDir },
+			err:    "`delete_after_read.archive_dir` '" + notDir + "' is not a directory",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := deleteAfterReadConfig(tempDir)
+			tc.modify(cfg)
+			m, err := cfg.Build(testutil.Logger(t), func(_ context.Context, _ *FileAttributes, _ []byte) {})
+			if tc.err == "" {
+				require.NoError(t, err)
+				require.Positive(t, m.deleteAfterRead.MinIdle)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
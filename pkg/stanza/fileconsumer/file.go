@@ -37,11 +37,13 @@ type Manager struct {
 
 	readerFactory readerFactory
 	finder        Finder
+	ordering      *orderingFilter
 	roller        roller
 	persister     operator.Persister
 
-	pollInterval  time.Duration
-	maxBatchFiles int
+	pollInterval    time.Duration
+	maxBatchFiles   int
+	deleteAfterRead DeleteAfterRead
 
 	knownFiles []*Reader
 	seenPaths  map[string]struct{}
@@ -113,6 +115,9 @@ func (m *Manager) poll(ctx context.Context) {
 
 	// Get the list of paths on disk
 	matches := m.finder.FindFiles()
+	if m.ordering != nil {
+		matches = m.ordering.apply(matches)
+	}
 	for len(matches) > m.maxBatchFiles {
 		m.consume(ctx, matches[:m.maxBatchFiles])
 		matches = matches[m.maxBatchFiles:]
@@ -122,6 +127,7 @@ func (m *Manager) poll(ctx context.Context) {
 
 func (m *Manager) consume(ctx context.Context, paths []string) {
 	m.Debug("Consuming files")
+	start := time.Now()
 	readers := m.makeReaders(paths)
 
 	// take care of files which disappeared from the pattern since the last poll cycle
@@ -142,7 +148,12 @@ func (m *Manager) consume(ctx context.Context, paths []string) {
 	// Any new files that appear should be consumed entirely
 	m.readerFactory.fromBeginning = true
 
-	m.roller.roll(ctx, readers)
+	openReaders := readers
+	if m.deleteAfterRead.Enabled {
+		openReaders = m.removeReadFiles(readers, start)
+	}
+
+	m.roller.roll(ctx, openReaders)
 	m.saveCurrent(readers)
 	m.syncLastPollFiles(ctx)
 }
@@ -189,6 +200,9 @@ OUTER:
 			if err := files[i].Close(); err != nil {
 				m.Errorf("problem closing file", "file", files[i].Name())
 			}
+			if m.deleteAfterRead.Enabled {
+				m.removeEmptyFile(files[i].Name())
+			}
 			// Empty file, don't read it until we can compare its fingerprint
 			fps = append(fps[:i], fps[i+1:]...)
 			files = append(files[:i], files[i+1:]...)
@@ -1180,3 +1180,29 @@ func TestEncodings(t *testing.T) {
 		})
 	}
 }
+
+// TestOrderingCriteria tests that only the files selected by the ordering
+// criteria are read
+func TestOrderingCriteria(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.StartAt = "beginning"
+	cfg.OrderingCriteria = OrderingCriteria{
+		Regex:  `^app\.(?P<n>\d+)\.log$`,
+		SortBy: []SortRule{{RegexKey: "n", SortType: "numeric"}},
+	}
+	operator, emitCalls := buildTestManager(t, cfg)
+	operator.persister = testutil.NewMockPersister("test")
+	defer func() {
+		require.NoError(t, operator.Stop())
+	}()
+
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "app.9.log"), []byte("testlog9\n"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "app.10.log"), []byte("testlog10\n"), 0600))
+
+	operator.poll(context.Background())
+	waitForToken(t, emitCalls, []byte("testlog10"))
+	expectNoTokens(t, emitCalls)
+}
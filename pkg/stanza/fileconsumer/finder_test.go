@@ -137,7 +137,7 @@ func TestFinder(t *testing.T) {
 				require.NoError(t, os.WriteFile(f, []byte(filepath.Base(f)), 0000))
 			}
 
-			finder := Finder{include, exclude}
+			finder := Finder{Include: include, Exclude: exclude}
 			require.ElementsMatch(t, finder.FindFiles(), expected)
 		})
 	}
@@ -150,3 +150,178 @@ func absPath(tempDir string, files []string) []string {
 	}
 	return absFiles
 }
+
+func TestOrderingFilter(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		files    []string
+		criteria OrderingCriteria
+		expected []string
+	}{
+		{
+			name:  "NewestTimestamp",
+			files: []string{"app-2022-10-18T09.log", "app-2022-10-18T10.log", "app-2022-10-17T23.log"},
+			criteria: OrderingCriteria{
+				Regex:  `^app-(?P<ts>\d{4}-\d{2}-\d{2}T\d{2})\.log$`,
+				SortBy: []SortRule{{RegexKey: "ts", SortType: "timestamp", Layout: "%Y-%m-%dT%H"}},
+			},
+			expected: []string{"app-2022-10-18T10.log"},
+		},
+		{
+			name:  "TopTwoNumeric",
+			files: []string{"app.9.log", "app.10.log", "app.2.log", "other.log"},
+			criteria: OrderingCriteria{
+				Regex:  `^app\.(?P<n>\d+)\.log$`,
+				TopN:   2,
+				SortBy: []SortRule{{RegexKey: "n", SortType: "numeric"}},
+			},
+			expected: []string{"app.9.log", "app.10.log"},
+		},
+		{
+			name:  "Ascending",
+			files: []string{"app.9.log", "app.10.log", "app.2.log"},
+			criteria: OrderingCriteria{
+				Regex:  `^app\.(?P<n>\d+)\.log$`,
+				SortBy: []SortRule{{RegexKey: "n", SortType: "numeric", Ascending: true}},
+			},
+			expected: []string{"app.2.log"},
+		},
+		{
+			name:  "Alphabetical",
+			files: []string{"app-b.log", "app-c.log", "app-a.log"},
+			criteria: OrderingCriteria{
+				Regex:  `^app-(?P<name>\w+)\.log$`,
+				SortBy: []SortRule{{RegexKey: "name", SortType: "alphabetical"}},
+			},
+			expected: []string{"app-c.log"},
+		},
+		{
+			name: "GroupBy",
+			files: []string{
+				"api-20221017.log", "api-20221018.log",
+				"web-20221016.log", "web-20221015.log",
+				"db.log",
+			},
+			criteria: OrderingCriteria{
+				Regex:   `^(?P<service>\w+)-(?P<date>\d{8})\.log$`,
+				GroupBy: "service",
+				SortBy:  []SortRule{{RegexKey: "date", SortType: "timestamp", Layout: "%Y%m%d"}},
+			},
+			expected: []string{"api-20221018.log", "web-20221016.log"},
+		},
+		{
+			name:  "SecondRuleBreaksTies",
+			files: []string{"app-20221018-1.log", "app-20221018-2.log", "app-20221017-3.log"},
+			criteria: OrderingCriteria{
+				Regex: `^app-(?P<date>\d{8})-(?P<n>\d+)\.log$`,
+				SortBy: []SortRule{
+					{RegexKey: "date", SortType: "timestamp", Layout: "%Y%m%d"},
+					{RegexKey: "n", SortType: "numeric"},
+				},
+			},
+			expected: []string{"app-20221018-2.log"},
+		},
+		{
+			name:  "UnparsableValuesAreSkipped",
+			files: []string{"app-20221018.log", "app-20221399.log"},
+			criteria: OrderingCriteria{
+				Regex:  `^app-(?P<date>\d{8})\.log$`,
+				TopN:   2,
+				SortBy: []SortRule{{RegexKey: "date", SortType: "timestamp", Layout: "%Y%m%d", Location: "America/New_York"}},
+			},
+			expected: []string{"app-20221018.log"},
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tempDir := t.TempDir()
+			files := absPath(tempDir, tc.files)
+			expected := absPath(tempDir, tc.expected)
+
+			for _, f := range files {
+				require.NoError(t, os.WriteFile(f, []byte(filepath.Base(f)), 0000))
+			}
+
+			filter, err := tc.criteria.build()
+			require.NoError(t, err)
+			finder := Finder{Include: []string{filepath.Join(tempDir, "*")}}
+			require.ElementsMatch(t, expected, filter.apply(finder.FindFiles()))
+		})
+	}
+}
+
+func TestOrderingCriteriaBuild(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		name     string
+		criteria OrderingCriteria
+		err      string
+	}{
+		{
+			name:     "Empty",
+			criteria: OrderingCriteria{},
+		},
+		{
+			name:     "MissingRegex",
+			criteria: OrderingCriteria{TopN: 2},
+			err:      "`ordering_criteria.regex` is required",
+		},
+		{
+			name:     "InvalidRegex",
+			criteria: OrderingCriteria{Regex: "("},
+			err:      "compile `ordering_criteria.regex`: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name:     "NegativeTopN",
+			criteria: OrderingCriteria{Regex: `(?P<n>\d+)`, TopN: -1},
+			err:      "`ordering_criteria.top_n` must not be negative",
+		},
+		{
+			name:     "UnknownGroupBy",
+			criteria: OrderingCriteria{Regex: `(?P<n>\d+)`, GroupBy: "service"},
+			err:      "`ordering_criteria.group_by` 'service' is not a capture group of the regex",
+		},
+		{
+			name:     "MissingSortBy",
+			criteria: OrderingCriteria{Regex: `(?P<n>\d+)`},
+			err:      "`ordering_criteria.sort_by` is required",
+		},
+		{
+			name:     "UnknownRegexKey",
+			criteria: OrderingCriteria{Regex: `(?P<n>\d+)`, SortBy: []SortRule{{RegexKey: "m", SortType: "numeric"}}},
+			err:      "`regex_key` 'm' is not a capture group of the regex",
+		},
+		{
+			name:     "InvalidSortType",
+			criteria: OrderingCriteria{Regex: `(?P<n>\d+)`, SortBy: []SortRule{{RegexKey: "n", SortType: "random"}}},
+			err:      "invalid `sort_type` 'random'",
+		},
+		{
+			name:     "MissingLayout",
+			criteria: OrderingCriteria{Regex: `(?P<n>\d+)`, SortBy: []SortRule{{RegexKey: "n", SortType: "timestamp"}}},
+			err:      "`layout` is required to sort by timestamp",
+		},
+		{
+			name:     "InvalidLocation",
+			criteria: OrderingCriteria{Regex: `(?P<n>\d+)`, SortBy: []SortRule{{RegexKey: "n", SortType: "timestamp", Layout: "%Y", Location: "Mars/Olympus"}}},
+			err:      "load location: unknown time zone Mars/Olympus",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := tc.criteria.build()
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fileconsumer // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer"
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	strptime "github.com/observiq/ctimefmt"
+)
+
+const (
+	sortTypeNumeric      = "numeric"
+	sortTypeAlphabetical = "alphabetical"
+	sortTypeTimestamp    = "timestamp"
+
+	defaultOrderingTopN = 1
+)
+
+// OrderingCriteria selects, among the files matched by the include and
+// exclude globs, the first TopN files of each group once sorted by the
+// values captured from their names.
+type OrderingCriteria struct {
+	// Regex is matched against the file names, files that do not match it
+	// are not consumed.
+	Regex string `mapstructure:"regex,omitempty" json:"regex,omitempty" yaml:"regex,omitempty"`
+	// TopN is the number of files kept per group, 1 by default.
+	TopN int `mapstructure:"top_n,omitempty" json:"top_n,omitempty" yaml:"top_n,omitempty"`
+	// GroupBy is the name of the capture group whose value groups files.
+	// All the files belong to the same group when empty.
+	GroupBy string `mapstructure:"group_by,omitempty" json:"group_by,omitempty" yaml:"group_by,omitempty"`
+	// SortBy are the sort rules, the first one taking precedence.
+	SortBy []SortRule `mapstructure:"sort_by,omitempty" json:"sort_by,omitempty" yaml:"sort_by,omitempty"`
+}
+
+// SortRule sorts files by the value of a capture group of the ordering regex.
+type SortRule struct {
+	RegexKey  string `mapstructure:"regex_key,omitempty" json:"regex_key,omitempty" yaml:"regex_key,omitempty"`
+	SortType  string `mapstructure:"sort_type,omitempty" json:"sort_type,omitempty" yaml:"sort_type,omitempty"`
+	Ascending bool   `mapstructure:"ascending,omitempty" json:"ascending,omitempty" yaml:"ascending,omitempty"`
+	// Layout is the strptime layout of timestamp values.
+	Layout string `mapstructure:"layout,omitempty" json:"layout,omitempty" yaml:"layout,omitempty"`
+	// Location is the time zone of timestamp values, UTC by default.
+	Location string `mapstructure:"location,omitempty" json:"location,omitempty" yaml:"location,omitempty"`
+}
+
+// orderingFilter is the validated form of OrderingCriteria.
+type orderingFilter struct {
+	regex   *regexp.Regexp
+	topN    int
+	groupBy int
+	rules   []sortRule
+}
+
+type sortRule struct {
+	index     int
+	sortType  string
+	ascending bool
+	layout    string
+	location  *time.Location
+}
+
+// sortValue holds the parsed value of a capture group, only the field
+// matching the sort type is set.
+type sortValue struct {
+	number int64
+	text   string
+	time   time.Time
+}
+
+type orderedFile struct {
+	position int
+	values   []sortValue
+}
+
+func (c OrderingCriteria) build() (*orderingFilter, error) {
+	if c.Regex == "" {
+		if c.TopN != 0 || c.GroupBy != "" || len(c.SortBy) != 0 {
+			return nil, fmt.Errorf("`ordering_criteria.regex` is required")
+		}
+		return nil, nil
+	}
+
+	regex, err := regexp.Compile(c.Regex)
+	if err != nil {
+		return nil, fmt.Errorf("compile `ordering_criteria.regex`: %w", err)
+	}
+	f := &orderingFilter{
+		regex:   regex,
+		topN:    c.TopN,
+		groupBy: -1,
+	}
+
+	if c.TopN < 0 {
+		return nil, fmt.Errorf("`ordering_criteria.top_n` must not be negative")
+	} else if c.TopN == 0 {
+		f.topN = defaultOrderingTopN
+	}
+
+	if c.GroupBy != "" {
+		if f.groupBy = regex.SubexpIndex(c.GroupBy); f.groupBy < 0 {
+			return nil, fmt.Errorf("`ordering_criteria.group_by` '%s' is not a capture group of the regex", c.GroupBy)
+		}
+	}
+
+	if len(c.SortBy) == 0 {
+		return nil, fmt.Errorf("`ordering_criteria.sort_by` is required")
+	}
+	for _, rule := range c.SortBy {
+		r := sortRule{
+			index:     regex.SubexpIndex(rule.RegexKey),
+			sortType:  rule.SortType,
+			ascending: rule.Ascending,
+			location:  time.UTC,
+		}
+		if r.index < 0 {
+			return nil, fmt.Errorf("`regex_key` '%s' is not a capture group of the regex", rule.RegexKey)
+		}
+		switch rule.SortType {
+		case sortTypeNumeric, sortTypeAlphabetical:
+		case sortTypeTimestamp:
+			if rule.Layout == "" {
+				return nil, fmt.Errorf("`layout` is required to sort by timestamp")
+			}
+			if r.layout, err = strptime.ToNative(rule.Layout); err != nil {
+				return nil, fmt.Errorf("parse strptime layout: %w", err)
+			}
+			if rule.Location != "" {
+				if r.location, err = time.LoadLocation(rule.Location); err != nil {
+					return nil, fmt.Errorf("load location: %w", err)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("invalid `sort_type` '%s'", rule.SortType)
+		}
+		f.rules = append(f.rules, r)
+	}
+	return f, nil
+}
+
+// apply returns the selected paths in their original order. Paths whose
+// name does not match the regex or whose captured values cannot be parsed
+// are left out.
+func (f *orderingFilter) apply(paths []string) []string {
+	groups := make(map[string][]orderedFile)
+	var groupNames []string
+	for position, path := range paths {
+		matches := f.regex.FindStringSubmatch(filepath.Base(path))
+		if matches == nil {
+			continue
+		}
+		file, ok := f.parse(position, matches)
+		if !ok {
+			continue
+		}
+		var group string
+		if f.groupBy >= 0 {
+			group = matches[f.groupBy]
+		}
+		if _, ok := groups[group]; !ok {
+			groupNames = append(groupNames, group)
+		}
+		groups[group] = append(groups[group], file)
+	}
+
+	var selected []int
+	for _, group := range groupNames {
+		files := groups[group]
+		sort.SliceStable(files, func(i, j int) bool {
+			return f.less(files[i], files[j])
+		})
+		if len(files) > f.topN {
+			files = files[:f.topN]
+		}
+		for _, file := range files {
+			selected = append(selected, file.position)
+		}
+	}
+
+	sort.Ints(selected)
+	result := make([]string, 0, len(selected))
+	for _, position := range selected {
+		result = append(result, paths[position])
+	}
+	return result
+}
+
+func (f *orderingFilter) parse(position int, matches []string) (orderedFile, bool) {
+	file := orderedFile{
+		position: position,
+		values:   make([]sortValue, len(f.rules)),
+	}
+	for i, rule := range f.rules {
+		value := matches[rule.index]
+		switch rule.sortType {
+		case sortTypeNumeric:
+			number, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return file, false
+			}
+			file.values[i].number = number
+		case sortTypeAlphabetical:
+			file.values[i].text = value
+		case sortTypeTimestamp:
+			ts, err := time.ParseInLocation(rule.layout, value, rule.location)
+			if err != nil {
+				return file, false
+			}
+			file.values[i].time = ts
+		}
+	}
+	return file, true
+}
+
+// less orders files by the first rule telling them apart.
+func (f *orderingFilter) less(a, b orderedFile) bool {
+	for i, rule := range f.rules {
+		var cmp int
+		switch rule.sortType {
+		case sortTypeNumeric:
+			switch {
+			case a.values[i].number < b.values[i].number:
+				cmp = -1
+			case a.values[i].number > b.values[i].number:
+				cmp = 1
+			}
+		case sortTypeAlphabetical:
+			cmp = strings.Compare(a.values[i].text, b.values[i].text)
+		case sortTypeTimestamp:
+			switch {
+			case a.values[i].time.Before(b.values[i].time):
+				cmp = -1
+			case a.values[i].time.After(b.values[i].time):
+				cmp = 1
+			}
+		}
+		if cmp != 0 {
+			return (cmp < 0) == rule.ascending
+		}
+	}
+	return false
+}
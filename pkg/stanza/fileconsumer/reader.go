@@ -17,7 +17,9 @@ package fileconsumer // import "github.com/open-telemetry/opentelemetry-collecto
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -27,6 +29,7 @@ import (
 type readerConfig struct {
 	fingerprintSize int
 	maxLogSize      int
+	compression     string
 	emit            EmitFunc
 }
 
@@ -41,10 +44,39 @@ type Reader struct {
 	generation     int
 	file           *os.File
 	fileAttributes *FileAttributes
+
+	// gzip is set when the file is compressed, the offset and fingerprint
+	// are then tracked over the decompressed content.
+	gzip       bool
+	gzipReader io.Reader
+	// gzipReadSize is the size of the compressed file when its content was
+	// last read to the end, so that unchanged files are not decompressed
+	// again on every poll.
+	gzipReadSize int64
+	// readToEOF is set when the whole content of the file has been
+	// emitted, eofSize is then the size of the file and eofTime the time
+	// at which its last entry was emitted.
+	readToEOF bool
+	eofSize   int64
+	eofTime   time.Time
 }
 
 // offsetToEnd sets the starting offset
 func (r *Reader) offsetToEnd() error {
+	if r.gzip {
+		gz, err := newGzipReader(r.file)
+		if err != nil {
+			return fmt.Errorf("gzip: %w", err)
+		}
+		defer gz.Close()
+		size, err := io.Copy(io.Discard, gz)
+		if err != nil {
+			return fmt.Errorf("gzip: %w", err)
+		}
+		r.Offset = size
+		return nil
+	}
+
 	info, err := r.file.Stat()
 	if err != nil {
 		return fmt.Errorf("stat: %w", err)
@@ -55,12 +87,41 @@ func (r *Reader) offsetToEnd() error {
 
 // ReadToEnd will read until the end of the file
 func (r *Reader) ReadToEnd(ctx context.Context) {
-	if _, err := r.file.Seek(r.Offset, 0); err != nil {
+	var gzipSize int64
+	if r.gzip {
+		info, err := r.file.Stat()
+		if err != nil {
+			r.Errorw("Failed to stat", zap.Error(err))
+			return
+		}
+		gzipSize = info.Size()
+		if gzipSize == r.gzipReadSize {
+			return
+		}
+
+		gz, err := newGzipReader(r.file)
+		if err != nil {
+			r.Errorw("Failed to read gzip header", zap.Error(err))
+			return
+		}
+		defer gz.Close()
+		// The decompressed content cannot be seeked.
+		if _, err = io.CopyN(io.Discard, gz, r.Offset); err != nil {
+			r.Errorw("Failed to skip to offset", zap.Error(err))
+			return
+		}
+		r.gzipReader = gz
+		defer func() { r.gzipReader = nil }()
+	} else if _, err := r.file.Seek(r.Offset, 0); err != nil {
 		r.Errorw("Failed to seek", zap.Error(err))
 		return
 	}
+	wasReadToEOF := r.readToEOF
+	r.readToEOF = false
+	emitted := false
 
-	scanner := NewPositionalScanner(r, r.maxLogSize, r.Offset, r.splitter.SplitFunc)
+	src := &countingReader{Reader: r, count: r.Offset}
+	scanner := NewPositionalScanner(src, r.maxLogSize, r.Offset, r.splitter.SplitFunc)
 
 	// Iterate over the tokenized file, emitting entries as we go
 	for {
@@ -74,6 +135,22 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		if !ok {
 			if err := scanner.getError(); err != nil {
 				r.Errorw("Failed during scan", zap.Error(err))
+				break
+			}
+			if r.gzip {
+				r.gzipReadSize = gzipSize
+			}
+			// A trailing entry that is not terminated yet is not emitted.
+			if r.Offset == src.count {
+				r.readToEOF = true
+				if r.gzip {
+					r.eofSize = gzipSize
+				} else {
+					r.eofSize = src.count
+				}
+				if emitted || !wasReadToEOF {
+					r.eofTime = time.Now()
+				}
 			}
 			break
 		}
@@ -83,6 +160,7 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 			r.Errorw("decode: %w", zap.Error(err))
 		} else {
 			r.emit(ctx, r.fileAttributes, token)
+			emitted = true
 		}
 
 		r.Offset = scanner.Pos()
@@ -100,12 +178,17 @@ func (r *Reader) Close() {
 
 // Read from the file and update the fingerprint if necessary
 func (r *Reader) Read(dst []byte) (int, error) {
+	var src io.Reader = r.file
+	if r.gzipReader != nil {
+		src = r.gzipReader
+	}
+
 	// Skip if fingerprint is already built
 	// or if fingerprint is behind Offset
 	if len(r.Fingerprint.FirstBytes) == r.fingerprintSize || int(r.Offset) > len(r.Fingerprint.FirstBytes) {
-		return r.file.Read(dst)
+		return src.Read(dst)
 	}
-	n, err := r.file.Read(dst)
+	n, err := src.Read(dst)
 	appendCount := min0(n, r.fingerprintSize-int(r.Offset))
 	// return for n == 0 or r.Offset >= r.fileInput.fingerprintSize
 	if appendCount == 0 {
@@ -117,6 +200,18 @@ func (r *Reader) Read(dst []byte) (int, error) {
 	return n, err
 }
 
+// countingReader counts the bytes read through it
+type countingReader struct {
+	io.Reader
+	count int64
+}
+
+func (c *countingReader) Read(dst []byte) (int, error) {
+	n, err := c.Reader.Read(dst)
+	c.count += int64(n)
+	return n, err
+}
+
 func min0(a, b int) int {
 	if a < 0 || b < 0 {
 		return 0
@@ -16,6 +16,7 @@ package fileconsumer // import "github.com/open-telemetry/opentelemetry-collecto
 
 import (
 	"os"
+	"time"
 
 	"go.uber.org/zap"
 
@@ -43,6 +44,8 @@ func (f *readerFactory) copy(old *Reader, newFile *os.File) (*Reader, error) {
 		withFingerprint(old.Fingerprint.Copy()).
 		withOffset(old.Offset).
 		withSplitter(old.splitter).
+		withGzipReadSize(old.gzipReadSize).
+		withEOF(old.readToEOF, old.eofSize, old.eofTime).
 		build()
 }
 
@@ -51,6 +54,9 @@ func (f *readerFactory) unsafeReader() (*Reader, error) {
 }
 
 func (f *readerFactory) newFingerprint(file *os.File) (*Fingerprint, error) {
+	if isGzip(file, f.readerConfig.compression) {
+		return newGzipFingerprint(file, f.readerConfig.fingerprintSize)
+	}
 	return NewFingerprint(file, f.readerConfig.fingerprintSize)
 }
 
@@ -60,6 +66,11 @@ type readerBuilder struct {
 	fp       *Fingerprint
 	offset   int64
 	splitter *helper.Splitter
+
+	gzipReadSize int64
+	readToEOF    bool
+	eofSize      int64
+	eofTime      time.Time
 }
 
 func (f *readerFactory) newReaderBuilder() *readerBuilder {
@@ -86,10 +97,26 @@ func (b *readerBuilder) withOffset(offset int64) *readerBuilder {
 	return b
 }
 
+func (b *readerBuilder) withGzipReadSize(size int64) *readerBuilder {
+	b.gzipReadSize = size
+	return b
+}
+
+func (b *readerBuilder) withEOF(readToEOF bool, size int64, eofTime time.Time) *readerBuilder {
+	b.readToEOF = readToEOF
+	b.eofSize = size
+	b.eofTime = eofTime
+	return b
+}
+
 func (b *readerBuilder) build() (r *Reader, err error) {
 	r = &Reader{
 		readerConfig: b.readerConfig,
 		Offset:       b.offset,
+		gzipReadSize: b.gzipReadSize,
+		readToEOF:    b.readToEOF,
+		eofSize:      b.eofSize,
+		eofTime:      b.eofTime,
 	}
 
 	if b.splitter != nil {
@@ -103,6 +130,7 @@ func (b *readerBuilder) build() (r *Reader, err error) {
 
 	if b.file != nil {
 		r.file = b.file
+		r.gzip = isGzip(b.file, b.readerConfig.compression)
 		r.SugaredLogger = b.SugaredLogger.With("path", b.file.Name())
 		r.fileAttributes, err = resolveFileAttributes(b.file.Name())
 		if err != nil {
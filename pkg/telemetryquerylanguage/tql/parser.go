@@ -240,7 +240,35 @@ func (p *Parser) ParseQueries(statements []string) ([]Query, error) {
 	return queries, nil
 }
 
-var parser = newParser()
+// ParseConditions parses boolean conditions, written like the where clause of a query
+// without the leading `where`, into evaluators.
+func (p *Parser) ParseConditions(conditions []string) ([]BoolExpressionEvaluator, error) {
+	var evaluators []BoolExpressionEvaluator
+	var errors error
+
+	for _, condition := range conditions {
+		parsed, err := parseCondition(condition)
+		if err != nil {
+			errors = multierr.Append(errors, err)
+			continue
+		}
+		evaluator, err := p.newBooleanExpressionEvaluator(parsed)
+		if err != nil {
+			errors = multierr.Append(errors, err)
+			continue
+		}
+		evaluators = append(evaluators, evaluator)
+	}
+
+	if errors != nil {
+		return nil, errors
+	}
+	return evaluators, nil
+}
+
+var parser = newParser[ParsedQuery]()
+
+var conditionParser = newParser[BooleanExpression]()
 
 func parseQuery(raw string) (*ParsedQuery, error) {
 	parsed, err := parser.ParseString("", raw)
@@ -250,6 +278,14 @@ func parseQuery(raw string) (*ParsedQuery, error) {
 	return parsed, nil
 }
 
+func parseCondition(raw string) (*BooleanExpression, error) {
+	parsed, err := conditionParser.ParseString("", raw)
+	if err != nil {
+		return nil, err
+	}
+	return parsed, nil
+}
+
 // buildLexer constructs a SimpleLexer definition.
 // Note that the ordering of these rules matters.
 // It's in a separate function so it can be easily tested alone (see lexer_test.go).
@@ -272,11 +308,11 @@ func buildLexer() *lexer.StatefulDefinition {
 	})
 }
 
-// newParser returns a parser that can be used to read a string into a ParsedQuery. An error will be returned if the string
-// is not formatted for the DSL.
-func newParser() *participle.Parser[ParsedQuery] {
+// newParser returns a parser that can be used to read a string into a ParsedQuery, or into any other part of the
+// grammar such as a BooleanExpression. An error will be returned if the string is not formatted for the DSL.
+func newParser[G any]() *participle.Parser[G] {
 	lex := buildLexer()
-	parser, err := participle.Build[ParsedQuery](
+	parser, err := participle.Build[G](
 		participle.Lexer(lex),
 		participle.Unquote("String"),
 		participle.Elide("whitespace"),
@@ -840,3 +840,51 @@ func Test_parseQuery(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseCondition(t *testing.T) {
+	tests := []struct {
+		condition string
+		wantErr   bool
+	}{
+		{`name == "foo"`, false},
+		{`attributes["http.status_code"] >= 500 and resource.attributes["env"] == "prod"`, false},
+		{`(name == "foo" or name == "bar") and kind != SPAN_KIND_SERVER`, false},
+		{`IsMatch(name, "^GET") == true`, false},
+		{`true`, false},
+		{`set(name, "foo")`, true},
+		{`set(name, "foo") where name == "bar"`, true},
+		{`where name == "foo"`, true},
+		{`name`, true},
+		{`name ==`, true},
+	}
+	pat := regexp.MustCompile("[^a-zA-Z0-9]+")
+	for _, tt := range tests {
+		name := pat.ReplaceAllString(tt.condition, "_")
+		t.Run(name, func(t *testing.T) {
+			_, err := parseCondition(tt.condition)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseCondition(%s) error = %v, wantErr %v", tt.condition, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_ParseConditions(t *testing.T) {
+	p := NewParser(nil, testParsePath, testParseEnum, NoOpLogger{})
+
+	evaluators, err := p.ParseConditions([]string{
+		`name == "foo"`,
+		`name == "bar" or name == "foo" and name != "baz"`,
+		`name > "foo"`,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, evaluators, 3)
+
+	ctx := tqltest.TestTransformContext{Item: "foo"}
+	assert.True(t, evaluators[0](ctx))
+	assert.True(t, evaluators[1](ctx))
+	assert.False(t, evaluators[2](ctx))
+
+	_, err = p.ParseConditions([]string{`name == "foo"`, `name ==`, `unknown == "foo"`})
+	assert.Error(t, err)
+}
@@ -45,6 +45,16 @@ type Config struct {
 	// StoreOnDisk tells the processor to keep only the trace ID in memory, serializing the trace spans to disk.
 	// Useful when the duration to wait for traces to complete is high.
 	// Default: false.
-	// Not yet implemented, and an error will be returned when this option is used.
+	// Not yet implemented, and an error will be returned when this option is used. Use StorageID instead.
 	StoreOnDisk bool `mapstructure:"store_on_disk"`
+
+	// StorageID is the ID of a storage extension the traces are kept in, instead of memory.
+	// The traces kept in the storage survive restarts, after which their wait duration starts over.
+	// Default: none, the traces are kept in memory.
+	StorageID *config.ComponentID `mapstructure:"storage"`
+
+	// MaxStorageSizeMiB is the maximum size in MiB of the spans kept in the storage extension.
+	// Spans that would exceed it are dropped. Zero means no limit.
+	// Default: 1024.
+	MaxStorageSizeMiB int64 `mapstructure:"max_storage_size_mib"`
 }
@@ -41,6 +41,9 @@ const (
 
 	// traceID to be removed
 	traceRemoved
+
+	// traceID kept by the storage from a previous run
+	traceRestored
 )
 
 var (
@@ -87,6 +90,7 @@ type eventMachine struct {
 	onTraceExpired  func(traceID pcommon.TraceID, worker *eventMachineWorker) error
 	onTraceReleased func(rss []ptrace.ResourceSpans) error
 	onTraceRemoved  func(traceID pcommon.TraceID) error
+	onTraceRestored func(traceID pcommon.TraceID, worker *eventMachineWorker) error
 
 	onError func(event)
 
@@ -216,6 +220,22 @@ func (em *eventMachine) handleEvent(e event, w *eventMachineWorker) {
 		em.handleEventWithObservability("onTraceRemoved", func() error {
 			return em.onTraceRemoved(payload)
 		})
+	case traceRestored:
+		if em.onTraceRestored == nil {
+			em.logger.Debug("onTraceRestored not set, skipping event")
+			em.callOnError(e)
+			return
+		}
+		payload, ok := e.payload.(pcommon.TraceID)
+		if !ok {
+			// the payload had an unexpected type!
+			em.callOnError(e)
+			return
+		}
+
+		em.handleEventWithObservability("onTraceRestored", func() error {
+			return em.onTraceRestored(payload, w)
+		})
 	default:
 		em.logger.Info("unknown event type", zap.Any("event", e.typ))
 		em.callOnError(e)
@@ -230,18 +250,29 @@ func (em *eventMachine) consume(td ptrace.Traces) error {
 		return fmt.Errorf("eventmachine consume failed: %w", err)
 	}
 
+	em.workerFor(traceID).fire(event{
+		typ:     traceReceived,
+		payload: tracesWithID{id: traceID, td: td},
+	})
+	return nil
+}
+
+// restore routes the ID of a trace kept by the storage from a previous run to the worker owning it.
+func (em *eventMachine) restore(traceID pcommon.TraceID) {
+	em.workerFor(traceID).fire(event{
+		typ:     traceRestored,
+		payload: traceID,
+	})
+}
+
+func (em *eventMachine) workerFor(traceID pcommon.TraceID) *eventMachineWorker {
 	var bucket uint64
 	if len(em.workers) != 1 {
 		bucket = workerIndexForTraceID(traceID, len(em.workers))
 	}
 
 	em.logger.Debug("scheduled trace to worker", zap.Uint64("id", bucket))
-
-	em.workers[bucket].fire(event{
-		typ:     traceReceived,
-		payload: tracesWithID{id: traceID, td: td},
-	})
-	return nil
+	return em.workers[bucket]
 }
 
 func workerIndexForTraceID(traceID pcommon.TraceID, numWorkers int) uint64 {
@@ -84,6 +84,18 @@ func TestEventCallback(t *testing.T) {
 				}
 			},
 		},
+		{
+			casename: "onTraceRestored",
+			typ:      traceRestored,
+			payload:  pcommon.TraceID([16]byte{1, 2, 3, 4}),
+			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
+				em.onTraceRestored = func(restored pcommon.TraceID, worker *eventMachineWorker) error {
+					wg.Done()
+					assert.Equal(t, pcommon.TraceID([16]byte{1, 2, 3, 4}), restored)
+					return nil
+				}
+			},
+		},
 	} {
 		t.Run(tt.casename, func(t *testing.T) {
 			// prepare
@@ -131,6 +143,10 @@ func TestEventCallbackNotSet(t *testing.T) {
 			casename: "onTraceRemoved",
 			typ:      traceRemoved,
 		},
+		{
+			casename: "onTraceRestored",
+			typ:      traceRestored,
+		},
 	} {
 		t.Run(tt.casename, func(t *testing.T) {
 			// prepare
@@ -199,6 +215,15 @@ func TestEventInvalidPayload(t *testing.T) {
 				}
 			},
 		},
+		{
+			casename: "onTraceRestored",
+			typ:      traceRestored,
+			registerCallback: func(em *eventMachine, wg *sync.WaitGroup) {
+				em.onTraceRestored = func(restored pcommon.TraceID, worker *eventMachineWorker) error {
+					return nil
+				}
+			},
+		},
 	} {
 		t.Run(tt.casename, func(t *testing.T) {
 			// prepare
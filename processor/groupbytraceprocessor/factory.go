@@ -36,10 +36,12 @@ const (
 	defaultNumWorkers     = 1
 	defaultDiscardOrphans = false
 	defaultStoreOnDisk    = false
+
+	defaultMaxStorageSizeMiB = 1024
 )
 
 var (
-	errDiskStorageNotSupported    = fmt.Errorf("option 'disk storage' not supported in this release")
+	errDiskStorageNotSupported    = fmt.Errorf("option 'disk storage' not supported in this release, use 'storage' instead")
 	errDiscardOrphansNotSupported = fmt.Errorf("option 'discard orphans' not supported in this release")
 )
 
@@ -65,6 +67,8 @@ func createDefaultConfig() config.Processor {
 		// not supported for now
 		DiscardOrphans: defaultDiscardOrphans,
 		StoreOnDisk:    defaultStoreOnDisk,
+
+		MaxStorageSizeMiB: defaultMaxStorageSizeMiB,
 	}
 }
 
@@ -85,8 +89,11 @@ func createTracesProcessor(
 		return nil, errDiscardOrphansNotSupported
 	}
 
-	// the only supported storage for now
-	st = newMemoryStorage()
+	if oCfg.StorageID != nil {
+		st = newPersistentStorage(params.Logger, *oCfg.StorageID, oCfg.ID(), oCfg.MaxStorageSizeMiB*1024*1024)
+	} else {
+		st = newMemoryStorage()
+	}
 
 	return newGroupByTraceProcessor(params.Logger, st, nextConsumer, *oCfg), nil
 }
@@ -640,12 +640,15 @@ func (st *mockStorage) delete(traceID pcommon.TraceID) ([]ptrace.ResourceSpans,
 	}
 	return nil, nil
 }
-func (st *mockStorage) start() error {
+func (st *mockStorage) start(context.Context, component.Host) error {
 	if st.onStart != nil {
 		return st.onStart()
 	}
 	return nil
 }
+func (st *mockStorage) restore() ([]pcommon.TraceID, error) {
+	return nil, nil
+}
 func (st *mockStorage) shutdown() error {
 	if st.onShutdown != nil {
 		return st.onShutdown()
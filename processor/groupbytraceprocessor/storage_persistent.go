@@ -0,0 +1,133 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/groupbytraceprocessor"
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/tracestore"
+)
+
+// persistentStorage keeps the traces in a storage extension, so that they survive restarts.
+type persistentStorage struct {
+	logger *zap.Logger
+	store  *tracestore.Store
+
+	stopped                   bool
+	stoppedLock               sync.RWMutex
+	metricsCollectionInterval time.Duration
+}
+
+var _ storage = (*persistentStorage)(nil)
+
+func newPersistentStorage(logger *zap.Logger, storageID, componentID config.ComponentID, maxSize int64) *persistentStorage {
+	return &persistentStorage{
+		logger:                    logger,
+		store:                     tracestore.New(storageID, componentID, maxSize),
+		metricsCollectionInterval: time.Second,
+	}
+}
+
+func (st *persistentStorage) createOrAppend(traceID pcommon.TraceID, td ptrace.Traces) error {
+	// the arrival time isn't used by this processor
+	return st.store.Append(traceID, time.Time{}, td)
+}
+
+func (st *persistentStorage) get(traceID pcommon.TraceID) ([]ptrace.ResourceSpans, error) {
+	batches, err := st.store.Load(traceID)
+	if err != nil {
+		return nil, err
+	}
+	return resourceSpansOf(batches), nil
+}
+
+// delete removes the trace from the storage. A trace that cannot be read anymore is
+// removed too, and the error is returned.
+func (st *persistentStorage) delete(traceID pcommon.TraceID) ([]ptrace.ResourceSpans, error) {
+	batches, err := st.store.Take(traceID)
+	if err != nil {
+		return nil, err
+	}
+	return resourceSpansOf(batches), nil
+}
+
+// start opens the storage client and loads the traces stored by a previous run.
+func (st *persistentStorage) start(ctx context.Context, host component.Host) error {
+	if err := st.store.Start(ctx, host); err != nil {
+		return err
+	}
+	go st.periodicMetrics()
+	return nil
+}
+
+// restore returns the IDs of the traces left in the storage by a previous run.
+// The traces are kept in the storage until they are deleted.
+func (st *persistentStorage) restore() ([]pcommon.TraceID, error) {
+	var traceIDs []pcommon.TraceID
+	for traceID := range st.store.Traces() {
+		traceIDs = append(traceIDs, traceID)
+	}
+	return traceIDs, nil
+}
+
+func (st *persistentStorage) shutdown() error {
+	st.stoppedLock.Lock()
+	st.stopped = true
+	st.stoppedLock.Unlock()
+
+	return st.store.Shutdown(context.Background())
+}
+
+func (st *persistentStorage) periodicMetrics() {
+	stats.Record(context.Background(), mNumTracesInMemory.M(int64(st.count())))
+
+	st.stoppedLock.RLock()
+	stopped := st.stopped
+	st.stoppedLock.RUnlock()
+	if stopped {
+		return
+	}
+
+	time.AfterFunc(st.metricsCollectionInterval, func() {
+		st.periodicMetrics()
+	})
+}
+
+func (st *persistentStorage) count() int {
+	return st.store.Count()
+}
+
+// resourceSpansOf returns the resource spans of the batches of a trace, or nil when there are none.
+func resourceSpansOf(batches []ptrace.Traces) []ptrace.ResourceSpans {
+	if len(batches) == 0 {
+		return nil
+	}
+	var result []ptrace.ResourceSpans
+	for _, batch := range batches {
+		for i := 0; i < batch.ResourceSpans().Len(); i++ {
+			result = append(result, batch.ResourceSpans().At(i))
+		}
+	}
+	return result
+}
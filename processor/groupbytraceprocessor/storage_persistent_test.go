@@ -0,0 +1,264 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package groupbytraceprocessor
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	experimentalstorage "go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/tracestore"
+)
+
+func newStartedPersistentStorage(t *testing.T, storageDir string, maxSize int64) *persistentStorage {
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("test", storageDir)
+	st := newPersistentStorage(zap.NewNop(), storagetest.NewStorageID("test"), config.NewComponentID(typeStr), maxSize)
+	require.NoError(t, st.start(context.Background(), host))
+	return st
+}
+
+func TestPersistentCreateAndGetTrace(t *testing.T) {
+	// prepare
+	st := newStartedPersistentStorage(t, t.TempDir(), 0)
+	defer func() { assert.NoError(t, st.shutdown()) }()
+
+	traceIDs := []pcommon.TraceID{
+		pcommon.TraceID([16]byte{1, 2, 3, 4}),
+		pcommon.TraceID([16]byte{2, 3, 4, 5}),
+	}
+
+	// test
+	for _, traceID := range traceIDs {
+		assert.NoError(t, st.createOrAppend(traceID, simpleTracesWithID(traceID)))
+	}
+	assert.NoError(t, st.createOrAppend(traceIDs[0], simpleTracesWithID(traceIDs[0])))
+
+	// verify
+	assert.Equal(t, 2, st.count())
+	retrieved, err := st.get(traceIDs[0])
+	require.NoError(t, err)
+	assert.Len(t, retrieved, 2)
+	retrieved, err = st.get(traceIDs[1])
+	require.NoError(t, err)
+	require.Len(t, retrieved, 1)
+	assert.Equal(t, simpleTracesWithID(traceIDs[1]).ResourceSpans().At(0), retrieved[0])
+
+	retrieved, err = st.get(pcommon.TraceID([16]byte{9}))
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+}
+
+func TestPersistentDeleteTrace(t *testing.T) {
+	// prepare
+	st := newStartedPersistentStorage(t, t.TempDir(), 0)
+	defer func() { assert.NoError(t, st.shutdown()) }()
+
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	trace := simpleTracesWithID(traceID)
+	require.NoError(t, st.createOrAppend(traceID, trace))
+
+	// test
+	deleted, err := st.delete(traceID)
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, []ptrace.ResourceSpans{trace.ResourceSpans().At(0)}, deleted)
+	assert.Equal(t, 0, st.count())
+	assert.Zero(t, st.store.Size())
+
+	retrieved, err := st.get(traceID)
+	require.NoError(t, err)
+	assert.Nil(t, retrieved)
+
+	deleted, err = st.delete(traceID)
+	require.NoError(t, err)
+	assert.Nil(t, deleted)
+}
+
+func TestPersistentRestoreAfterRestart(t *testing.T) {
+	// prepare
+	dir := t.TempDir()
+	st := newStartedPersistentStorage(t, dir, 0)
+
+	kept := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	removed := pcommon.TraceID([16]byte{1, 3, 5, 7})
+	require.NoError(t, st.createOrAppend(kept, simpleTracesWithID(kept)))
+	require.NoError(t, st.createOrAppend(removed, simpleTracesWithID(removed)))
+	_, err := st.delete(removed)
+	require.NoError(t, err)
+	size := st.store.Size()
+	require.NoError(t, st.shutdown())
+
+	// test
+	st = newStartedPersistentStorage(t, dir, 0)
+	defer func() { assert.NoError(t, st.shutdown()) }()
+	restored, err := st.restore()
+
+	// verify
+	require.NoError(t, err)
+	assert.Equal(t, []pcommon.TraceID{kept}, restored)
+	assert.Equal(t, 1, st.count(), "restored traces must stay in the storage")
+	assert.Equal(t, size, st.store.Size())
+
+	// spans received after the restart are appended to the restored trace
+	require.NoError(t, st.createOrAppend(kept, simpleTracesWithID(kept)))
+	deleted, err := st.delete(kept)
+	require.NoError(t, err)
+	assert.Len(t, deleted, 2)
+	assert.Zero(t, st.store.Size())
+}
+
+func TestPersistentDeleteCorruptTrace(t *testing.T) {
+	// prepare
+	dir := t.TempDir()
+	st := newStartedPersistentStorage(t, dir, 0)
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	require.NoError(t, st.createOrAppend(traceID, simpleTracesWithID(traceID)))
+	require.NoError(t, st.shutdown())
+
+	// remove the batch of spans behind the back of the processor
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("test", dir)
+	ext := host.GetExtensions()[storagetest.NewStorageID("test")].(experimentalstorage.Extension)
+	client, err := ext.GetClient(context.Background(), component.KindProcessor, config.NewComponentID(typeStr), "")
+	require.NoError(t, err)
+	require.NoError(t, client.Delete(context.Background(), "trace_"+traceID.HexString()+"_0"))
+	require.NoError(t, client.Close(context.Background()))
+
+	st = newStartedPersistentStorage(t, dir, 0)
+	defer func() { assert.NoError(t, st.shutdown()) }()
+
+	// test
+	_, errGet := st.get(traceID)
+	_, errDelete := st.delete(traceID)
+
+	// verify
+	assert.ErrorIs(t, errGet, tracestore.ErrCorrupt)
+	assert.ErrorIs(t, errDelete, tracestore.ErrCorrupt)
+	assert.Equal(t, 0, st.count(), "unreadable traces must be removed")
+	assert.Zero(t, st.store.Size())
+}
+
+func TestPersistentStorageFull(t *testing.T) {
+	// prepare
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	data, err := ptrace.NewProtoMarshaler().MarshalTraces(simpleTracesWithID(traceID))
+	require.NoError(t, err)
+	st := newStartedPersistentStorage(t, t.TempDir(), int64(len(data)))
+	defer func() { assert.NoError(t, st.shutdown()) }()
+
+	// test
+	require.NoError(t, st.createOrAppend(traceID, simpleTracesWithID(traceID)))
+	errAppend := st.createOrAppend(traceID, simpleTracesWithID(traceID))
+	other := pcommon.TraceID([16]byte{2, 3, 4, 5})
+	errCreate := st.createOrAppend(other, simpleTracesWithID(other))
+
+	// verify
+	assert.ErrorIs(t, errAppend, tracestore.ErrFull)
+	assert.ErrorIs(t, errCreate, tracestore.ErrFull)
+	assert.Equal(t, 1, st.count())
+	retrieved, err := st.get(traceID)
+	require.NoError(t, err)
+	assert.Len(t, retrieved, 1)
+
+	// deleting the trace frees the space
+	_, err = st.delete(traceID)
+	require.NoError(t, err)
+	assert.NoError(t, st.createOrAppend(other, simpleTracesWithID(other)))
+}
+
+func TestPersistentStartWithInvalidExtension(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		host *storagetest.StorageHost
+	}{
+		{
+			name: "missing",
+			host: storagetest.NewStorageHost(),
+		},
+		{
+			name: "non-storage",
+			host: storagetest.NewStorageHost().WithNonStorageExtension("test"),
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			st := newPersistentStorage(zap.NewNop(), storagetest.NewNonStorageID("test"), config.NewComponentID(typeStr), 0)
+			assert.Error(t, st.start(context.Background(), tt.host))
+
+			// the storage can't be used until started
+			assert.ErrorIs(t, st.createOrAppend(pcommon.TraceID([16]byte{1}), simpleTraces()), tracestore.ErrClosed)
+			assert.NoError(t, st.shutdown())
+		})
+	}
+}
+
+func TestProcessorRestoresPersistedTraces(t *testing.T) {
+	// prepare
+	dir := t.TempDir()
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4})
+	st := newStartedPersistentStorage(t, dir, 0)
+	require.NoError(t, st.createOrAppend(traceID, simpleTracesWithID(traceID)))
+	require.NoError(t, st.shutdown())
+
+	received := make(chan ptrace.Traces, 1)
+	next := &mockProcessor{
+		onTraces: func(_ context.Context, td ptrace.Traces) error {
+			received <- td
+			return nil
+		},
+	}
+	cfg := Config{
+		WaitDuration: time.Millisecond,
+		NumTraces:    10,
+		NumWorkers:   1,
+	}
+	p := newGroupByTraceProcessor(zap.NewNop(), newPersistentStorage(zap.NewNop(), storagetest.NewStorageID("test"), config.NewComponentID(typeStr), 0), next, cfg)
+
+	// test
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("test", dir)
+	require.NoError(t, p.Start(context.Background(), host))
+	defer func() { assert.NoError(t, p.Shutdown(context.Background())) }()
+
+	// verify
+	select {
+	case td := <-received:
+		assert.Equal(t, simpleTracesWithID(traceID), td)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the restored trace wasn't released")
+	}
+	assert.Eventually(t, func() bool {
+		return p.st.(*persistentStorage).count() == 0
+	}, 5*time.Second, 10*time.Millisecond, "the released trace must be removed from the storage")
+}
+
+func TestCreateTestProcessorWithStorage(t *testing.T) {
+	c := createDefaultConfig().(*Config)
+	storageID := storagetest.NewStorageID("test")
+	c.StorageID = &storageID
+
+	p, err := createTracesProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), c, &mockProcessor{})
+	require.NoError(t, err)
+	assert.IsType(t, &persistentStorage{}, p.(*groupByTraceProcessor).st)
+}
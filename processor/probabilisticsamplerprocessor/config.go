@@ -15,9 +15,29 @@
 package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
 
 import (
+	"fmt"
+
 	"go.opentelemetry.io/collector/config"
 )
 
+// AttributeSource is the source of the value hashed to sample log records.
+type AttributeSource string
+
+const (
+	// traceIDAttributeSource hashes the trace ID of the log records.
+	traceIDAttributeSource = AttributeSource("traceID")
+	// recordAttributeSource hashes the value of the from_attribute attribute, or of the from_body_key
+	// body field, of the log records.
+	recordAttributeSource = AttributeSource("record")
+
+	defaultAttributeSource = traceIDAttributeSource
+)
+
+var validAttributeSource = map[AttributeSource]bool{
+	traceIDAttributeSource: true,
+	recordAttributeSource:  true,
+}
+
 // Config has the configuration guiding the trace sampler processor.
 type Config struct {
 	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
@@ -30,11 +50,39 @@ type Config struct {
 	// have different sampling rates: if they use the same seed all passing one layer may pass the other even if they have
 	// different sampling rates, configuring different seeds avoids that.
 	HashSeed uint32 `mapstructure:"hash_seed"`
+
+	// AttributeSource (logs only) defines where to look for the value hashed to make the sampling decision.
+	// With "traceID" (default) the trace ID of the log record is used, when the log record has no trace ID
+	// or with "record" the value of the log record attribute named by FromAttribute, or of the body field
+	// named by FromBodyKey, is used.
+	AttributeSource AttributeSource `mapstructure:"attribute_source"`
+
+	// FromAttribute (logs only) is the name of the log record attribute hashed to make the sampling decision,
+	// e.g. "log.file.name". It is required when AttributeSource is "record".
+	FromAttribute string `mapstructure:"from_attribute"`
+
+	// FromBodyKey (logs only) is the key of the field of a map body hashed to make the sampling decision,
+	// nested keys are separated by dots, e.g. "user.id". It cannot be set together with FromAttribute.
+	FromBodyKey string `mapstructure:"from_body_key"`
+
+	// SamplingPriority (logs only) is the name of a log record attribute whose value, when present, is used as
+	// the sampling percentage of that log record instead of SamplingPercentage. A value of 0 drops the record
+	// and a value greater or equal to 100 always samples it.
+	SamplingPriority string `mapstructure:"sampling_priority"`
 }
 
 var _ config.Processor = (*Config)(nil)
 
 // Validate checks if the processor configuration is valid
 func (cfg *Config) Validate() error {
+	if cfg.AttributeSource != "" && !validAttributeSource[cfg.AttributeSource] {
+		return fmt.Errorf("invalid attribute source: %v. Expected: %v or %v", cfg.AttributeSource, traceIDAttributeSource, recordAttributeSource)
+	}
+	if cfg.FromAttribute != "" && cfg.FromBodyKey != "" {
+		return fmt.Errorf("from_attribute and from_body_key cannot both be set")
+	}
+	if cfg.AttributeSource == recordAttributeSource && cfg.FromAttribute == "" && cfg.FromBodyKey == "" {
+		return fmt.Errorf("from_attribute or from_body_key must be set when attribute_source is %v", recordAttributeSource)
+	}
 	return nil
 }
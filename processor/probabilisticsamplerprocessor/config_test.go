@@ -37,6 +37,18 @@ func TestLoadConfig(t *testing.T) {
 				ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
 				SamplingPercentage: 15.3,
 				HashSeed:           22,
+				AttributeSource:    "traceID",
+			},
+		},
+		{
+			id: config.NewComponentIDWithName(typeStr, "logs"),
+			expected: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+				SamplingPercentage: 15.3,
+				HashSeed:           22,
+				AttributeSource:    "record",
+				FromAttribute:      "log.file.name",
+				SamplingPriority:   "priority",
 			},
 		},
 		{
@@ -62,3 +74,45 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     *Config
+		wantErr string
+	}{
+		{
+			name:    "invalid attribute source",
+			cfg:     &Config{AttributeSource: "body"},
+			wantErr: "invalid attribute source: body. Expected: traceID or record",
+		},
+		{
+			name:    "record without from_attribute",
+			cfg:     &Config{AttributeSource: recordAttributeSource},
+			wantErr: "from_attribute or from_body_key must be set when attribute_source is record",
+		},
+		{
+			name:    "from_attribute and from_body_key",
+			cfg:     &Config{FromAttribute: "log.file.name", FromBodyKey: "user.id"},
+			wantErr: "from_attribute and from_body_key cannot both be set",
+		},
+		{
+			name: "record with from_body_key",
+			cfg:  &Config{AttributeSource: recordAttributeSource, FromBodyKey: "user.id"},
+		},
+		{
+			name: "record with from_attribute",
+			cfg:  &Config{AttributeSource: recordAttributeSource, FromAttribute: "log.file.name"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+}
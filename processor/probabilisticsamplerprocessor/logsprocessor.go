@@ -0,0 +1,152 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/probabilisticsamplerprocessor"
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/processor/processorhelper"
+)
+
+type logSamplerProcessor struct {
+	scaledSamplingRate uint32
+	hashSeed           uint32
+	traceIDEnabled     bool
+	fromAttribute      string
+	fromBodyKey        []string
+	samplingPriority   string
+}
+
+// newLogsProcessor returns a processor.LogsProcessor that will perform head sampling according to the given
+// configuration.
+func newLogsProcessor(ctx context.Context, set component.ProcessorCreateSettings, cfg *Config, nextConsumer consumer.Logs) (component.LogsProcessor, error) {
+	lsp := &logSamplerProcessor{
+		scaledSamplingRate: uint32(cfg.SamplingPercentage * percentageScaleFactor),
+		hashSeed:           cfg.HashSeed,
+		traceIDEnabled:     cfg.AttributeSource != recordAttributeSource,
+		fromAttribute:      cfg.FromAttribute,
+		samplingPriority:   cfg.SamplingPriority,
+	}
+	if cfg.FromBodyKey != "" {
+		lsp.fromBodyKey = strings.Split(cfg.FromBodyKey, ".")
+	}
+
+	return processorhelper.NewLogsProcessor(
+		ctx,
+		set,
+		cfg,
+		nextConsumer,
+		lsp.processLogs,
+		processorhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}))
+}
+
+func (lsp *logSamplerProcessor) processLogs(_ context.Context, ld plog.Logs) (plog.Logs, error) {
+	ld.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+		rl.ScopeLogs().RemoveIf(func(sl plog.ScopeLogs) bool {
+			sl.LogRecords().RemoveIf(func(lr plog.LogRecord) bool {
+				rate := lsp.scaledSamplingRate
+				if lsp.samplingPriority != "" {
+					if priority, ok := lr.Attributes().Get(lsp.samplingPriority); ok {
+						if percentage, ok := parseSamplingPercentage(priority); ok {
+							rate = scaleSamplingPercentage(percentage)
+						}
+					}
+				}
+				return hash(lsp.hashKey(lr), lsp.hashSeed)&bitMaskHashBuckets >= rate
+			})
+			// Filter out empty ScopeLogs
+			return sl.LogRecords().Len() == 0
+		})
+		// Filter out empty ResourceLogs
+		return rl.ScopeLogs().Len() == 0
+	})
+	if ld.ResourceLogs().Len() == 0 {
+		return ld, processorhelper.ErrSkipProcessingData
+	}
+	return ld, nil
+}
+
+// hashKey returns the value hashed to make the sampling decision of the log record: its trace ID
+// when enabled and set, otherwise the value of the configured attribute or body field. Log records
+// without any of them all hash the same empty key, so they are either all sampled or all dropped.
+func (lsp *logSamplerProcessor) hashKey(lr plog.LogRecord) []byte {
+	if tid := lr.TraceID(); lsp.traceIDEnabled && !tid.IsEmpty() {
+		return tid[:]
+	}
+	if lsp.fromAttribute != "" {
+		if v, ok := lr.Attributes().Get(lsp.fromAttribute); ok {
+			return valueBytes(v)
+		}
+	}
+	if lsp.fromBodyKey != nil {
+		if v, ok := bodyField(lr.Body(), lsp.fromBodyKey); ok {
+			return valueBytes(v)
+		}
+	}
+	return nil
+}
+
+// bodyField returns the value of the nested field of a map body.
+func bodyField(body pcommon.Value, keys []string) (pcommon.Value, bool) {
+	v := body
+	for _, key := range keys {
+		if v.Type() != pcommon.ValueTypeMap {
+			return pcommon.Value{}, false
+		}
+		var ok bool
+		if v, ok = v.MapVal().Get(key); !ok {
+			return pcommon.Value{}, false
+		}
+	}
+	return v, true
+}
+
+func valueBytes(v pcommon.Value) []byte {
+	if v.Type() == pcommon.ValueTypeBytes {
+		return v.BytesVal().AsRaw()
+	}
+	return []byte(v.AsString())
+}
+
+// parseSamplingPercentage reads the sampling percentage from the value of a sampling priority attribute.
+func parseSamplingPercentage(v pcommon.Value) (float64, bool) {
+	switch v.Type() {
+	case pcommon.ValueTypeInt:
+		return float64(v.IntVal()), true
+	case pcommon.ValueTypeDouble:
+		return v.DoubleVal(), true
+	case pcommon.ValueTypeString:
+		if f, err := strconv.ParseFloat(v.StringVal(), 64); err == nil {
+			return f, true
+		}
+	}
+	return 0, false
+}
+
+func scaleSamplingPercentage(percentage float64) uint32 {
+	if percentage <= 0 {
+		return 0
+	}
+	if percentage >= 100 {
+		return numHashBuckets
+	}
+	return uint32(percentage * percentageScaleFactor)
+}
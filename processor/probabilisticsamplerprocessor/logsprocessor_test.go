@@ -0,0 +1,269 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package probabilisticsamplerprocessor
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/idutils"
+)
+
+func TestNewLogsProcessor(t *testing.T) {
+	tests := []struct {
+		name         string
+		nextConsumer consumer.Logs
+		cfg          *Config
+		wantErr      bool
+	}{
+		{
+			name: "nil_nextConsumer",
+			cfg: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+				SamplingPercentage: 15.5,
+			},
+			wantErr: true,
+		},
+		{
+			name:         "happy_path",
+			nextConsumer: consumertest.NewNop(),
+			cfg: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+				SamplingPercentage: 15.5,
+			},
+		},
+		{
+			name:         "happy_path_record_attribute",
+			nextConsumer: consumertest.NewNop(),
+			cfg: &Config{
+				ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+				SamplingPercentage: 13.33,
+				HashSeed:           4321,
+				AttributeSource:    recordAttributeSource,
+				FromAttribute:      "log.file.name",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), tt.cfg, tt.nextConsumer)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.NotNil(t, got)
+			}
+		})
+	}
+}
+
+func Test_logsamplerprocessor_SamplingPercentageRange(t *testing.T) {
+	const numRecords = 10000
+	tests := []struct {
+		name              string
+		cfg               Config
+		fill              func(i int, lr plog.LogRecord)
+		expectedRecordMin int
+		expectedRecordMax int
+	}{
+		{
+			name: "trace_id_25",
+			cfg:  Config{SamplingPercentage: 25, AttributeSource: traceIDAttributeSource},
+			fill: func(i int, lr plog.LogRecord) {
+				lr.SetTraceID(idutils.UInt64ToTraceID(uint64(i), uint64(i)))
+			},
+			expectedRecordMin: 2300,
+			expectedRecordMax: 2700,
+		},
+		{
+			name: "trace_id_fallback_to_attribute",
+			cfg:  Config{SamplingPercentage: 50, AttributeSource: traceIDAttributeSource, FromAttribute: "id"},
+			fill: func(i int, lr plog.LogRecord) {
+				lr.Attributes().UpsertString("id", fmt.Sprint(i))
+			},
+			expectedRecordMin: 4700,
+			expectedRecordMax: 5300,
+		},
+		{
+			name: "record_attribute_10",
+			cfg:  Config{SamplingPercentage: 10, AttributeSource: recordAttributeSource, FromAttribute: "id"},
+			fill: func(i int, lr plog.LogRecord) {
+				lr.SetTraceID(idutils.UInt64ToTraceID(1, 1))
+				lr.Attributes().UpsertInt("id", int64(i))
+			},
+			expectedRecordMin: 850,
+			expectedRecordMax: 1150,
+		},
+		{
+			name: "record_body_key_10",
+			cfg:  Config{SamplingPercentage: 10, AttributeSource: recordAttributeSource, FromBodyKey: "user.id"},
+			fill: func(i int, lr plog.LogRecord) {
+				lr.SetTraceID(idutils.UInt64ToTraceID(1, 1))
+				lr.Body().SetEmptyMapVal().UpsertEmptyMap("user").UpsertInt("id", int64(i))
+			},
+			expectedRecordMin: 850,
+			expectedRecordMax: 1150,
+		},
+		{
+			name: "sample_all",
+			cfg:  Config{SamplingPercentage: 100, AttributeSource: traceIDAttributeSource},
+			fill: func(i int, lr plog.LogRecord) {
+				lr.SetTraceID(idutils.UInt64ToTraceID(uint64(i), uint64(i)))
+			},
+			expectedRecordMin: numRecords,
+			expectedRecordMax: numRecords,
+		},
+		{
+			name: "sample_none",
+			cfg:  Config{SamplingPercentage: 0, AttributeSource: traceIDAttributeSource},
+			fill: func(i int, lr plog.LogRecord) {
+				lr.SetTraceID(idutils.UInt64ToTraceID(uint64(i), uint64(i)))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(consumertest.LogsSink)
+			tt.cfg.ProcessorSettings = config.NewProcessorSettings(config.NewComponentID(typeStr))
+			lp, err := newLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), &tt.cfg, sink)
+			require.NoError(t, err)
+
+			ld := plog.NewLogs()
+			records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+			for i := 0; i < numRecords; i++ {
+				tt.fill(i, records.AppendEmpty())
+			}
+			require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+
+			sampled := sink.LogRecordCount()
+			assert.GreaterOrEqual(t, sampled, tt.expectedRecordMin)
+			assert.LessOrEqual(t, sampled, tt.expectedRecordMax)
+		})
+	}
+}
+
+func Test_logsamplerprocessor_ConsistentDecision(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings:  config.NewProcessorSettings(config.NewComponentID(typeStr)),
+		SamplingPercentage: 50,
+		AttributeSource:    recordAttributeSource,
+		FromAttribute:      "log.file.name",
+	}
+	sink := new(consumertest.LogsSink)
+	lp, err := newLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+	require.NoError(t, err)
+
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for i := 0; i < 1000; i++ {
+		lr := records.AppendEmpty()
+		lr.Attributes().UpsertString("log.file.name", fmt.Sprintf("file-%d.log", i%10))
+		lr.Body().SetStringVal(fmt.Sprint(i))
+	}
+	require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+
+	// All the records of a file must share the same sampling decision.
+	counts := map[string]int{}
+	for _, sampled := range sink.AllLogs() {
+		records := sampled.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+		for i := 0; i < records.Len(); i++ {
+			name, _ := records.At(i).Attributes().Get("log.file.name")
+			counts[name.StringVal()]++
+		}
+	}
+	for name, count := range counts {
+		assert.Equal(t, 100, count, "all records of %s must be sampled", name)
+	}
+}
+
+func Test_logsamplerprocessor_SamplingPriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority pcommon.Value
+		sampled  bool
+	}{
+		{
+			name:     "int_zero_drops",
+			priority: pcommon.NewValueInt(0),
+		},
+		{
+			name:     "int_hundred_samples",
+			priority: pcommon.NewValueInt(100),
+			sampled:  true,
+		},
+		{
+			name:     "double_above_hundred_samples",
+			priority: pcommon.NewValueDouble(1000),
+			sampled:  true,
+		},
+		{
+			name:     "string_hundred_samples",
+			priority: pcommon.NewValueString("100"),
+			sampled:  true,
+		},
+		{
+			name:     "invalid_string_uses_sampling_percentage",
+			priority: pcommon.NewValueString("high"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
+				AttributeSource:   traceIDAttributeSource,
+				SamplingPriority:  "priority",
+			}
+			sink := new(consumertest.LogsSink)
+			lp, err := newLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, sink)
+			require.NoError(t, err)
+
+			ld := plog.NewLogs()
+			lr := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+			lr.SetTraceID(idutils.UInt64ToTraceID(1, 2))
+			lr.Attributes().Insert("priority", tt.priority)
+			require.NoError(t, lp.ConsumeLogs(context.Background(), ld))
+
+			if tt.sampled {
+				assert.Equal(t, 1, sink.LogRecordCount())
+			} else {
+				assert.Equal(t, 0, sink.LogRecordCount())
+			}
+		})
+	}
+}
+
+func Test_logsamplerprocessor_hashKeyFromBody(t *testing.T) {
+	lsp := &logSamplerProcessor{fromBodyKey: []string{"user", "id"}}
+
+	lr := plog.NewLogRecord()
+	lr.Body().SetEmptyMapVal().UpsertEmptyMap("user").UpsertString("id", "alice")
+	assert.Equal(t, []byte("alice"), lsp.hashKey(lr))
+
+	lr.Body().SetEmptyMapVal().UpsertString("user", "alice")
+	assert.Nil(t, lsp.hashKey(lr), "user is not a map")
+
+	lr.Body().SetStringVal("alice")
+	assert.Nil(t, lsp.hashKey(lr), "the body is not a map")
+}
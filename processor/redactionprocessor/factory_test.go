@@ -40,3 +40,25 @@ func TestCreateTestProcessor(t *testing.T) {
 	assert.NotNil(t, tp)
 	assert.Equal(t, true, tp.Capabilities().MutatesData)
 }
+
+func TestCreateTestLogsProcessor(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
+	}
+
+	lp, err := createLogsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, consumertest.NewNop())
+	assert.NoError(t, err)
+	assert.NotNil(t, lp)
+	assert.Equal(t, true, lp.Capabilities().MutatesData)
+}
+
+func TestCreateTestMetricsProcessor(t *testing.T) {
+	cfg := &Config{
+		ProcessorSettings: config.NewProcessorSettings(config.NewComponentID(typeStr)),
+	}
+
+	mp, err := createMetricsProcessor(context.Background(), componenttest.NewNopProcessorCreateSettings(), cfg, consumertest.NewNop())
+	assert.NoError(t, err)
+	assert.NotNil(t, mp)
+	assert.Equal(t, true, mp.Capabilities().MutatesData)
+}
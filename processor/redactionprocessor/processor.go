@@ -24,13 +24,18 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
 )
 
-const attrValuesSeparator = ","
-
-var _ component.TracesProcessor = (*redaction)(nil)
+const (
+	attrValuesSeparator = ","
+	// bodyKey is the key used in the summary attributes for the body of a
+	// log record, the keys of a map body are listed with this prefix
+	bodyKey = "body"
+)
 
 type redaction struct {
 	// Attribute keys allowed in a span
@@ -41,12 +46,10 @@ type redaction struct {
 	config *Config
 	// Logger
 	logger *zap.Logger
-	// Next trace consumer in line
-	next consumer.Traces
 }
 
 // newRedaction creates a new instance of the redaction processor
-func newRedaction(ctx context.Context, config *Config, logger *zap.Logger, next consumer.Traces) (*redaction, error) {
+func newRedaction(ctx context.Context, config *Config, logger *zap.Logger) (*redaction, error) {
 	allowList := makeAllowList(config)
 	blockRegexList, err := makeBlockRegexList(ctx, config)
 	if err != nil {
@@ -59,11 +62,10 @@ func newRedaction(ctx context.Context, config *Config, logger *zap.Logger, next
 		blockRegexList: blockRegexList,
 		config:         config,
 		logger:         logger,
-		next:           next,
 	}, nil
 }
 
-// processTraces implements ProcessMetricsFunc. It processes the incoming data
+// processTraces implements ProcessTracesFunc. It processes the incoming data
 // and returns the data to be sent to the next component
 func (s *redaction) processTraces(ctx context.Context, batch ptrace.Traces) (ptrace.Traces, error) {
 	for i := 0; i < batch.ResourceSpans().Len(); i++ {
@@ -93,11 +95,113 @@ func (s *redaction) processResourceSpan(ctx context.Context, rs ptrace.ResourceS
 	}
 }
 
-// processAttrs redacts the attributes of a resource span or a span
+// processLogs implements ProcessLogsFunc. It processes the incoming data
+// and returns the data to be sent to the next component
+func (s *redaction) processLogs(ctx context.Context, logs plog.Logs) (plog.Logs, error) {
+	for i := 0; i < logs.ResourceLogs().Len(); i++ {
+		rl := logs.ResourceLogs().At(i)
+		s.processAttrs(ctx, rl.Resource().Attributes())
+
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			records := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				s.processLogRecord(ctx, records.At(k))
+			}
+		}
+	}
+	return logs, nil
+}
+
+// processLogRecord redacts the attributes and the body of a log record. A map
+// body is redacted like attributes and a string body is masked. The changes
+// made to the body are summarized in the attributes of the log record.
+func (s *redaction) processLogRecord(_ context.Context, record plog.LogRecord) {
+	attributes := record.Attributes()
+	toDelete, toBlock := s.redactAttrs(attributes)
+
+	body := record.Body()
+	switch body.Type() {
+	case pcommon.ValueTypeString:
+		if s.maskValue(body) {
+			toBlock = append(toBlock, bodyKey)
+		}
+	case pcommon.ValueTypeMap:
+		bodyDelete, bodyBlock := s.redactAttrs(body.MapVal())
+		for _, k := range bodyDelete {
+			toDelete = append(toDelete, bodyKey+"."+k)
+		}
+		for _, k := range bodyBlock {
+			toBlock = append(toBlock, bodyKey+"."+k)
+		}
+	}
+
+	// Add diagnostic information to the log record
+	s.addMetaAttrs(toDelete, attributes, redactedKeys, redactedKeyCount)
+	s.addMetaAttrs(toBlock, attributes, maskedValues, maskedValueCount)
+}
+
+// processMetrics implements ProcessMetricsFunc. It processes the incoming data
+// and returns the data to be sent to the next component
+func (s *redaction) processMetrics(ctx context.Context, metrics pmetric.Metrics) (pmetric.Metrics, error) {
+	for i := 0; i < metrics.ResourceMetrics().Len(); i++ {
+		rm := metrics.ResourceMetrics().At(i)
+		s.processAttrs(ctx, rm.Resource().Attributes())
+
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			ms := rm.ScopeMetrics().At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				s.processMetric(ctx, ms.At(k))
+			}
+		}
+	}
+	return metrics, nil
+}
+
+// processMetric redacts the attributes of the data points of a metric
+func (s *redaction) processMetric(ctx context.Context, metric pmetric.Metric) {
+	switch metric.DataType() {
+	case pmetric.MetricDataTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			s.processAttrs(ctx, dps.At(i).Attributes())
+		}
+	case pmetric.MetricDataTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			s.processAttrs(ctx, dps.At(i).Attributes())
+		}
+	case pmetric.MetricDataTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			s.processAttrs(ctx, dps.At(i).Attributes())
+		}
+	case pmetric.MetricDataTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			s.processAttrs(ctx, dps.At(i).Attributes())
+		}
+	case pmetric.MetricDataTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			s.processAttrs(ctx, dps.At(i).Attributes())
+		}
+	}
+}
+
+// processAttrs redacts the attributes of a resource, a span, a log record or
+// a metric data point and adds the summary of its changes to them
 func (s *redaction) processAttrs(_ context.Context, attributes pcommon.Map) {
 	// TODO: Use the context for recording metrics
-	var toDelete []string
-	var toBlock []string
+	toDelete, toBlock := s.redactAttrs(attributes)
+
+	// Add diagnostic information to the attributes
+	s.addMetaAttrs(toDelete, attributes, redactedKeys, redactedKeyCount)
+	s.addMetaAttrs(toBlock, attributes, maskedValues, maskedValueCount)
+}
+
+// redactAttrs deletes the attributes that are not allowed, masks the blocked
+// values of the other ones and returns the keys it deleted and masked
+func (s *redaction) redactAttrs(attributes pcommon.Map) (toDelete []string, toBlock []string) {
 
 	// Identify attributes to redact and mask in the following sequence
 	// 1. Make a list of attribute keys to redact
@@ -118,15 +222,8 @@ func (s *redaction) processAttrs(_ context.Context, attributes pcommon.Map) {
 		}
 
 		// Mask any blocked values for the other attributes
-		strVal := value.StringVal()
-		for _, compiledRE := range s.blockRegexList {
-			match := compiledRE.MatchString(strVal)
-			if match {
-				toBlock = append(toBlock, k)
-
-				maskedValue := compiledRE.ReplaceAllString(strVal, "****")
-				value.SetStringVal(maskedValue)
-			}
+		if s.maskValue(value) {
+			toBlock = append(toBlock, k)
 		}
 		return true
 	})
@@ -135,20 +232,24 @@ func (s *redaction) processAttrs(_ context.Context, attributes pcommon.Map) {
 	for _, k := range toDelete {
 		attributes.Remove(k)
 	}
-	// Add diagnostic information to the span
-	s.addMetaAttrs(toDelete, attributes, redactedKeys, redactedKeyCount)
-	s.addMetaAttrs(toBlock, attributes, maskedValues, maskedValueCount)
+	return toDelete, toBlock
 }
 
-// ConsumeTraces implements the SpanProcessor interface
-func (s *redaction) ConsumeTraces(ctx context.Context, batch ptrace.Traces) error {
-	batch, err := s.processTraces(ctx, batch)
-	if err != nil {
-		return err
+// maskValue masks the parts of the value that match a blocked value and
+// reports whether the value was masked
+func (s *redaction) maskValue(value pcommon.Value) bool {
+	strVal := value.StringVal()
+	masked := false
+	for _, compiledRE := range s.blockRegexList {
+		if compiledRE.MatchString(strVal) {
+			masked = true
+			strVal = compiledRE.ReplaceAllString(strVal, "****")
+		}
 	}
-
-	err = s.next.ConsumeTraces(ctx, batch)
-	return err
+	if masked {
+		value.SetStringVal(strVal)
+	}
+	return masked
 }
 
 // addMetaAttrs adds diagnostic information about redacted or masked attribute keys
@@ -25,14 +25,15 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap/zaptest"
 )
 
 func TestCapabilities(t *testing.T) {
 	config := &Config{}
-	next := new(consumertest.TracesSink)
-	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t), next)
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
 	assert.NoError(t, err)
 
 	cap := processor.Capabilities()
@@ -41,8 +42,7 @@ func TestCapabilities(t *testing.T) {
 
 func TestStartShutdown(t *testing.T) {
 	config := &Config{}
-	next := new(consumertest.TracesSink)
-	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t), next)
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
 	assert.NoError(t, err)
 
 	ctx := context.Background()
@@ -351,6 +351,29 @@ func TestMultipleBlockValues(t *testing.T) {
 	assert.Equal(t, "mystery ****", mysteryValue.StringVal())
 }
 
+// TestMultipleBlockValuesInOneValue validates that all the blocked values
+// found in a value are masked, and that its key is reported once
+func TestMultipleBlockValuesInOneValue(t *testing.T) {
+	config := &Config{AllowedKeys: []string{"name"},
+		BlockedValues: []string{"4[0-9]{12}(?:[0-9]{3})?", "(5[1-5][0-9]{3})"},
+		Summary:       "debug"}
+	masked := map[string]pcommon.Value{
+		"name": pcommon.NewValueString("visa 4111111111111111 mastercard 52000"),
+	}
+
+	_, _, next := runTest(t, map[string]pcommon.Value{}, map[string]pcommon.Value{}, masked, config)
+
+	attr := next.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Attributes()
+	nameValue, _ := attr.Get("name")
+	assert.Equal(t, "visa **** mastercard ****", nameValue.StringVal())
+	maskedValues, ok := attr.Get(maskedValues)
+	assert.True(t, ok)
+	assert.Equal(t, "name", maskedValues.StringVal())
+	maskedValueCount, ok := attr.Get(maskedValueCount)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), maskedValueCount.IntVal())
+}
+
 // TestProcessAttrsAppliedTwice validates a use case when data is coming through redaction processor more than once.
 // Existing attributes must be updated, not overridden or ignored.
 func TestProcessAttrsAppliedTwice(t *testing.T) {
@@ -359,7 +382,7 @@ func TestProcessAttrsAppliedTwice(t *testing.T) {
 		BlockedValues: []string{"4[0-9]{12}(?:[0-9]{3})?"},
 		Summary:       "debug",
 	}
-	processor, err := newRedaction(context.TODO(), config, zaptest.NewLogger(t), consumertest.NewNop())
+	processor, err := newRedaction(context.TODO(), config, zaptest.NewLogger(t))
 	require.NoError(t, err)
 
 	attrs := pcommon.NewMap()
@@ -390,6 +413,110 @@ func TestProcessAttrsAppliedTwice(t *testing.T) {
 	assert.Equal(t, int64(2), val.IntVal())
 }
 
+// TestRedactLogs validates that the processor redacts the attributes and
+// masks the string body of log records
+func TestRedactLogs(t *testing.T) {
+	config := &Config{
+		AllowedKeys:   []string{"id", "name"},
+		BlockedValues: []string{"4[0-9]{12}(?:[0-9]{3})?"},
+		Summary:       "debug",
+	}
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	logs := plog.NewLogs()
+	rl := logs.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().UpsertString("credit_card", "4111111111111111")
+	record := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.Body().SetStringVal("payment with 4111111111111111 accepted")
+	record.Attributes().UpsertInt("id", 5)
+	record.Attributes().UpsertString("name", "placeholder 4111111111111111")
+	record.Attributes().UpsertString("email", "user@example.com")
+
+	_, err = processor.processLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	_, ok := rl.Resource().Attributes().Get("credit_card")
+	assert.False(t, ok)
+	assert.Equal(t, "payment with **** accepted", record.Body().StringVal())
+	assert.Equal(t, map[string]interface{}{
+		"id":             int64(5),
+		"name":           "placeholder ****",
+		redactedKeys:     "email",
+		redactedKeyCount: int64(1),
+		maskedValues:     "body,name",
+		maskedValueCount: int64(2),
+	}, record.Attributes().AsRaw())
+}
+
+// TestRedactLogsMapBody validates that the processor redacts the keys of a
+// map body like attributes and summarizes them in the log record attributes
+func TestRedactLogsMapBody(t *testing.T) {
+	config := &Config{
+		AllowedKeys:   []string{"id", "message"},
+		BlockedValues: []string{"4[0-9]{12}(?:[0-9]{3})?"},
+		Summary:       "info",
+	}
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	logs := plog.NewLogs()
+	record := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	record.Body().SetEmptyMapVal().FromRaw(map[string]interface{}{
+		"id":          1,
+		"message":     "card 4111111111111111 declined",
+		"credit_card": "4111111111111111",
+	})
+
+	_, err = processor.processLogs(context.Background(), logs)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]interface{}{
+		"id":      int64(1),
+		"message": "card **** declined",
+	}, record.Body().MapVal().AsRaw())
+	assert.Equal(t, map[string]interface{}{
+		redactedKeyCount: int64(1),
+		maskedValueCount: int64(1),
+	}, record.Attributes().AsRaw())
+}
+
+// TestRedactMetrics validates that the processor redacts the attributes of
+// the data points of every metric type
+func TestRedactMetrics(t *testing.T) {
+	config := &Config{
+		AllowedKeys:   []string{"host", "user"},
+		BlockedValues: []string{"[a-z]+@example.com"},
+		Summary:       "silent",
+	}
+	processor, err := newRedaction(context.Background(), config, zaptest.NewLogger(t))
+	require.NoError(t, err)
+
+	metrics := pmetric.NewMetrics()
+	ms := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	var attrs []pcommon.Map
+	attrs = append(attrs, ms.AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty().Attributes())
+	attrs = append(attrs, ms.AppendEmpty().SetEmptySum().DataPoints().AppendEmpty().Attributes())
+	attrs = append(attrs, ms.AppendEmpty().SetEmptyHistogram().DataPoints().AppendEmpty().Attributes())
+	attrs = append(attrs, ms.AppendEmpty().SetEmptyExponentialHistogram().DataPoints().AppendEmpty().Attributes())
+	attrs = append(attrs, ms.AppendEmpty().SetEmptySummary().DataPoints().AppendEmpty().Attributes())
+	for _, attr := range attrs {
+		attr.UpsertString("host", "localhost")
+		attr.UpsertString("user", "user jdoe@example.com")
+		attr.UpsertString("ssn", "123-45-6789")
+	}
+
+	_, err = processor.processMetrics(context.Background(), metrics)
+	require.NoError(t, err)
+
+	for _, attr := range attrs {
+		assert.Equal(t, map[string]interface{}{
+			"host": "localhost",
+			"user": "user ****",
+		}, attr.AsRaw())
+	}
+}
+
 // runTest transforms the test input data and passes it through the processor
 func runTest(
 	t *testing.T,
@@ -426,9 +553,11 @@ func runTest(
 	// test
 	ctx := context.Background()
 	next := new(consumertest.TracesSink)
-	processor, err := newRedaction(ctx, config, zaptest.NewLogger(t), next)
+	processor, err := newRedaction(ctx, config, zaptest.NewLogger(t))
 	assert.NoError(t, err)
-	err = processor.ConsumeTraces(ctx, inBatch)
+	outBatch, err := processor.processTraces(ctx, inBatch)
+	assert.NoError(t, err)
+	err = next.ConsumeTraces(ctx, outBatch)
 
 	// verify
 	assert.NoError(t, err)
@@ -457,8 +586,7 @@ func BenchmarkRedactSummaryDebug(b *testing.B) {
 		"credit_card": pcommon.NewValueString("would be nice"),
 	}
 	ctx := context.Background()
-	next := new(consumertest.TracesSink)
-	processor, _ := newRedaction(ctx, config, zaptest.NewLogger(b), next)
+	processor, _ := newRedaction(ctx, config, zaptest.NewLogger(b))
 
 	for i := 0; i < b.N; i++ {
 		runBenchmark(allowed, redacted, masked, processor)
@@ -484,8 +612,7 @@ func BenchmarkMaskSummaryDebug(b *testing.B) {
 		"url":  pcommon.NewValueString("https://www.this_is_testing_url.com"),
 	}
 	ctx := context.Background()
-	next := new(consumertest.TracesSink)
-	processor, _ := newRedaction(ctx, config, zaptest.NewLogger(b), next)
+	processor, _ := newRedaction(ctx, config, zaptest.NewLogger(b))
 
 	for i := 0; i < b.N; i++ {
 		runBenchmark(allowed, nil, masked, processor)
@@ -519,5 +646,5 @@ func runBenchmark(
 		v.CopyTo(span.Attributes().UpsertEmpty(k))
 	}
 
-	_ = processor.ConsumeTraces(context.Background(), inBatch)
+	_, _ = processor.processTraces(context.Background(), inBatch)
 }
@@ -44,6 +44,12 @@ type Config struct {
 	// translated to, allowing older and newer formats
 	// to conform to the target schema identifier.
 	Targets []string `mapstructure:"targets"`
+
+	// CacheDirectory is a directory used to store the retrieved
+	// schema files so they survive restarts. Schema files already
+	// present in the directory are used without being fetched,
+	// which allows running without network access. (Optional field)
+	CacheDirectory string `mapstructure:"cache_directory"`
 }
 
 func (c *Config) Validate() error {
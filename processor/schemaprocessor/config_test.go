@@ -50,6 +50,7 @@ func TestLoadConfig(t *testing.T) {
 			"https://opentelemetry.io/schemas/1.4.2",
 			"https://example.com/otel/schemas/1.2.0",
 		},
+		CacheDirectory: "/var/lib/otelcol/schemas",
 	}, cfg)
 }
 
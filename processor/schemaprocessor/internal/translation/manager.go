@@ -0,0 +1,258 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+// retryInterval is the minimum time between two attempts to retrieve a
+// schema file that could not be retrieved, so that a missing schema file
+// does not cause a request for every batch of incoming data.
+const retryInterval = time.Minute
+
+var (
+	errNoProvider = errors.New("no schema provider set")
+	errNotAllowed = errors.New("schema is neither a target nor prefetched")
+)
+
+// Translator converts data published with a given schema URL to
+// the target schema URL of its family.
+type Translator struct {
+	translation *Translation
+	from, to    *Version
+	targetURL   string
+}
+
+// TargetURL is the schema URL of the translated data.
+func (t *Translator) TargetURL() string {
+	return t.targetURL
+}
+
+// ApplyResourceSpans translates the resource spans and updates their schema URL.
+func (t *Translator) ApplyResourceSpans(rs ptrace.ResourceSpans) {
+	t.translation.TranslateResourceSpans(rs, t.from, t.to)
+	rs.SetSchemaUrl(t.targetURL)
+}
+
+// ApplyResourceMetrics translates the resource metrics and updates their schema URL.
+func (t *Translator) ApplyResourceMetrics(rm pmetric.ResourceMetrics) {
+	t.translation.TranslateResourceMetrics(rm, t.from, t.to)
+	rm.SetSchemaUrl(t.targetURL)
+}
+
+// ApplyResourceLogs translates the resource logs and updates their schema URL.
+func (t *Translator) ApplyResourceLogs(rl plog.ResourceLogs) {
+	t.translation.TranslateResourceLogs(rl, t.from, t.to)
+	rl.SetSchemaUrl(t.targetURL)
+}
+
+// Manager keeps track of the schema targets and caches the translations
+// built from the schema files it retrieves. Only the schema files of the
+// targets and of the prefetched schema URLs are retrieved, so that incoming
+// data can not make the manager retrieve arbitrary schema URLs.
+type Manager struct {
+	log     *zap.Logger
+	targets map[string]*Version
+	now     func() time.Time
+
+	mu           sync.Mutex
+	provider     Provider
+	allowed      map[string]struct{}
+	translations map[string]*Translation
+	failures     map[string]time.Time
+	fetching     map[string]*fetchCall
+}
+
+// NewManager creates a manager for the target schema URLs, at most one per family.
+// No schema file is retrieved until a provider is set.
+func NewManager(targets []string, log *zap.Logger) (*Manager, error) {
+	m := &Manager{
+		log:          log,
+		targets:      make(map[string]*Version, len(targets)),
+		now:          time.Now,
+		allowed:      make(map[string]struct{}, len(targets)),
+		translations: make(map[string]*Translation),
+		failures:     make(map[string]time.Time),
+		fetching:     make(map[string]*fetchCall),
+	}
+	for _, target := range targets {
+		family, version, err := GetFamilyAndVersion(target)
+		if err != nil {
+			return nil, err
+		}
+		m.targets[family] = version
+		m.allowed[joinSchemaURL(family, version)] = struct{}{}
+	}
+	return m, nil
+}
+
+// SetProvider sets the provider used to retrieve schema files.
+func (m *Manager) SetProvider(provider Provider) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.provider = provider
+}
+
+// Prefetch retrieves and caches the schema file published at the schema URL.
+// Data published with a version newer than its target is only translated
+// when the schema URL of that version has been prefetched.
+func (m *Manager) Prefetch(ctx context.Context, schemaURL string) error {
+	m.mu.Lock()
+	m.allowed[schemaURL] = struct{}{}
+	m.mu.Unlock()
+	_, err := m.translation(ctx, schemaURL)
+	return err
+}
+
+// RequestTranslator returns the translator to use for data published with the
+// schema URL. A nil translator is returned when the data does not belong to
+// any of the target families or is already published with the target version.
+func (m *Manager) RequestTranslator(ctx context.Context, schemaURL string) (*Translator, error) {
+	if schemaURL == "" {
+		return nil, nil
+	}
+	family, from, err := GetFamilyAndVersion(schemaURL)
+	if err != nil {
+		return nil, err
+	}
+	to, ok := m.targets[family]
+	if !ok || from.Equal(to) {
+		return nil, nil
+	}
+
+	// A schema file contains every version up to its own, the file of the
+	// newest of the two versions is therefore able to translate between them.
+	newest := joinSchemaURL(family, to)
+	if from.GreaterThan(to) {
+		newest = joinSchemaURL(family, from)
+	}
+	m.mu.Lock()
+	_, allowed := m.allowed[newest]
+	m.mu.Unlock()
+	if !allowed {
+		return nil, fmt.Errorf("%w: %q", errNotAllowed, newest)
+	}
+	t, err := m.translation(ctx, newest)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range []*Version{from, to} {
+		if !t.SupportsVersion(v) {
+			return nil, fmt.Errorf("version %s is not defined by schema family %q", v, family)
+		}
+	}
+	return &Translator{
+		translation: t,
+		from:        from,
+		to:          to,
+		targetURL:   joinSchemaURL(family, to),
+	}, nil
+}
+
+func (m *Manager) translation(ctx context.Context, schemaURL string) (*Translation, error) {
+	m.mu.Lock()
+	if t, ok := m.translations[schemaURL]; ok {
+		m.mu.Unlock()
+		return t, nil
+	}
+	if failed, ok := m.failures[schemaURL]; ok && m.now().Sub(failed) < retryInterval {
+		m.mu.Unlock()
+		return nil, fmt.Errorf("schema %q recently failed to be retrieved", schemaURL)
+	}
+	if call, ok := m.fetching[schemaURL]; ok {
+		m.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.translation, call.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	// The lock is not held while retrieving the schema file, so that a slow
+	// schema URL does not block the requests for the cached ones.
+	call := &fetchCall{done: make(chan struct{})}
+	m.fetching[schemaURL] = call
+	provider := m.provider
+	m.mu.Unlock()
+
+	m.log.Info("Fetching schema", zap.String("schema-url", schemaURL))
+	call.translation, call.err = fetch(ctx, provider, schemaURL)
+
+	m.mu.Lock()
+	delete(m.fetching, schemaURL)
+	if call.err != nil {
+		m.log.Warn("Failed to retrieve schema", zap.String("schema-url", schemaURL), zap.Error(call.err))
+		// A cancelled request says nothing about the availability of the schema.
+		if ctx.Err() == nil {
+			m.recordFailure(schemaURL)
+		}
+	} else {
+		delete(m.failures, schemaURL)
+		m.translations[schemaURL] = call.translation
+	}
+	m.mu.Unlock()
+	close(call.done)
+	return call.translation, call.err
+}
+
+// recordFailure records the failed retrieval of the schema URL, and forgets
+// the failures that are old enough to be retried.
+func (m *Manager) recordFailure(schemaURL string) {
+	now := m.now()
+	for url, failed := range m.failures {
+		if now.Sub(failed) >= retryInterval {
+			delete(m.failures, url)
+		}
+	}
+	m.failures[schemaURL] = now
+}
+
+// fetchCall is an in-flight retrieval of a schema file, shared by the
+// concurrent requests for the same schema URL.
+type fetchCall struct {
+	done        chan struct{}
+	translation *Translation
+	err         error
+}
+
+func fetch(ctx context.Context, provider Provider, schemaURL string) (*Translation, error) {
+	if provider == nil {
+		return nil, errNoProvider
+	}
+	content, err := provider.Retrieve(ctx, schemaURL)
+	if err != nil {
+		return nil, err
+	}
+	t, err := NewTranslation(bytes.NewReader(content))
+	if err != nil {
+		return nil, fmt.Errorf("invalid schema %q: %w", schemaURL, err)
+	}
+	return t, nil
+}
+
+func joinSchemaURL(family string, v *Version) string {
+	return family + "/" + v.String()
+}
@@ -0,0 +1,237 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap/zaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/fixture"
+)
+
+type countingProvider struct {
+	calls   int64
+	content []byte
+	err     error
+}
+
+func (cp *countingProvider) Retrieve(_ context.Context, _ string) ([]byte, error) {
+	atomic.AddInt64(&cp.calls, 1)
+	return cp.content, cp.err
+}
+
+func newTestProvider(t *testing.T) *countingProvider {
+	content, err := os.ReadFile(filepath.Join("..", "..", "testdata", "schema.yml"))
+	require.NoError(t, err, "Must be able to read the test schema")
+	return &countingProvider{content: content}
+}
+
+func TestManagerRequestTranslator(t *testing.T) {
+	t.Parallel()
+
+	provider := newTestProvider(t)
+	m, err := NewManager([]string{"https://opentelemetry.io/schemas/1.1.0"}, zaptest.NewLogger(t))
+	require.NoError(t, err, "Must create a manager with valid targets")
+	m.SetProvider(provider)
+
+	tests := []struct {
+		scenario  string
+		schemaURL string
+		expectNil bool
+		expectErr bool
+	}{
+		{scenario: "no schema url", schemaURL: "", expectNil: true},
+		{scenario: "unknown family", schemaURL: "https://example.com/schemas/1.0.0", expectNil: true},
+		{scenario: "already at target", schemaURL: "https://opentelemetry.io/schemas/1.1.0", expectNil: true},
+		{scenario: "invalid schema url", schemaURL: "https://opentelemetry.io/schemas/latest", expectNil: true, expectErr: true},
+		{scenario: "unknown version", schemaURL: "https://opentelemetry.io/schemas/0.9.0", expectNil: true, expectErr: true},
+		{scenario: "newer version not prefetched", schemaURL: "https://opentelemetry.io/schemas/1.2.0", expectNil: true, expectErr: true},
+		{scenario: "older version", schemaURL: "https://opentelemetry.io/schemas/1.0.0"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.scenario, func(t *testing.T) {
+			tr, err := m.RequestTranslator(context.Background(), tc.schemaURL)
+			if tc.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			if tc.expectNil {
+				assert.Nil(t, tr)
+				return
+			}
+			require.NotNil(t, tr)
+			assert.Equal(t, "https://opentelemetry.io/schemas/1.1.0", tr.TargetURL())
+
+			rs := ptrace.NewResourceSpans()
+			rs.SetSchemaUrl(tc.schemaURL)
+			rs.Resource().Attributes().UpsertString("k8s.pod.name", "pod")
+			tr.ApplyResourceSpans(rs)
+			assert.Equal(t, tr.TargetURL(), rs.SchemaUrl())
+			assert.Equal(t, map[string]interface{}{"kubernetes.pod.name": "pod"}, rs.Resource().Attributes().AsRaw())
+		})
+	}
+	assert.Equal(t, int64(1), atomic.LoadInt64(&provider.calls), "Must only retrieve the schema once")
+}
+
+func TestManagerOnlyRetrievesAllowedSchemas(t *testing.T) {
+	t.Parallel()
+
+	provider := newTestProvider(t)
+	provider.err = errors.New("unavailable")
+	m, err := NewManager([]string{"https://opentelemetry.io/schemas/1.1.0"}, zaptest.NewLogger(t))
+	require.NoError(t, err, "Must create a manager with valid targets")
+	m.SetProvider(provider)
+
+	for _, version := range []string{"1.99.0", "1.99.1", "1.99.2"} {
+		_, err = m.RequestTranslator(context.Background(), "https://opentelemetry.io/schemas/"+version)
+		assert.ErrorIs(t, err, errNotAllowed)
+	}
+	assert.Zero(t, atomic.LoadInt64(&provider.calls), "Must not retrieve schemas that are not allowed")
+	assert.Empty(t, m.failures)
+
+	// A prefetched schema URL can be used to translate newer data.
+	assert.Error(t, m.Prefetch(context.Background(), "https://opentelemetry.io/schemas/1.99.0"))
+	_, err = m.RequestTranslator(context.Background(), "https://opentelemetry.io/schemas/1.99.0")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, errNotAllowed)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&provider.calls))
+}
+
+func TestManagerForgetsOldFailures(t *testing.T) {
+	t.Parallel()
+
+	provider := newTestProvider(t)
+	provider.err = errors.New("unavailable")
+	m, err := NewManager([]string{"https://opentelemetry.io/schemas/1.1.0"}, zaptest.NewLogger(t))
+	require.NoError(t, err, "Must create a manager with valid targets")
+	now := time.Now()
+	m.now = func() time.Time { return now }
+	m.SetProvider(provider)
+
+	assert.Error(t, m.Prefetch(context.Background(), "https://opentelemetry.io/schemas/1.1.0"))
+	now = now.Add(retryInterval)
+	assert.Error(t, m.Prefetch(context.Background(), "https://opentelemetry.io/schemas/1.2.0"))
+	assert.Equal(t, map[string]time.Time{"https://opentelemetry.io/schemas/1.2.0": now}, m.failures)
+}
+
+func TestManagerRetriesFailures(t *testing.T) {
+	t.Parallel()
+
+	provider := newTestProvider(t)
+	provider.err = errors.New("unavailable")
+
+	m, err := NewManager([]string{"https://opentelemetry.io/schemas/1.1.0"}, zaptest.NewLogger(t))
+	require.NoError(t, err, "Must create a manager with valid targets")
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	assert.ErrorIs(t, m.Prefetch(context.Background(), "https://opentelemetry.io/schemas/1.1.0"), errNoProvider)
+
+	m.SetProvider(provider)
+	now = now.Add(retryInterval)
+	assert.Error(t, m.Prefetch(context.Background(), "https://opentelemetry.io/schemas/1.1.0"))
+	assert.Error(t, m.Prefetch(context.Background(), "https://opentelemetry.io/schemas/1.1.0"))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&provider.calls), "Must not retry before the retry interval")
+
+	provider.err = nil
+	now = now.Add(retryInterval)
+	assert.NoError(t, m.Prefetch(context.Background(), "https://opentelemetry.io/schemas/1.1.0"))
+	assert.Equal(t, int64(2), atomic.LoadInt64(&provider.calls))
+}
+
+func TestManagerConcurrentRequests(t *testing.T) {
+	provider := newTestProvider(t)
+	m, err := NewManager([]string{"https://opentelemetry.io/schemas/1.1.0"}, zaptest.NewLogger(t))
+	require.NoError(t, err, "Must create a manager with valid targets")
+	m.SetProvider(provider)
+
+	fixture.ParallelRaceCompute(t, 10, func() error {
+		tr, err := m.RequestTranslator(context.Background(), "https://opentelemetry.io/schemas/1.0.0")
+		if err != nil {
+			return err
+		}
+		rs := ptrace.NewResourceSpans()
+		rs.Resource().Attributes().UpsertString("k8s.pod.name", "pod")
+		tr.ApplyResourceSpans(rs)
+		return nil
+	})
+	assert.Equal(t, int64(1), atomic.LoadInt64(&provider.calls), "Must only retrieve the schema once")
+}
+
+// blockingProvider blocks the retrieval of one schema URL until released.
+type blockingProvider struct {
+	*countingProvider
+	blockedURL string
+	release    chan struct{}
+}
+
+func (bp *blockingProvider) Retrieve(ctx context.Context, schemaURL string) ([]byte, error) {
+	if schemaURL == bp.blockedURL {
+		<-bp.release
+	}
+	return bp.countingProvider.Retrieve(ctx, schemaURL)
+}
+
+func TestManagerSlowSchemaDoesNotBlockCachedSchemas(t *testing.T) {
+	t.Parallel()
+
+	provider := &blockingProvider{
+		countingProvider: newTestProvider(t),
+		blockedURL:       "https://opentelemetry.io/schemas/1.2.0",
+		release:          make(chan struct{}),
+	}
+	m, err := NewManager([]string{"https://opentelemetry.io/schemas/1.1.0"}, zaptest.NewLogger(t))
+	require.NoError(t, err, "Must create a manager with valid targets")
+	m.SetProvider(provider)
+	require.NoError(t, m.Prefetch(context.Background(), "https://opentelemetry.io/schemas/1.1.0"))
+
+	// Concurrent requests for the slow schema share a single retrieval.
+	slow := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			slow <- m.Prefetch(context.Background(), "https://opentelemetry.io/schemas/1.2.0")
+		}()
+	}
+	require.Eventually(t, func() bool {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		return len(m.fetching) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	tr, err := m.RequestTranslator(context.Background(), "https://opentelemetry.io/schemas/1.0.0")
+	require.NoError(t, err, "Must not wait for the slow schema")
+	assert.NotNil(t, tr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, m.Prefetch(ctx, "https://opentelemetry.io/schemas/1.2.0"), context.Canceled)
+
+	close(provider.release)
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, <-slow)
+	}
+	assert.Equal(t, int64(2), atomic.LoadInt64(&provider.calls))
+}
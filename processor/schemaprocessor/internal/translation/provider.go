@@ -0,0 +1,122 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// maxSchemaSize limits the size of the schema files that are read.
+const maxSchemaSize = 10 * 1024 * 1024
+
+// Provider retrieves the content of the schema file published at a schema URL.
+type Provider interface {
+	Retrieve(ctx context.Context, schemaURL string) ([]byte, error)
+}
+
+type httpProvider struct {
+	client *http.Client
+}
+
+var _ Provider = (*httpProvider)(nil)
+
+// NewHTTPProvider returns a provider that downloads schema files using the client.
+func NewHTTPProvider(client *http.Client) Provider {
+	return &httpProvider{client: client}
+}
+
+func (hp *httpProvider) Retrieve(ctx context.Context, schemaURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, schemaURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := hp.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %q retrieving schema %q", resp.Status, schemaURL)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxSchemaSize))
+}
+
+type cacheProvider struct {
+	dir  string
+	next Provider
+}
+
+var _ Provider = (*cacheProvider)(nil)
+
+// NewCacheProvider returns a provider that reads schema files from dir, where a
+// schema URL such as https://opentelemetry.io/schemas/1.9.0 is stored as
+// <dir>/opentelemetry.io/schemas/1.9.0. Schema files that are missing from dir
+// are retrieved from next and stored in dir. A nil next only reads from dir,
+// which allows to run without network access from a pre-populated directory.
+func NewCacheProvider(dir string, next Provider) Provider {
+	return &cacheProvider{dir: dir, next: next}
+}
+
+func (cp *cacheProvider) Retrieve(ctx context.Context, schemaURL string) ([]byte, error) {
+	path, err := cp.path(schemaURL)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := os.ReadFile(path)
+	if err == nil || cp.next == nil || !errors.Is(err, os.ErrNotExist) {
+		return content, err
+	}
+
+	content, err = cp.next.Retrieve(ctx, schemaURL)
+	if err != nil {
+		return nil, err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	// Write to a temporary file first so that concurrent
+	// readers never observe a partially written schema file.
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".schema-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(content); err != nil {
+		_ = tmp.Close()
+		return nil, err
+	}
+	if err = tmp.Close(); err != nil {
+		return nil, err
+	}
+	return content, os.Rename(tmp.Name(), path)
+}
+
+func (cp *cacheProvider) path(schemaURL string) (string, error) {
+	u, err := url.Parse(schemaURL)
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(cp.dir, u.Host, filepath.FromSlash(filepath.Clean("/"+u.Path)))
+	return path, nil
+}
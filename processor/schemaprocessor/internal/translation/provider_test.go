@@ -0,0 +1,80 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPProvider(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(wr http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/schemas/1.1.0" {
+			wr.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := wr.Write([]byte("schema"))
+		assert.NoError(t, err, "Must not have issues writing schema content")
+	}))
+	defer srv.Close()
+
+	p := NewHTTPProvider(srv.Client())
+
+	content, err := p.Retrieve(context.Background(), srv.URL+"/schemas/1.1.0")
+	assert.NoError(t, err, "Must retrieve an existing schema")
+	assert.Equal(t, []byte("schema"), content)
+
+	_, err = p.Retrieve(context.Background(), srv.URL+"/schemas/1.2.0")
+	assert.Error(t, err, "Must error on a missing schema")
+}
+
+func TestCacheProvider(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	next := &countingProvider{content: []byte("schema")}
+	p := NewCacheProvider(dir, next)
+
+	for i := 0; i < 2; i++ {
+		content, err := p.Retrieve(context.Background(), "https://opentelemetry.io/schemas/1.1.0")
+		require.NoError(t, err, "Must retrieve the schema")
+		assert.Equal(t, []byte("schema"), content)
+	}
+	assert.Equal(t, int64(1), next.calls, "Must use the cached schema file")
+
+	content, err := os.ReadFile(filepath.Join(dir, "opentelemetry.io", "schemas", "1.1.0"))
+	require.NoError(t, err, "Must store the schema file in the cache directory")
+	assert.Equal(t, []byte("schema"), content)
+
+	offline := NewCacheProvider(dir, nil)
+	content, err = offline.Retrieve(context.Background(), "https://opentelemetry.io/schemas/1.1.0")
+	assert.NoError(t, err, "Must read cached schemas without a next provider")
+	assert.Equal(t, []byte("schema"), content)
+
+	_, err = offline.Retrieve(context.Background(), "https://opentelemetry.io/schemas/1.2.0")
+	assert.ErrorIs(t, err, os.ErrNotExist, "Must error on missing schemas without a next provider")
+
+	_, err = offline.Retrieve(context.Background(), "https://opentelemetry.io/../../etc/passwd")
+	assert.ErrorIs(t, err, os.ErrNotExist, "Must not read outside of the cache directory")
+}
@@ -0,0 +1,208 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
+
+import (
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/otel/schema/v1.0/ast"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/alias"
+)
+
+// renames holds a mapping of names in both directions, upgrade maps the names
+// used by the previous version to the names used starting from the revision's
+// version and downgrade maps them back.
+type renames struct {
+	upgrade   nameMap
+	downgrade nameMap
+}
+
+func newRenames(m map[string]string) *renames {
+	r := &renames{
+		upgrade:   make(nameMap, len(m)),
+		downgrade: make(nameMap, len(m)),
+	}
+	for k, v := range m {
+		r.upgrade[k] = v
+		r.downgrade[v] = k
+	}
+	return r
+}
+
+// lookup returns the mapping to apply in the requested direction.
+func (r *renames) lookup(upgrade bool) nameMap {
+	if upgrade {
+		return r.upgrade
+	}
+	return r.downgrade
+}
+
+// nameMap maps the current name of an attribute or signal to its new name.
+type nameMap map[string]string
+
+// renameAttributes moves the values of the matching keys to their new name.
+// The renames are applied to the original keys in a deterministic order, so
+// that chained renames (a to b, b to c) move each value once. An attribute
+// whose new name is already used keeps its original name.
+func (r nameMap) renameAttributes(attrs pcommon.Map) {
+	froms := make([]string, 0, len(r))
+	for from := range r {
+		if _, ok := attrs.Get(from); ok {
+			froms = append(froms, from)
+		}
+	}
+	if len(froms) == 0 {
+		return
+	}
+	sort.Strings(froms)
+
+	// The values reference the map's storage, which is modified below.
+	values := make([]pcommon.Value, len(froms))
+	for i, from := range froms {
+		v, _ := attrs.Get(from)
+		values[i] = pcommon.NewValueEmpty()
+		v.CopyTo(values[i])
+		attrs.Remove(from)
+	}
+	for i, from := range froms {
+		to := r[from]
+		if _, exists := attrs.Get(to); exists {
+			attrs.Insert(from, values[i])
+			continue
+		}
+		attrs.Insert(to, values[i])
+	}
+}
+
+// renameSignal updates the name of the signal if it has a new name.
+func (r nameMap) renameSignal(s alias.Signal) {
+	if to, ok := r[s.Name()]; ok {
+		s.SetName(to)
+	}
+}
+
+// nameSet is an optional set of names a change is restricted to,
+// an empty set matches every name.
+type nameSet map[string]struct{}
+
+func (ns nameSet) matches(name string) bool {
+	if len(ns) == 0 {
+		return true
+	}
+	_, ok := ns[name]
+	return ok
+}
+
+func newNameSet[T ~string](names []T) nameSet {
+	ns := make(nameSet, len(names))
+	for _, n := range names {
+		ns[string(n)] = struct{}{}
+	}
+	return ns
+}
+
+// conditionalRenames are attribute renames that only apply to
+// the signals (or span events of the spans) listed in the schema file.
+type conditionalRenames struct {
+	spans      nameSet
+	events     nameSet
+	metrics    nameSet
+	attributes *renames
+}
+
+// spanEventChange is either a rename of span events or
+// of span event attributes, following the schema file format.
+type spanEventChange struct {
+	names      *renames
+	attributes *conditionalRenames
+}
+
+// metricChange is either a rename of metrics or
+// of metric data point attributes, following the schema file format.
+type metricChange struct {
+	names      *renames
+	attributes *conditionalRenames
+}
+
+// revision contains all the changes introduced by a single
+// version of a schema family. The changes of each section
+// are stored in the order they are defined in the schema file.
+type revision struct {
+	version *Version
+
+	all        []*renames
+	resources  []*renames
+	spans      []conditionalRenames
+	spanEvents []spanEventChange
+	metrics    []metricChange
+	logs       []*renames
+}
+
+func newRevision(v *Version, def ast.VersionDef) *revision {
+	r := &revision{version: v}
+	for _, c := range def.All.Changes {
+		if c.RenameAttributes != nil {
+			r.all = append(r.all, newRenames(*c.RenameAttributes))
+		}
+	}
+	for _, c := range def.Resources.Changes {
+		if c.RenameAttributes != nil {
+			r.resources = append(r.resources, newRenames(*c.RenameAttributes))
+		}
+	}
+	for _, c := range def.Spans.Changes {
+		if c.RenameAttributes != nil {
+			r.spans = append(r.spans, conditionalRenames{
+				spans:      newNameSet(c.RenameAttributes.ApplyToSpans),
+				attributes: newRenames(c.RenameAttributes.AttributeMap),
+			})
+		}
+	}
+	for _, c := range def.SpanEvents.Changes {
+		if c.RenameEvents != nil {
+			r.spanEvents = append(r.spanEvents, spanEventChange{names: newRenames(c.RenameEvents.EventNameMap)})
+		}
+		if c.RenameAttributes != nil {
+			r.spanEvents = append(r.spanEvents, spanEventChange{attributes: &conditionalRenames{
+				spans:      newNameSet(c.RenameAttributes.ApplyToSpans),
+				events:     newNameSet(c.RenameAttributes.ApplyToEvents),
+				attributes: newRenames(c.RenameAttributes.AttributeMap),
+			}})
+		}
+	}
+	for _, c := range def.Metrics.Changes {
+		if len(c.RenameMetrics) > 0 {
+			names := make(map[string]string, len(c.RenameMetrics))
+			for k, v := range c.RenameMetrics {
+				names[string(k)] = string(v)
+			}
+			r.metrics = append(r.metrics, metricChange{names: newRenames(names)})
+		}
+		if c.RenameAttributes != nil {
+			r.metrics = append(r.metrics, metricChange{attributes: &conditionalRenames{
+				metrics:    newNameSet(c.RenameAttributes.ApplyToMetrics),
+				attributes: newRenames(c.RenameAttributes.AttributeMap),
+			}})
+		}
+	}
+	for _, c := range def.Logs.Changes {
+		if c.RenameAttributes != nil {
+			r.logs = append(r.logs, newRenames(c.RenameAttributes.AttributeMap))
+		}
+	}
+	return r
+}
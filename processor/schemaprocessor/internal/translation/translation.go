@@ -0,0 +1,299 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	schema "go.opentelemetry.io/otel/schema/v1.0"
+	"go.opentelemetry.io/otel/schema/v1.0/ast"
+)
+
+// Translation holds the revisions of a schema family read from a single
+// schema file and can translate signals between any of its versions.
+type Translation struct {
+	family    string
+	revisions []*revision
+	// indexes maps each known version to its position in revisions
+	indexes map[Version]int
+}
+
+// NewTranslation parses the content of a schema file.
+func NewTranslation(content io.Reader) (*Translation, error) {
+	s, err := schema.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	return newTranslationFromAST(s)
+}
+
+func newTranslationFromAST(s *ast.Schema) (*Translation, error) {
+	family, _, err := GetFamilyAndVersion(s.SchemaURL)
+	if err != nil {
+		return nil, err
+	}
+	t := &Translation{
+		family:  family,
+		indexes: make(map[Version]int, len(s.Versions)),
+	}
+	for key, def := range s.Versions {
+		v, err := NewVersion(string(key))
+		if err != nil {
+			return nil, fmt.Errorf("schema %q: %w", s.SchemaURL, err)
+		}
+		t.revisions = append(t.revisions, newRevision(v, def))
+	}
+	sort.Slice(t.revisions, func(i, j int) bool {
+		return t.revisions[i].version.LessThan(t.revisions[j].version)
+	})
+	for i, r := range t.revisions {
+		t.indexes[*r.version] = i
+	}
+	return t, nil
+}
+
+// Family returns the schema family of the translation.
+func (t *Translation) Family() string {
+	return t.family
+}
+
+// SupportsVersion reports whether the version is defined by the schema file.
+func (t *Translation) SupportsVersion(v *Version) bool {
+	_, ok := t.indexes[*v]
+	return ok
+}
+
+// TranslateResourceSpans translates the resource spans from one version to another,
+// both versions must be supported by the translation.
+func (t *Translation) TranslateResourceSpans(rs ptrace.ResourceSpans, from, to *Version) {
+	t.iterate(from, to, func(r *revision, upgrade bool) {
+		r.translateResourceSpans(rs, upgrade)
+	})
+}
+
+// TranslateResourceMetrics translates the resource metrics from one version to another,
+// both versions must be supported by the translation.
+func (t *Translation) TranslateResourceMetrics(rm pmetric.ResourceMetrics, from, to *Version) {
+	t.iterate(from, to, func(r *revision, upgrade bool) {
+		r.translateResourceMetrics(rm, upgrade)
+	})
+}
+
+// TranslateResourceLogs translates the resource logs from one version to another,
+// both versions must be supported by the translation.
+func (t *Translation) TranslateResourceLogs(rl plog.ResourceLogs, from, to *Version) {
+	t.iterate(from, to, func(r *revision, upgrade bool) {
+		r.translateResourceLogs(rl, upgrade)
+	})
+}
+
+// iterate calls fn with the revisions required to go from one version to another.
+// When upgrading, the changes of every version after from up to and including to
+// are applied in ascending order. When downgrading, the changes of every version
+// after to up to and including from are rolled back in descending order.
+func (t *Translation) iterate(from, to *Version, fn func(r *revision, upgrade bool)) {
+	start, end := t.indexes[*from], t.indexes[*to]
+	for i := start + 1; i <= end; i++ {
+		fn(t.revisions[i], true)
+	}
+	for i := start; i > end; i-- {
+		fn(t.revisions[i], false)
+	}
+}
+
+// inOrder calls fn for every index in [0, n), in reverse order when rolling back changes.
+func inOrder(n int, upgrade bool, fn func(i int)) {
+	if upgrade {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+	for i := n - 1; i >= 0; i-- {
+		fn(i)
+	}
+}
+
+// applyAll applies the attribute renames of the "all" section.
+func (r *revision) applyAll(upgrade bool, attrs pcommon.Map) {
+	inOrder(len(r.all), upgrade, func(i int) {
+		r.all[i].lookup(upgrade).renameAttributes(attrs)
+	})
+}
+
+func (r *revision) applyResources(upgrade bool, res pcommon.Resource) {
+	inOrder(len(r.resources), upgrade, func(i int) {
+		r.resources[i].lookup(upgrade).renameAttributes(res.Attributes())
+	})
+}
+
+func (r *revision) translateResourceSpans(rs ptrace.ResourceSpans, upgrade bool) {
+	steps := []func(){
+		func() {
+			r.applyAll(upgrade, rs.Resource().Attributes())
+			forEachSpan(rs, func(span ptrace.Span) {
+				r.applyAll(upgrade, span.Attributes())
+				for i := 0; i < span.Events().Len(); i++ {
+					r.applyAll(upgrade, span.Events().At(i).Attributes())
+				}
+			})
+		},
+		func() { r.applyResources(upgrade, rs.Resource()) },
+		func() {
+			inOrder(len(r.spans), upgrade, func(i int) {
+				change := r.spans[i]
+				attrs := change.attributes.lookup(upgrade)
+				forEachSpan(rs, func(span ptrace.Span) {
+					if change.spans.matches(span.Name()) {
+						attrs.renameAttributes(span.Attributes())
+					}
+				})
+			})
+		},
+		func() {
+			inOrder(len(r.spanEvents), upgrade, func(i int) {
+				change := r.spanEvents[i]
+				forEachSpan(rs, func(span ptrace.Span) {
+					for j := 0; j < span.Events().Len(); j++ {
+						event := span.Events().At(j)
+						if change.names != nil {
+							change.names.lookup(upgrade).renameSignal(event)
+							continue
+						}
+						if change.attributes.spans.matches(span.Name()) && change.attributes.events.matches(event.Name()) {
+							change.attributes.attributes.lookup(upgrade).renameAttributes(event.Attributes())
+						}
+					}
+				})
+			})
+		},
+	}
+	inOrder(len(steps), upgrade, func(i int) { steps[i]() })
+}
+
+func (r *revision) translateResourceMetrics(rm pmetric.ResourceMetrics, upgrade bool) {
+	steps := []func(){
+		func() {
+			r.applyAll(upgrade, rm.Resource().Attributes())
+			forEachMetric(rm, func(metric pmetric.Metric) {
+				forEachDataPointAttributes(metric, func(attrs pcommon.Map) {
+					r.applyAll(upgrade, attrs)
+				})
+			})
+		},
+		func() { r.applyResources(upgrade, rm.Resource()) },
+		func() {
+			inOrder(len(r.metrics), upgrade, func(i int) {
+				change := r.metrics[i]
+				forEachMetric(rm, func(metric pmetric.Metric) {
+					if change.names != nil {
+						change.names.lookup(upgrade).renameSignal(metric)
+						return
+					}
+					if !change.attributes.metrics.matches(metric.Name()) {
+						return
+					}
+					attrs := change.attributes.attributes.lookup(upgrade)
+					forEachDataPointAttributes(metric, attrs.renameAttributes)
+				})
+			})
+		},
+	}
+	inOrder(len(steps), upgrade, func(i int) { steps[i]() })
+}
+
+func (r *revision) translateResourceLogs(rl plog.ResourceLogs, upgrade bool) {
+	steps := []func(){
+		func() {
+			r.applyAll(upgrade, rl.Resource().Attributes())
+			forEachLogRecord(rl, func(lr plog.LogRecord) {
+				r.applyAll(upgrade, lr.Attributes())
+			})
+		},
+		func() { r.applyResources(upgrade, rl.Resource()) },
+		func() {
+			inOrder(len(r.logs), upgrade, func(i int) {
+				attrs := r.logs[i].lookup(upgrade)
+				forEachLogRecord(rl, func(lr plog.LogRecord) {
+					attrs.renameAttributes(lr.Attributes())
+				})
+			})
+		},
+	}
+	inOrder(len(steps), upgrade, func(i int) { steps[i]() })
+}
+
+func forEachSpan(rs ptrace.ResourceSpans, fn func(span ptrace.Span)) {
+	for i := 0; i < rs.ScopeSpans().Len(); i++ {
+		spans := rs.ScopeSpans().At(i).Spans()
+		for j := 0; j < spans.Len(); j++ {
+			fn(spans.At(j))
+		}
+	}
+}
+
+func forEachMetric(rm pmetric.ResourceMetrics, fn func(metric pmetric.Metric)) {
+	for i := 0; i < rm.ScopeMetrics().Len(); i++ {
+		metrics := rm.ScopeMetrics().At(i).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			fn(metrics.At(j))
+		}
+	}
+}
+
+func forEachLogRecord(rl plog.ResourceLogs, fn func(lr plog.LogRecord)) {
+	for i := 0; i < rl.ScopeLogs().Len(); i++ {
+		logs := rl.ScopeLogs().At(i).LogRecords()
+		for j := 0; j < logs.Len(); j++ {
+			fn(logs.At(j))
+		}
+	}
+}
+
+func forEachDataPointAttributes(metric pmetric.Metric, fn func(attrs pcommon.Map)) {
+	switch metric.DataType() {
+	case pmetric.MetricDataTypeGauge:
+		dps := metric.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes())
+		}
+	case pmetric.MetricDataTypeSum:
+		dps := metric.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes())
+		}
+	case pmetric.MetricDataTypeHistogram:
+		dps := metric.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes())
+		}
+	case pmetric.MetricDataTypeExponentialHistogram:
+		dps := metric.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes())
+		}
+	case pmetric.MetricDataTypeSummary:
+		dps := metric.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			fn(dps.At(i).Attributes())
+		}
+	}
+}
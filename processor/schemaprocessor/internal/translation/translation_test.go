@@ -0,0 +1,231 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package translation
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+)
+
+var (
+	v100 = &Version{Major: 1, Minor: 0, Patch: 0}
+	v110 = &Version{Major: 1, Minor: 1, Patch: 0}
+)
+
+func newTestTranslation(t *testing.T) *Translation {
+	t.Helper()
+
+	f, err := os.Open(filepath.Join("..", "..", "testdata", "schema.yml"))
+	require.NoError(t, err, "Must be able to open the test schema")
+	defer f.Close()
+
+	tn, err := NewTranslation(f)
+	require.NoError(t, err, "Must be able to parse the test schema")
+	return tn
+}
+
+// The sort functions order the attributes by key since
+// renamed attributes do not keep their position.
+
+func sortSpanAttributes(rs ptrace.ResourceSpans) ptrace.ResourceSpans {
+	rs.Resource().Attributes().Sort()
+	forEachSpan(rs, func(span ptrace.Span) {
+		span.Attributes().Sort()
+		for i := 0; i < span.Events().Len(); i++ {
+			span.Events().At(i).Attributes().Sort()
+		}
+	})
+	return rs
+}
+
+func sortMetricAttributes(rm pmetric.ResourceMetrics) pmetric.ResourceMetrics {
+	rm.Resource().Attributes().Sort()
+	forEachMetric(rm, func(metric pmetric.Metric) {
+		forEachDataPointAttributes(metric, func(attrs pcommon.Map) { attrs.Sort() })
+	})
+	return rm
+}
+
+func sortLogAttributes(rl plog.ResourceLogs) plog.ResourceLogs {
+	rl.Resource().Attributes().Sort()
+	forEachLogRecord(rl, func(lr plog.LogRecord) { lr.Attributes().Sort() })
+	return rl
+}
+
+func TestNewTranslation(t *testing.T) {
+	t.Parallel()
+
+	tn := newTestTranslation(t)
+	assert.Equal(t, "https://opentelemetry.io/schemas", tn.Family())
+	assert.True(t, tn.SupportsVersion(v100))
+	assert.True(t, tn.SupportsVersion(v110))
+	assert.False(t, tn.SupportsVersion(&Version{Major: 1, Minor: 2, Patch: 0}))
+
+	_, err := NewTranslation(strings.NewReader("file_format: 1.0.0\nschema_url: not-a-url\n"))
+	assert.Error(t, err, "Must error with an invalid schema url")
+}
+
+func TestTranslateResourceSpans(t *testing.T) {
+	t.Parallel()
+
+	newSpans := func(old bool) ptrace.ResourceSpans {
+		rs := ptrace.NewResourceSpans()
+		pick := func(oldName, newName string) string {
+			if old {
+				return oldName
+			}
+			return newName
+		}
+		rs.Resource().Attributes().UpsertString(pick("k8s.pod.name", "kubernetes.pod.name"), "pod")
+		rs.Resource().Attributes().UpsertString(pick("telemetry.auto.version", "telemetry.auto_instr.version"), "1.0")
+
+		spans := rs.ScopeSpans().AppendEmpty().Spans()
+		get := spans.AppendEmpty()
+		get.SetName("HTTP GET")
+		get.Attributes().UpsertString(pick("peer.service", "peer.service.name"), "db")
+		event := get.Events().AppendEmpty()
+		event.SetName(pick("stacktrace", "stack_trace"))
+		event.Attributes().UpsertString(pick("k8s.node.name", "kubernetes.node.name"), "node")
+
+		// The span attribute rename only applies to "HTTP GET" spans.
+		post := spans.AppendEmpty()
+		post.SetName("HTTP POST")
+		post.Attributes().UpsertString("peer.service", "db")
+
+		// The event attribute rename only applies to "exception.stack_trace" events.
+		exception := post.Events().AppendEmpty()
+		exception.SetName("exception.stack_trace")
+		exception.Attributes().UpsertString(pick("peer.service", "peer.service.name"), "db")
+		return rs
+	}
+
+	tn := newTestTranslation(t)
+
+	rs := newSpans(true)
+	tn.TranslateResourceSpans(rs, v100, v110)
+	assert.Equal(t, sortSpanAttributes(newSpans(false)), sortSpanAttributes(rs), "Must upgrade to the newer version")
+
+	tn.TranslateResourceSpans(rs, v110, v100)
+	assert.Equal(t, sortSpanAttributes(newSpans(true)), sortSpanAttributes(rs), "Must downgrade back to the older version")
+
+	tn.TranslateResourceSpans(rs, v100, v100)
+	assert.Equal(t, sortSpanAttributes(newSpans(true)), sortSpanAttributes(rs), "Must not change data at the same version")
+}
+
+func TestTranslateResourceMetrics(t *testing.T) {
+	t.Parallel()
+
+	newMetrics := func(old bool) pmetric.ResourceMetrics {
+		rm := pmetric.NewResourceMetrics()
+		pick := func(oldName, newName string) string {
+			if old {
+				return oldName
+			}
+			return newName
+		}
+		rm.Resource().Attributes().UpsertString(pick("k8s.cluster.name", "kubernetes.cluster.name"), "prod")
+
+		metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+		cpu := metrics.AppendEmpty()
+		cpu.SetName(pick("container.cpu.usage.total", "cpu.usage.total"))
+		cpu.SetEmptySum().DataPoints().AppendEmpty().Attributes().UpsertString(pick("k8s.pod.name", "kubernetes.pod.name"), "pod")
+
+		utilization := metrics.AppendEmpty()
+		utilization.SetName("system.cpu.utilization")
+		utilization.SetEmptyGauge().DataPoints().AppendEmpty().Attributes().UpsertString(pick("status", "state"), "idle")
+
+		// The attribute rename only applies to the listed metrics.
+		other := metrics.AppendEmpty()
+		other.SetName("system.disk.io")
+		other.SetEmptyHistogram().DataPoints().AppendEmpty().Attributes().UpsertString("status", "ok")
+		return rm
+	}
+
+	tn := newTestTranslation(t)
+
+	rm := newMetrics(true)
+	tn.TranslateResourceMetrics(rm, v100, v110)
+	assert.Equal(t, sortMetricAttributes(newMetrics(false)), sortMetricAttributes(rm), "Must upgrade to the newer version")
+
+	tn.TranslateResourceMetrics(rm, v110, v100)
+	assert.Equal(t, sortMetricAttributes(newMetrics(true)), sortMetricAttributes(rm), "Must downgrade back to the older version")
+}
+
+func TestTranslateResourceLogs(t *testing.T) {
+	t.Parallel()
+
+	newLogs := func(old bool) plog.ResourceLogs {
+		rl := plog.NewResourceLogs()
+		pick := func(oldName, newName string) string {
+			if old {
+				return oldName
+			}
+			return newName
+		}
+		rl.Resource().Attributes().UpsertString(pick("k8s.namespace.name", "kubernetes.namespace.name"), "default")
+		lr := rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+		lr.Attributes().UpsertString(pick("process.executable_name", "process.executable.name"), "otelcol")
+		lr.Attributes().UpsertInt(pick("k8s.job.uid", "kubernetes.job.uid"), 42)
+		lr.Attributes().UpsertString("unchanged", "value")
+		return rl
+	}
+
+	tn := newTestTranslation(t)
+
+	rl := newLogs(true)
+	tn.TranslateResourceLogs(rl, v100, v110)
+	assert.Equal(t, sortLogAttributes(newLogs(false)), sortLogAttributes(rl), "Must upgrade to the newer version")
+
+	tn.TranslateResourceLogs(rl, v110, v100)
+	assert.Equal(t, sortLogAttributes(newLogs(true)), sortLogAttributes(rl), "Must downgrade back to the older version")
+}
+
+func TestRenameAttributesConflict(t *testing.T) {
+	t.Parallel()
+
+	attrs := pcommon.NewMap()
+	attrs.UpsertString("old", "from-old")
+	attrs.UpsertString("new", "existing")
+
+	newRenames(map[string]string{"old": "new"}).lookup(true).renameAttributes(attrs)
+
+	assert.Equal(t, map[string]interface{}{"old": "from-old", "new": "existing"}, attrs.AsRaw())
+}
+
+func TestRenameAttributesChained(t *testing.T) {
+	t.Parallel()
+
+	renames := newRenames(map[string]string{"a": "b", "b": "c"})
+	for i := 0; i < 20; i++ {
+		attrs := pcommon.NewMap()
+		attrs.UpsertString("a", "from-a")
+		attrs.UpsertString("b", "from-b")
+
+		renames.lookup(true).renameAttributes(attrs)
+		assert.Equal(t, map[string]interface{}{"b": "from-a", "c": "from-b"}, attrs.AsRaw())
+
+		renames.lookup(false).renameAttributes(attrs)
+		assert.Equal(t, map[string]interface{}{"a": "from-a", "b": "from-b"}, attrs.AsRaw())
+	}
+}
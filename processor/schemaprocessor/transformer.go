@@ -24,11 +24,15 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/schemaprocessor/internal/translation"
 )
 
 type transformer struct {
-	targets []string
-	log     *zap.Logger
+	cfg      *Config
+	settings component.TelemetrySettings
+	log      *zap.Logger
+	manager  *translation.Manager
 }
 
 func newTransformer(
@@ -40,29 +44,76 @@ func newTransformer(
 	if !ok {
 		return nil, errors.New("invalid configuration provided")
 	}
+	manager, err := translation.NewManager(cfg.Targets, set.Logger)
+	if err != nil {
+		return nil, err
+	}
 	return &transformer{
-		log:     set.Logger,
-		targets: cfg.Targets,
+		cfg:      cfg,
+		settings: set.TelemetrySettings,
+		log:      set.Logger,
+		manager:  manager,
 	}, nil
 }
 
 func (t transformer) processLogs(ctx context.Context, ld plog.Logs) (plog.Logs, error) {
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		if tr := t.requestTranslator(ctx, rl.SchemaUrl()); tr != nil {
+			tr.ApplyResourceLogs(rl)
+		}
+	}
 	return ld, nil
 }
 
 func (t transformer) processMetrics(ctx context.Context, md pmetric.Metrics) (pmetric.Metrics, error) {
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		if tr := t.requestTranslator(ctx, rm.SchemaUrl()); tr != nil {
+			tr.ApplyResourceMetrics(rm)
+		}
+	}
 	return md, nil
 }
 
 func (t transformer) processTraces(ctx context.Context, td ptrace.Traces) (ptrace.Traces, error) {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		if tr := t.requestTranslator(ctx, rs.SchemaUrl()); tr != nil {
+			tr.ApplyResourceSpans(rs)
+		}
+	}
 	return td, nil
 }
 
-// start will load the remote file definition if it isn't already cached
-// and resolve the schema translation file
+// requestTranslator returns the translator for the schema URL, if any.
+// Data that can not be translated is passed on unchanged.
+func (t transformer) requestTranslator(ctx context.Context, schemaURL string) *translation.Translator {
+	tr, err := t.manager.RequestTranslator(ctx, schemaURL)
+	if err != nil {
+		t.log.Debug("Unable to translate schema", zap.String("schema-url", schemaURL), zap.Error(err))
+		return nil
+	}
+	return tr
+}
+
 func (t *transformer) start(ctx context.Context, host component.Host) error {
-	for _, target := range t.targets {
-		t.log.Info("Fetching remote schema url", zap.String("schema-url", target))
+	client, err := t.cfg.HTTPClientSettings.ToClient(host, t.settings)
+	if err != nil {
+		return err
+	}
+	provider := translation.NewHTTPProvider(client)
+	if t.cfg.CacheDirectory != "" {
+		provider = translation.NewCacheProvider(t.cfg.CacheDirectory, provider)
+	}
+	t.manager.SetProvider(provider)
+
+	// Failures are logged by the manager and retried when the schema is needed.
+	for _, schemaURL := range t.cfg.Targets {
+		_ = t.manager.Prefetch(ctx, schemaURL)
+	}
+	for _, schemaURL := range t.cfg.Prefetch {
+		_ = t.manager.Prefetch(ctx, schemaURL)
 	}
 	return nil
 }
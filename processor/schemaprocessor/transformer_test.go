@@ -18,11 +18,13 @@ import (
 	"context"
 	_ "embed"
 	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
@@ -108,3 +110,81 @@ func TestTransformerProcessing(t *testing.T) {
 		assert.Equal(t, in, out, "Must return the same data (subject to change)")
 	})
 }
+
+func TestTransformerTranslation(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(SchemaHandler(t)))
+	defer srv.Close()
+
+	cfg := newDefaultConfiguration().(*Config)
+	cfg.Targets = []string{srv.URL + "/schemas/1.1.0"}
+	cfg.CacheDirectory = t.TempDir()
+
+	trans, err := newTransformer(context.Background(), cfg, component.ProcessorCreateSettings{
+		TelemetrySettings: component.TelemetrySettings{
+			Logger: zaptest.NewLogger(t),
+		},
+	})
+	require.NoError(t, err, "Must not error when creating transformer")
+	require.NoError(t, trans.start(context.Background(), componenttest.NewNopHost()))
+
+	t.Run("metrics", func(t *testing.T) {
+		in := pmetric.NewMetrics()
+		rm := in.ResourceMetrics().AppendEmpty()
+		rm.SetSchemaUrl(srv.URL + "/schemas/1.0.0")
+		m := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		m.SetName("container.memory.usage.max")
+		m.SetEmptyGauge().DataPoints().AppendEmpty().Attributes().UpsertString("k8s.pod.name", "pod")
+
+		out, err := trans.processMetrics(context.Background(), in)
+		require.NoError(t, err, "Must not error when processing metrics")
+		rm = out.ResourceMetrics().At(0)
+		assert.Equal(t, srv.URL+"/schemas/1.1.0", rm.SchemaUrl())
+		m = rm.ScopeMetrics().At(0).Metrics().At(0)
+		assert.Equal(t, "memory.usage.max", m.Name())
+		assert.Equal(t, map[string]interface{}{"kubernetes.pod.name": "pod"}, m.Gauge().DataPoints().At(0).Attributes().AsRaw())
+	})
+
+	t.Run("traces", func(t *testing.T) {
+		in := ptrace.NewTraces()
+		rs := in.ResourceSpans().AppendEmpty()
+		rs.SetSchemaUrl(srv.URL + "/schemas/1.0.0")
+		rs.Resource().Attributes().UpsertString("telemetry.auto.version", "1.0")
+		s := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		s.SetName("HTTP GET")
+		s.Attributes().UpsertString("peer.service", "db")
+
+		out, err := trans.processTraces(context.Background(), in)
+		require.NoError(t, err, "Must not error when processing traces")
+		rs = out.ResourceSpans().At(0)
+		assert.Equal(t, srv.URL+"/schemas/1.1.0", rs.SchemaUrl())
+		assert.Equal(t, map[string]interface{}{"telemetry.auto_instr.version": "1.0"}, rs.Resource().Attributes().AsRaw())
+		assert.Equal(t, map[string]interface{}{"peer.service.name": "db"}, rs.ScopeSpans().At(0).Spans().At(0).Attributes().AsRaw())
+	})
+
+	t.Run("logs", func(t *testing.T) {
+		in := plog.NewLogs()
+		rl := in.ResourceLogs().AppendEmpty()
+		rl.SetSchemaUrl(srv.URL + "/schemas/1.0.0")
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Attributes().UpsertString("process.executable_name", "otelcol")
+
+		out, err := trans.processLogs(context.Background(), in)
+		require.NoError(t, err, "Must not error when processing logs")
+		rl = out.ResourceLogs().At(0)
+		assert.Equal(t, srv.URL+"/schemas/1.1.0", rl.SchemaUrl())
+		assert.Equal(t, map[string]interface{}{"process.executable.name": "otelcol"}, rl.ScopeLogs().At(0).LogRecords().At(0).Attributes().AsRaw())
+	})
+
+	t.Run("unavailable schema", func(t *testing.T) {
+		in := plog.NewLogs()
+		rl := in.ResourceLogs().AppendEmpty()
+		rl.SetSchemaUrl(srv.URL + "/schemas/1.2.0")
+		rl.ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Attributes().UpsertString("process.executable_name", "otelcol")
+		expected := in.Clone()
+
+		out, err := trans.processLogs(context.Background(), in)
+		require.NoError(t, err, "Must not error when the schema can not be translated")
+		assert.Equal(t, expected, out, "Must pass on untranslatable data unchanged")
+	})
+}
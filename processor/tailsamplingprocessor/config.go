@@ -40,6 +40,9 @@ const (
 	StringAttribute PolicyType = "string_attribute"
 	// RateLimiting allows all traces until the specified limits are satisfied.
 	RateLimiting PolicyType = "rate_limiting"
+	// RateLimitingByKey allows all traces until the limits of the value of a given
+	// resource or span attribute, e.g.: service.name, are satisfied.
+	RateLimitingByKey PolicyType = "rate_limiting_by_key"
 	// Composite allows defining a composite policy, combining the other policies in one
 	Composite PolicyType = "composite"
 	// And allows defining a And policy, combining the other policies in one
@@ -48,6 +51,8 @@ const (
 	SpanCount PolicyType = "span_count"
 	// TraceState sample traces with specified values by the given key
 	TraceState PolicyType = "trace_state"
+	// TQLCondition sample traces whose spans satisfy a telemetry query language condition.
+	TQLCondition PolicyType = "tql_condition"
 )
 
 // sharedPolicyCfg holds the common configuration to all policies that are used in derivative policy configurations
@@ -69,10 +74,14 @@ type sharedPolicyCfg struct {
 	StringAttributeCfg StringAttributeCfg `mapstructure:"string_attribute"`
 	// Configs for rate limiting filter sampling policy evaluator.
 	RateLimitingCfg RateLimitingCfg `mapstructure:"rate_limiting"`
+	// Configs for rate limiting by key filter sampling policy evaluator.
+	RateLimitingByKeyCfg RateLimitingByKeyCfg `mapstructure:"rate_limiting_by_key"`
 	// Configs for span count filter sampling policy evaluator.
 	SpanCountCfg SpanCountCfg `mapstructure:"span_count"`
 	// Configs for defining trace_state policy
 	TraceStateCfg TraceStateCfg `mapstructure:"trace_state"`
+	// Configs for tql condition filter sampling policy evaluator.
+	TQLConditionCfg TQLConditionCfg `mapstructure:"tql_condition"`
 }
 
 // CompositeSubPolicyCfg holds the common configuration to all policies under composite policy.
@@ -187,6 +196,22 @@ type RateLimitingCfg struct {
 	SpansPerSecond int64 `mapstructure:"spans_per_second"`
 }
 
+// RateLimitingByKeyCfg holds the configurable settings to create a rate limiting by key
+// sampling policy evaluator.
+type RateLimitingByKeyCfg struct {
+	// Key is the resource or span attribute whose values have separate limits.
+	Key string `mapstructure:"key"`
+	// SpansPerSecond sets the limit on the maximum number of spans that can be processed each second
+	// for each value of the key, unless it is overridden. The traces without the key, and the traces
+	// of the values exceeding MaxKeys, share a single limit of SpansPerSecond.
+	SpansPerSecond int64 `mapstructure:"spans_per_second"`
+	// Overrides sets the limit of specific values of the key.
+	Overrides map[string]int64 `mapstructure:"overrides"`
+	// MaxKeys is the maximum number of distinct values of the key that have their own limit
+	// each second. Defaults to 1000.
+	MaxKeys int `mapstructure:"max_keys"`
+}
+
 // SpanCountCfg holds the configurable settings to create a Span Count filter sampling policy
 // sampling policy evaluator
 type SpanCountCfg struct {
@@ -194,6 +219,18 @@ type SpanCountCfg struct {
 	MinSpans int32 `mapstructure:"min_spans"`
 }
 
+// TQLConditionCfg holds the configurable settings to create a tql condition filter
+// sampling policy evaluator.
+type TQLConditionCfg struct {
+	// Condition is the telemetry query language condition evaluated against the spans,
+	// e.g.: attributes["http.status_code"] >= 500 and resource.attributes["env"] == "prod".
+	Condition string `mapstructure:"condition"`
+	// Match is either "any", to sample the traces with at least one span satisfying the
+	// condition, or "all", to sample the traces whose spans all satisfy the condition.
+	// Defaults to "any".
+	Match string `mapstructure:"match"`
+}
+
 // Config holds the configuration for tail-based sampling.
 type Config struct {
 	config.ProcessorSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
@@ -209,4 +246,9 @@ type Config struct {
 	// PolicyCfgs sets the tail-based sampling policy which makes a sampling decision
 	// for a given trace when requested.
 	PolicyCfgs []PolicyCfg `mapstructure:"policies"`
+	// StorageID is the ID of a storage extension the spans awaiting a sampling decision are kept in,
+	// instead of memory. The spans kept in the storage survive restarts, after which the sampling
+	// decision is evaluated again, and traces evicted from memory because of NumTraces keep their
+	// spans in the storage until their sampling decision is evaluated.
+	StorageID *config.ComponentID `mapstructure:"storage"`
 }
@@ -108,6 +108,28 @@ func TestLoadConfig(t *testing.T) {
 						TraceStateCfg: TraceStateCfg{Key: "key3", Values: []string{"value1", "value2"}},
 					},
 				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name: "test-policy-12",
+						Type: RateLimitingByKey,
+						RateLimitingByKeyCfg: RateLimitingByKeyCfg{
+							Key:            "service.name",
+							SpansPerSecond: 35,
+							Overrides:      map[string]int64{"checkout": 100},
+							MaxKeys:        500,
+						},
+					},
+				},
+				{
+					sharedPolicyCfg: sharedPolicyCfg{
+						Name: "test-policy-13",
+						Type: TQLCondition,
+						TQLConditionCfg: TQLConditionCfg{
+							Condition: `attributes["http.status_code"] >= 500 and resource.attributes["env"] == "prod"`,
+							Match:     "all",
+						},
+					},
+				},
 				{
 					sharedPolicyCfg: sharedPolicyCfg{
 						Name: "and-policy-1",
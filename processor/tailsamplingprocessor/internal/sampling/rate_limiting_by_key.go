@@ -0,0 +1,125 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+// defaultMaxKeys is the number of distinct keys that get their own budget when none is configured.
+const defaultMaxKeys = 1000
+
+type rateLimitingByKey struct {
+	key            string
+	spansPerSecond int64
+	overrides      map[string]int64
+	maxKeys        int
+
+	timeProvider  TimeProvider
+	currentSecond int64
+	// spansInCurrentSecond holds the spans sampled during the current second for each key.
+	spansInCurrentSecond map[string]int64
+	// overflowSpans holds the spans sampled during the current second for the traces
+	// that have no key or whose key exceeds maxKeys.
+	overflowSpans int64
+	logger        *zap.Logger
+}
+
+var _ PolicyEvaluator = (*rateLimitingByKey)(nil)
+
+// NewRateLimitingByKey creates a policy evaluator that limits the spans sampled each second
+// separately for each value of the resource or span attribute key. Values listed in overrides
+// have their own limit, all other values are limited to spansPerSecond. At most maxKeys values
+// have their own budget each second, the traces of additional values and the traces without the
+// attribute share a single budget of spansPerSecond.
+func NewRateLimitingByKey(logger *zap.Logger, key string, spansPerSecond int64, overrides map[string]int64, maxKeys int) PolicyEvaluator {
+	if maxKeys <= 0 {
+		maxKeys = defaultMaxKeys
+	}
+	return &rateLimitingByKey{
+		key:                  key,
+		spansPerSecond:       spansPerSecond,
+		overrides:            overrides,
+		maxKeys:              maxKeys,
+		timeProvider:         MonotonicClock{},
+		spansInCurrentSecond: make(map[string]int64),
+		logger:               logger,
+	}
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (r *rateLimitingByKey) Evaluate(_ pcommon.TraceID, trace *TraceData) (Decision, error) {
+	r.logger.Debug("Evaluating spans in rate-limiting-by-key filter")
+	currSecond := r.timeProvider.getCurSecond()
+	if r.currentSecond != currSecond {
+		r.currentSecond = currSecond
+		r.spansInCurrentSecond = make(map[string]int64, len(r.spansInCurrentSecond))
+		r.overflowSpans = 0
+	}
+
+	trace.Lock()
+	value, found := r.keyValue(trace.ReceivedBatches)
+	trace.Unlock()
+
+	spans, tracked := r.spansInCurrentSecond[value]
+	if !found || (!tracked && len(r.spansInCurrentSecond) >= r.maxKeys) {
+		spansInSecondIfSampled := r.overflowSpans + trace.SpanCount.Load()
+		if spansInSecondIfSampled < r.spansPerSecond {
+			r.overflowSpans = spansInSecondIfSampled
+			return Sampled, nil
+		}
+		return NotSampled, nil
+	}
+
+	limit := r.spansPerSecond
+	if override, ok := r.overrides[value]; ok {
+		limit = override
+	}
+	spansInSecondIfSampled := spans + trace.SpanCount.Load()
+	if spansInSecondIfSampled < limit {
+		r.spansInCurrentSecond[value] = spansInSecondIfSampled
+		return Sampled, nil
+	}
+	if !tracked {
+		// The key still counts against maxKeys for the rest of the second.
+		r.spansInCurrentSecond[value] = 0
+	}
+	return NotSampled, nil
+}
+
+// keyValue returns the value of the first resource or span attribute matching the key.
+func (r *rateLimitingByKey) keyValue(batches []ptrace.Traces) (string, bool) {
+	var value string
+	var found bool
+	hasResourceOrSpanWithCondition(
+		batches,
+		func(resource pcommon.Resource) bool {
+			v, ok := resource.Attributes().Get(r.key)
+			if ok {
+				value, found = v.AsString(), true
+			}
+			return ok
+		},
+		func(span ptrace.Span) bool {
+			v, ok := span.Attributes().Get(r.key)
+			if ok {
+				value, found = v.AsString(), true
+			}
+			return ok
+		})
+	return value, found
+}
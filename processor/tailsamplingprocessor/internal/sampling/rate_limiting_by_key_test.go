@@ -0,0 +1,82 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/atomic"
+	"go.uber.org/zap"
+)
+
+type mutableTimeProvider struct {
+	second int64
+}
+
+func (m *mutableTimeProvider) getCurSecond() int64 {
+	return m.second
+}
+
+func newTraceWithKey(nodeAttrs map[string]interface{}, spanAttrKey string, spanAttrValue string, spanCount int64) *TraceData {
+	trace := newTraceStringAttrs(nodeAttrs, spanAttrKey, spanAttrValue)
+	trace.SpanCount = atomic.NewInt64(spanCount)
+	return trace
+}
+
+func TestRateLimiterByKey(t *testing.T) {
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	clock := &mutableTimeProvider{second: 1}
+	rateLimiter := NewRateLimitingByKey(zap.NewNop(), "service.name", 5, map[string]int64{"critical": 10}, 3).(*rateLimitingByKey)
+	rateLimiter.timeProvider = clock
+
+	evaluate := func(trace *TraceData) Decision {
+		decision, err := rateLimiter.Evaluate(traceID, trace)
+		assert.NoError(t, err)
+		return decision
+	}
+
+	// The noisy service exhausts its own budget only.
+	noisy := newTraceWithKey(map[string]interface{}{"service.name": "noisy"}, "", "", 4)
+	assert.Equal(t, Sampled, evaluate(noisy))
+	assert.Equal(t, NotSampled, evaluate(noisy))
+	quiet := newTraceWithKey(map[string]interface{}{"service.name": "quiet"}, "", "", 4)
+	assert.Equal(t, Sampled, evaluate(quiet))
+
+	// Span attributes are used when the resource does not have the key.
+	assert.Equal(t, NotSampled, evaluate(newTraceWithKey(nil, "service.name", "quiet", 2)))
+
+	// Overridden keys have their own limit.
+	critical := newTraceWithKey(map[string]interface{}{"service.name": "critical"}, "", "", 8)
+	assert.Equal(t, Sampled, evaluate(critical))
+
+	// Additional keys and traces without the key share the default budget.
+	overflow := newTraceWithKey(map[string]interface{}{"service.name": "other"}, "", "", 3)
+	assert.Equal(t, Sampled, evaluate(overflow))
+	assert.Equal(t, NotSampled, evaluate(newTraceWithKey(nil, "", "", 3)))
+	assert.Len(t, rateLimiter.spansInCurrentSecond, 3)
+
+	// The budgets are reset every second.
+	clock.second++
+	assert.Equal(t, Sampled, evaluate(noisy))
+	assert.Equal(t, Sampled, evaluate(newTraceWithKey(nil, "", "", 3)))
+	assert.Len(t, rateLimiter.spansInCurrentSecond, 1)
+}
+
+func TestRateLimiterByKeyDefaultMaxKeys(t *testing.T) {
+	rateLimiter := NewRateLimitingByKey(zap.NewNop(), "tenant.id", 5, nil, 0).(*rateLimitingByKey)
+	assert.Equal(t, defaultMaxKeys, rateLimiter.maxKeys)
+}
@@ -0,0 +1,120 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/contexts/tqltraces"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/functions/tqlcommon"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/functions/tqlotel"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/telemetryquerylanguage/tql"
+)
+
+const (
+	// MatchAny samples the traces with at least one span satisfying the condition.
+	MatchAny = "any"
+	// MatchAll samples the traces whose spans all satisfy the condition.
+	MatchAll = "all"
+)
+
+// tqlFunctions are the functions that can be used in the conditions, only
+// functions that do not modify the spans are allowed.
+var tqlFunctions = map[string]interface{}{
+	"TraceID": tqlotel.TraceID,
+	"SpanID":  tqlotel.SpanID,
+	"IsMatch": tqlcommon.IsMatch,
+	"Concat":  tqlcommon.Concat,
+	"Int":     tqlcommon.Int,
+}
+
+type tqlCondition struct {
+	condition tql.BoolExpressionEvaluator
+	matchAll  bool
+	logger    *zap.Logger
+}
+
+var _ PolicyEvaluator = (*tqlCondition)(nil)
+
+// NewTQLCondition creates a policy evaluator that samples the traces whose spans satisfy
+// the given telemetry query language condition, either any of the spans or all of them
+// depending on match.
+func NewTQLCondition(logger *zap.Logger, condition string, match string) (PolicyEvaluator, error) {
+	var matchAll bool
+	switch match {
+	case "", MatchAny:
+	case MatchAll:
+		matchAll = true
+	default:
+		return nil, fmt.Errorf("unknown match %q, must be one of %q or %q", match, MatchAny, MatchAll)
+	}
+
+	tqlp := tql.NewParser(tqlFunctions, tqltraces.ParsePath, tqltraces.ParseEnum, tql.NoOpLogger{})
+	conditions, err := tqlp.ParseConditions([]string{condition})
+	if err != nil {
+		return nil, fmt.Errorf("invalid condition %q: %w", condition, err)
+	}
+
+	return &tqlCondition{
+		condition: conditions[0],
+		matchAll:  matchAll,
+		logger:    logger,
+	}, nil
+}
+
+// Evaluate looks at the trace data and returns a corresponding SamplingDecision.
+func (tc *tqlCondition) Evaluate(_ pcommon.TraceID, trace *TraceData) (Decision, error) {
+	tc.logger.Debug("Evaluating spans in tql condition filter")
+
+	trace.Lock()
+	batches := trace.ReceivedBatches
+	trace.Unlock()
+
+	var evaluated bool
+	for _, batch := range batches {
+		rspans := batch.ResourceSpans()
+		for i := 0; i < rspans.Len(); i++ {
+			rs := rspans.At(i)
+			ilss := rs.ScopeSpans()
+			for j := 0; j < ilss.Len(); j++ {
+				ils := ilss.At(j)
+				spans := ils.Spans()
+				for k := 0; k < spans.Len(); k++ {
+					evaluated = true
+					matched := tc.matches(spans.At(k), ils.Scope(), rs.Resource())
+					if matched && !tc.matchAll {
+						return Sampled, nil
+					}
+					if !matched && tc.matchAll {
+						return NotSampled, nil
+					}
+				}
+			}
+		}
+	}
+
+	if tc.matchAll && evaluated {
+		return Sampled, nil
+	}
+	return NotSampled, nil
+}
+
+func (tc *tqlCondition) matches(span ptrace.Span, scope pcommon.InstrumentationScope, resource pcommon.Resource) bool {
+	return tc.condition(tqltraces.NewTransformContext(span, scope, resource))
+}
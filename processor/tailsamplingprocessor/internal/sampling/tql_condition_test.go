@@ -0,0 +1,126 @@
+// Copyright  The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sampling
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+func newTraceWithStatusCodes(env string, statusCodes ...int64) *TraceData {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().UpsertString("env", env)
+	ils := rs.ScopeSpans().AppendEmpty()
+	for _, statusCode := range statusCodes {
+		span := ils.Spans().AppendEmpty()
+		span.SetTraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+		span.SetSpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+		span.Attributes().UpsertInt("http.status_code", statusCode)
+	}
+	return &TraceData{
+		ReceivedBatches: []ptrace.Traces{traces},
+	}
+}
+
+func TestTQLCondition(t *testing.T) {
+	const condition = `attributes["http.status_code"] >= 500 and resource.attributes["env"] == "prod"`
+
+	cases := []struct {
+		Desc     string
+		Match    string
+		Trace    *TraceData
+		Decision Decision
+	}{
+		{
+			Desc:     "any span matching",
+			Match:    MatchAny,
+			Trace:    newTraceWithStatusCodes("prod", 200, 503),
+			Decision: Sampled,
+		},
+		{
+			Desc:     "any defaults when empty",
+			Trace:    newTraceWithStatusCodes("prod", 503),
+			Decision: Sampled,
+		},
+		{
+			Desc:     "no span matching",
+			Match:    MatchAny,
+			Trace:    newTraceWithStatusCodes("prod", 200, 404),
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "resource not matching",
+			Match:    MatchAny,
+			Trace:    newTraceWithStatusCodes("dev", 503),
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "all spans matching",
+			Match:    MatchAll,
+			Trace:    newTraceWithStatusCodes("prod", 500, 503),
+			Decision: Sampled,
+		},
+		{
+			Desc:     "not all spans matching",
+			Match:    MatchAll,
+			Trace:    newTraceWithStatusCodes("prod", 200, 503),
+			Decision: NotSampled,
+		},
+		{
+			Desc:     "all on a trace without spans",
+			Match:    MatchAll,
+			Trace:    newTraceWithStatusCodes("prod"),
+			Decision: NotSampled,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			filter, err := NewTQLCondition(zap.NewNop(), condition, c.Match)
+			require.NoError(t, err)
+			decision, err := filter.Evaluate(pcommon.NewTraceIDEmpty(), c.Trace)
+			assert.NoError(t, err)
+			assert.Equal(t, c.Decision, decision)
+		})
+	}
+}
+
+func TestTQLConditionFunctions(t *testing.T) {
+	filter, err := NewTQLCondition(zap.NewNop(), `IsMatch(resource.attributes["env"], "^pro") == true`, MatchAny)
+	require.NoError(t, err)
+	decision, err := filter.Evaluate(pcommon.NewTraceIDEmpty(), newTraceWithStatusCodes("prod", 200))
+	assert.NoError(t, err)
+	assert.Equal(t, Sampled, decision)
+}
+
+func TestTQLConditionInvalid(t *testing.T) {
+	_, err := NewTQLCondition(zap.NewNop(), `attributes["http.status_code"] >=`, MatchAny)
+	assert.Error(t, err)
+
+	_, err = NewTQLCondition(zap.NewNop(), `unknown_field == 1`, MatchAny)
+	assert.Error(t, err)
+
+	_, err = NewTQLCondition(zap.NewNop(), `set(attributes["key"], "value") == true`, MatchAny)
+	assert.Error(t, err)
+
+	_, err = NewTQLCondition(zap.NewNop(), `name == "foo"`, "some")
+	assert.Error(t, err)
+}
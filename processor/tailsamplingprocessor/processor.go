@@ -31,6 +31,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/timeutils"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/tracestore"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/idbatcher"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
 )
@@ -60,6 +61,8 @@ type tailSamplingSpanProcessor struct {
 	decisionBatcher idbatcher.Batcher
 	deleteChan      chan pcommon.TraceID
 	numTracesOnMap  *atomic.Uint64
+	// buffer keeps the spans awaiting a sampling decision when a storage is configured
+	buffer *tracestore.Store
 }
 
 const (
@@ -112,6 +115,9 @@ func newTracesProcessor(logger *zap.Logger, nextConsumer consumer.Traces, cfg Co
 
 	tsp.policyTicker = &timeutils.PolicyTicker{OnTickFunc: tsp.samplingPolicyOnTick}
 	tsp.deleteChan = make(chan pcommon.TraceID, cfg.NumTraces)
+	if cfg.StorageID != nil {
+		tsp.buffer = tracestore.New(*cfg.StorageID, cfg.ID(), 0)
+	}
 
 	return tsp, nil
 }
@@ -149,12 +155,18 @@ func getSharedPolicyEvaluator(logger *zap.Logger, cfg *sharedPolicyCfg) (samplin
 	case RateLimiting:
 		rlfCfg := cfg.RateLimitingCfg
 		return sampling.NewRateLimiting(logger, rlfCfg.SpansPerSecond), nil
+	case RateLimitingByKey:
+		rlkCfg := cfg.RateLimitingByKeyCfg
+		return sampling.NewRateLimitingByKey(logger, rlkCfg.Key, rlkCfg.SpansPerSecond, rlkCfg.Overrides, rlkCfg.MaxKeys), nil
 	case SpanCount:
 		spCfg := cfg.SpanCountCfg
 		return sampling.NewSpanCount(logger, spCfg.MinSpans), nil
 	case TraceState:
 		tsfCfg := cfg.TraceStateCfg
 		return sampling.NewTraceStateFilter(logger, tsfCfg.Key, tsfCfg.Values), nil
+	case TQLCondition:
+		tcfCfg := cfg.TQLConditionCfg
+		return sampling.NewTQLCondition(logger, tcfCfg.Condition, tcfCfg.Match)
 	default:
 		return nil, fmt.Errorf("unknown sampling policy type %s", cfg.Type)
 	}
@@ -172,18 +184,26 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 	batchLen := len(batch)
 	tsp.logger.Debug("Sampling Policy Evaluation ticked")
 	for _, id := range batch {
-		d, ok := tsp.idToTrace.Load(id)
-		if !ok {
+		var trace *sampling.TraceData
+		if d, ok := tsp.idToTrace.Load(id); ok {
+			trace = d.(*sampling.TraceData)
+		} else if trace, ok = tsp.evictedTrace(id); !ok {
 			metrics.idNotFoundOnMapCount++
 			continue
 		}
-		trace := d.(*sampling.TraceData)
 		trace.DecisionTime = time.Now()
 
+		// Evaluate the policies with the spans kept in the storage
+		trace.Lock()
+		tsp.takeBufferedBatches(id, trace)
+		trace.Unlock()
+
 		decision, policy := tsp.makeDecision(id, trace, &metrics)
 
-		// Sampled or not, remove the batches
+		// Sampled or not, remove the batches, including the ones
+		// that were kept in the storage during the evaluation
 		trace.Lock()
+		tsp.takeBufferedBatches(id, trace)
 		traceBatches := trace.ReceivedBatches
 		trace.ReceivedBatches = nil
 		trace.Unlock()
@@ -348,19 +368,7 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 			actualData.SpanCount.Add(lenSpans)
 		} else {
 			newTraceIDs++
-			tsp.decisionBatcher.AddToCurrentBatch(id)
-			tsp.numTracesOnMap.Add(1)
-			postDeletion := false
-			currTime := time.Now()
-			for !postDeletion {
-				select {
-				case tsp.deleteChan <- id:
-					postDeletion = true
-				default:
-					traceKeyToDrop := <-tsp.deleteChan
-					tsp.dropTrace(traceKeyToDrop, currTime)
-				}
-			}
+			tsp.trackNewTrace(id)
 		}
 
 		for i, p := range tsp.policies {
@@ -373,7 +381,7 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 				// Add the spans to the trace, but only once for all policy, otherwise same spans will
 				// be duplicated in the final trace.
 				traceTd = prepareTraceBatch(resourceSpans, spans)
-				actualData.ReceivedBatches = append(actualData.ReceivedBatches, traceTd)
+				tsp.bufferBatch(id, actualData, traceTd)
 				actualData.Unlock()
 				break
 			}
@@ -408,20 +416,116 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 	stats.Record(tsp.ctx, statNewTraceIDReceivedCount.M(newTraceIDs))
 }
 
+// trackNewTrace schedules the sampling decision of a trace added to idToTrace, dropping
+// the oldest traces when more than maxNumTraces are kept.
+func (tsp *tailSamplingSpanProcessor) trackNewTrace(id pcommon.TraceID) {
+	tsp.decisionBatcher.AddToCurrentBatch(id)
+	tsp.numTracesOnMap.Add(1)
+	postDeletion := false
+	currTime := time.Now()
+	for !postDeletion {
+		select {
+		case tsp.deleteChan <- id:
+			postDeletion = true
+		default:
+			traceKeyToDrop := <-tsp.deleteChan
+			tsp.dropTrace(traceKeyToDrop, currTime)
+		}
+	}
+}
+
+// bufferBatch keeps a batch of spans of a trace awaiting a sampling decision, in the
+// storage when one is configured. The caller must hold the lock of the trace.
+func (tsp *tailSamplingSpanProcessor) bufferBatch(id pcommon.TraceID, trace *sampling.TraceData, td ptrace.Traces) {
+	if tsp.buffer != nil {
+		err := tsp.buffer.Append(id, trace.ArrivalTime, td)
+		if err == nil {
+			return
+		}
+		tsp.logger.Warn("Failed to keep spans in the storage, keeping them in memory",
+			zap.String("traceID", id.HexString()),
+			zap.Error(err))
+	}
+	trace.ReceivedBatches = append(trace.ReceivedBatches, td)
+}
+
+// takeBufferedBatches moves the batches of the trace kept in the storage to the trace data.
+// The caller must hold the lock of the trace.
+func (tsp *tailSamplingSpanProcessor) takeBufferedBatches(id pcommon.TraceID, trace *sampling.TraceData) {
+	if tsp.buffer == nil {
+		return
+	}
+	batches, err := tsp.buffer.Take(id)
+	if err != nil {
+		tsp.logger.Warn("Failed to read spans from the storage",
+			zap.String("traceID", id.HexString()),
+			zap.Error(err))
+	}
+	trace.ReceivedBatches = append(trace.ReceivedBatches, batches...)
+}
+
+// evictedTrace returns the data of a trace that was dropped from idToTrace before its sampling
+// decision, but whose spans are still kept in the storage.
+func (tsp *tailSamplingSpanProcessor) evictedTrace(id pcommon.TraceID) (*sampling.TraceData, bool) {
+	if tsp.buffer == nil {
+		return nil, false
+	}
+	bt, ok := tsp.buffer.Get(id)
+	if !ok {
+		return nil, false
+	}
+	return tsp.newBufferedTraceData(bt), true
+}
+
+func (tsp *tailSamplingSpanProcessor) newBufferedTraceData(bt tracestore.Trace) *sampling.TraceData {
+	decisions := make([]sampling.Decision, len(tsp.policies))
+	for i := range decisions {
+		decisions[i] = sampling.Pending
+	}
+	return &sampling.TraceData{
+		Decisions:   decisions,
+		ArrivalTime: bt.ArrivalTime,
+		SpanCount:   atomic.NewInt64(int64(bt.SpanCount)),
+	}
+}
+
+// restoreBufferedTraces schedules the sampling decision of the traces
+// kept in the storage by a previous run.
+func (tsp *tailSamplingSpanProcessor) restoreBufferedTraces() {
+	traces := tsp.buffer.Traces()
+	for id, bt := range traces {
+		if _, loaded := tsp.idToTrace.LoadOrStore(id, tsp.newBufferedTraceData(bt)); !loaded {
+			tsp.trackNewTrace(id)
+		}
+	}
+	if len(traces) > 0 {
+		tsp.logger.Info("Restored traces awaiting a sampling decision from the storage", zap.Int("traces", len(traces)))
+	}
+}
+
 func (tsp *tailSamplingSpanProcessor) Capabilities() consumer.Capabilities {
 	return consumer.Capabilities{MutatesData: false}
 }
 
 // Start is invoked during service startup.
-func (tsp *tailSamplingSpanProcessor) Start(context.Context, component.Host) error {
+func (tsp *tailSamplingSpanProcessor) Start(ctx context.Context, host component.Host) error {
+	if tsp.buffer != nil {
+		if err := tsp.buffer.Start(ctx, host); err != nil {
+			return err
+		}
+		tsp.restoreBufferedTraces()
+	}
 	tsp.policyTicker.Start(tsp.tickerFrequency)
 	return nil
 }
 
 // Shutdown is invoked during service shutdown.
-func (tsp *tailSamplingSpanProcessor) Shutdown(context.Context) error {
+func (tsp *tailSamplingSpanProcessor) Shutdown(ctx context.Context) error {
 	tsp.decisionBatcher.Stop()
 	tsp.policyTicker.Stop()
+	if tsp.buffer != nil {
+		return tsp.buffer.Shutdown(ctx)
+	}
 	return nil
 }
 
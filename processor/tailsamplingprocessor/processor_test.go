@@ -24,14 +24,18 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/storagetest"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/timeutils"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/tracestore"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/idbatcher"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
 )
@@ -569,6 +573,91 @@ func TestMultipleBatchesAreCombinedIntoOne(t *testing.T) {
 	}
 }
 
+func newBufferedTestProcessor(storageDir string, maxSize uint64, mpe sampling.PolicyEvaluator, next *consumertest.TracesSink) (*tailSamplingSpanProcessor, component.Host) {
+	host := storagetest.NewStorageHost().WithFileBackedStorageExtension("test", storageDir)
+	tsp := &tailSamplingSpanProcessor{
+		ctx:             context.Background(),
+		nextConsumer:    next,
+		maxNumTraces:    maxSize,
+		logger:          zap.NewNop(),
+		decisionBatcher: newSyncIDBatcher(1),
+		policies:        []*policy{{name: "mock-policy", evaluator: mpe, ctx: context.TODO()}},
+		deleteChan:      make(chan pcommon.TraceID, maxSize),
+		policyTicker:    &manualTTicker{},
+		tickerFrequency: 100 * time.Millisecond,
+		numTracesOnMap:  atomic.NewUint64(0),
+		buffer:          tracestore.New(storagetest.NewStorageID("test"), config.NewComponentID(typeStr), 0),
+	}
+	return tsp, host
+}
+
+func TestBufferedTracesAreRestoredAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	msp := new(consumertest.TracesSink)
+	mpe := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+
+	tsp, host := newBufferedTestProcessor(dir, 100, mpe, msp)
+	require.NoError(t, tsp.Start(context.Background(), host))
+	traceIds, batches := generateIdsAndBatches(3)
+	for _, batch := range batches {
+		require.NoError(t, tsp.ConsumeTraces(context.Background(), batch))
+	}
+	for _, id := range traceIds {
+		d, ok := tsp.idToTrace.Load(id)
+		require.True(t, ok)
+		require.Empty(t, d.(*sampling.TraceData).ReceivedBatches, "Spans awaiting a decision must be kept in the storage")
+	}
+	require.NoError(t, tsp.Shutdown(context.Background()))
+
+	// the decision is evaluated by the restarted processor
+	tsp, host = newBufferedTestProcessor(dir, 100, mpe, msp)
+	require.NoError(t, tsp.Start(context.Background(), host))
+	defer func() {
+		require.NoError(t, tsp.Shutdown(context.Background()))
+	}()
+	for i, id := range traceIds {
+		d, ok := tsp.idToTrace.Load(id)
+		require.True(t, ok, "Buffered traces must be restored")
+		require.EqualValues(t, i+1, d.(*sampling.TraceData).SpanCount.Load())
+	}
+
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+
+	require.Len(t, msp.AllTraces(), 3)
+	for i, id := range traceIds {
+		require.EqualValues(t, i+1, findTrace(t, msp.AllTraces(), id).SpanCount())
+	}
+	require.Empty(t, tsp.buffer.Traces(), "Decided traces must be removed from the storage")
+}
+
+func TestEvictedTracesAreDecidedFromStorage(t *testing.T) {
+	msp := new(consumertest.TracesSink)
+	mpe := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+
+	// only one trace is kept in memory
+	tsp, host := newBufferedTestProcessor(t.TempDir(), 1, mpe, msp)
+	require.NoError(t, tsp.Start(context.Background(), host))
+	defer func() {
+		require.NoError(t, tsp.Shutdown(context.Background()))
+	}()
+
+	traceIds, batches := generateIdsAndBatches(3)
+	for _, batch := range batches {
+		require.NoError(t, tsp.ConsumeTraces(context.Background(), batch))
+	}
+	_, ok := tsp.idToTrace.Load(traceIds[0])
+	require.False(t, ok, "The first trace must be evicted from memory")
+
+	tsp.samplingPolicyOnTick()
+	tsp.samplingPolicyOnTick()
+
+	require.Len(t, msp.AllTraces(), 3, "Evicted traces must be sampled from the storage")
+	for i, id := range traceIds {
+		require.EqualValues(t, i+1, findTrace(t, msp.AllTraces(), id).SpanCount())
+	}
+}
+
 func collectSpanIds(trace ptrace.Traces) []pcommon.SpanID {
 	var spanIDs []pcommon.SpanID
 
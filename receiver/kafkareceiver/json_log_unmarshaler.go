@@ -0,0 +1,58 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"encoding/json"
+
+	"github.com/Shopify/sarama"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// jsonLogsUnmarshaler parses the message as JSON and sets it as the body of a log record,
+// a JSON object is set as a map body.
+type jsonLogsUnmarshaler struct{}
+
+var _ messageLogsUnmarshaler = (*jsonLogsUnmarshaler)(nil)
+
+func (j jsonLogsUnmarshaler) Unmarshal(buf []byte) (plog.Logs, error) {
+	var raw interface{}
+	if err := json.Unmarshal(buf, &raw); err != nil {
+		return plog.Logs{}, err
+	}
+	logs, record := newSingleRecordLogs()
+	body := record.Body()
+	switch v := raw.(type) {
+	case map[string]interface{}:
+		body.SetEmptyMapVal().FromRaw(v)
+	case []interface{}:
+		body.SetEmptySliceVal().FromRaw(v)
+	case string:
+		body.SetStringVal(v)
+	case float64:
+		body.SetDoubleVal(v)
+	case bool:
+		body.SetBoolVal(v)
+	}
+	return logs, nil
+}
+
+func (j jsonLogsUnmarshaler) unmarshalMessage(message *sarama.ConsumerMessage) (plog.Logs, error) {
+	return unmarshalMessageWithAttributes(j, message)
+}
+
+func (j jsonLogsUnmarshaler) Encoding() string {
+	return "json"
+}
@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLogsUnmarshaler(t *testing.T) {
+	tests := map[string]struct {
+		message string
+		want    interface{}
+	}{
+		"object": {
+			message: `{"level": "info", "msg": "started", "port": 8080, "tags": ["a", "b"]}`,
+			want: map[string]interface{}{
+				"level": "info",
+				"msg":   "started",
+				"port":  float64(8080),
+				"tags":  []interface{}{"a", "b"},
+			},
+		},
+		"array": {
+			message: `[1, "two"]`,
+			want:    []interface{}{float64(1), "two"},
+		},
+		"string": {
+			message: `"hello"`,
+			want:    "hello",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			logs, err := jsonLogsUnmarshaler{}.Unmarshal([]byte(test.message))
+			require.NoError(t, err)
+			require.Equal(t, 1, logs.LogRecordCount())
+			body := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body()
+			switch want := test.want.(type) {
+			case map[string]interface{}:
+				assert.Equal(t, want, body.MapVal().AsRaw())
+			case []interface{}:
+				assert.Equal(t, want, body.SliceVal().AsRaw())
+			default:
+				assert.Equal(t, want, body.StringVal())
+			}
+		})
+	}
+}
+
+func TestJSONLogsUnmarshaler_invalid(t *testing.T) {
+	_, err := jsonLogsUnmarshaler{}.Unmarshal([]byte(`{"unterminated": `))
+	assert.Error(t, err)
+}
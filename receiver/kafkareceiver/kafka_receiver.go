@@ -17,6 +17,7 @@ package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collect
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/Shopify/sarama"
@@ -26,6 +27,7 @@ import (
 	"go.opentelemetry.io/collector/config"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/obsreport"
+	"go.opentelemetry.io/collector/pdata/plog"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
@@ -250,9 +252,9 @@ func (c *kafkaMetricsConsumer) Shutdown(context.Context) error {
 }
 
 func newLogsReceiver(config Config, set component.ReceiverCreateSettings, unmarshalers map[string]LogsUnmarshaler, nextConsumer consumer.Logs) (*kafkaLogsConsumer, error) {
-	unmarshaler := unmarshalers[config.Encoding]
-	if unmarshaler == nil {
-		return nil, errUnrecognizedEncoding
+	unmarshaler, err := getLogsUnmarshaler(config.Encoding, unmarshalers)
+	if err != nil {
+		return nil, err
 	}
 
 	c := sarama.NewConfig()
@@ -286,6 +288,18 @@ func newLogsReceiver(config Config, set component.ReceiverCreateSettings, unmars
 	}, nil
 }
 
+// getLogsUnmarshaler returns the unmarshaler of the encoding, the unmarshalers of the text encodings
+// of any charset, e.g. `text_shift_jis`, are created on demand.
+func getLogsUnmarshaler(encoding string, unmarshalers map[string]LogsUnmarshaler) (LogsUnmarshaler, error) {
+	if unmarshaler, ok := unmarshalers[encoding]; ok {
+		return unmarshaler, nil
+	}
+	if strings.HasPrefix(encoding, textEncodingPrefix) {
+		return newTextLogsUnmarshaler(encoding)
+	}
+	return nil, errUnrecognizedEncoding
+}
+
 func (c *kafkaLogsConsumer) Start(context.Context, component.Host) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	c.cancelConsumeLoop = cancel
@@ -548,7 +562,13 @@ func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSess
 			statMessageOffset.M(message.Offset),
 			statMessageOffsetLag.M(claim.HighWaterMarkOffset()-message.Offset-1))
 
-		logs, err := c.unmarshaler.Unmarshal(message.Value)
+		var logs plog.Logs
+		var err error
+		if unmarshaler, ok := c.unmarshaler.(messageLogsUnmarshaler); ok {
+			logs, err = unmarshaler.unmarshalMessage(message)
+		} else {
+			logs, err = c.unmarshaler.Unmarshal(message.Value)
+		}
 		if err != nil {
 			c.logger.Error("failed to unmarshal message", zap.Error(err))
 			if c.messageMarking.After && c.messageMarking.OnError {
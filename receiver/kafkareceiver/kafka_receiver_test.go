@@ -549,6 +549,60 @@ func TestLogsConsumerGroupHandler(t *testing.T) {
 	wg.Wait()
 }
 
+func TestLogsConsumerGroupHandler_messageAttributes(t *testing.T) {
+	sink := &consumertest.LogsSink{}
+	c := logsConsumerGroupHandler{
+		unmarshaler:  rawLogsUnmarshaler{},
+		logger:       zap.NewNop(),
+		ready:        make(chan bool),
+		nextConsumer: sink,
+		obsrecv:      obsreport.NewReceiver(obsreport.ReceiverSettings{ReceiverCreateSettings: componenttest.NewNopReceiverCreateSettings()}),
+	}
+
+	groupClaim := testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		require.NoError(t, c.ConsumeClaim(testConsumerGroupSession{}, groupClaim))
+		wg.Done()
+	}()
+
+	groupClaim.messageChan <- &sarama.ConsumerMessage{
+		Topic:     "logs",
+		Partition: 3,
+		Offset:    42,
+		Value:     []byte("hello"),
+		Headers:   []*sarama.RecordHeader{{Key: []byte("tenant"), Value: []byte("acme")}},
+	}
+	close(groupClaim.messageChan)
+	wg.Wait()
+
+	require.Len(t, sink.AllLogs(), 1)
+	record := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.Equal(t, []byte("hello"), record.Body().BytesVal().AsRaw())
+	assert.Equal(t, map[string]interface{}{
+		"kafka.topic":         "logs",
+		"kafka.partition":     int64(3),
+		"kafka.offset":        int64(42),
+		"kafka.header.tenant": "acme",
+	}, record.Attributes().AsRaw())
+}
+
+func TestGetLogsUnmarshaler(t *testing.T) {
+	unmarshaler, err := getLogsUnmarshaler("text_shift_jis", defaultLogsUnmarshalers())
+	require.NoError(t, err)
+	assert.Equal(t, "text_shift_jis", unmarshaler.Encoding())
+
+	_, err = getLogsUnmarshaler("text_unknown", defaultLogsUnmarshalers())
+	assert.ErrorContains(t, err, "unsupported charset 'unknown'")
+
+	_, err = getLogsUnmarshaler("unknown", defaultLogsUnmarshalers())
+	assert.ErrorIs(t, err, errUnrecognizedEncoding)
+}
+
 func TestLogsConsumerGroupHandler_error_unmarshal(t *testing.T) {
 	c := logsConsumerGroupHandler{
 		unmarshaler:  newPdataLogsUnmarshaler(plog.NewProtoUnmarshaler(), defaultEncoding),
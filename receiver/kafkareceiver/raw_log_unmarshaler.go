@@ -0,0 +1,130 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Shopify/sarama"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// textEncodingPrefix is the prefix of the text encodings of the messages written in another charset
+// than UTF-8, e.g. `text_shift_jis`.
+const textEncodingPrefix = "text_"
+
+// messageLogsUnmarshaler is implemented by the logs unmarshalers that produce a single log record per
+// message, whose attributes are set from the topic, partition, offset and headers of the message.
+type messageLogsUnmarshaler interface {
+	LogsUnmarshaler
+
+	unmarshalMessage(message *sarama.ConsumerMessage) (plog.Logs, error)
+}
+
+// rawLogsUnmarshaler sets the message as the bytes body of a log record.
+type rawLogsUnmarshaler struct{}
+
+var _ messageLogsUnmarshaler = (*rawLogsUnmarshaler)(nil)
+
+func (r rawLogsUnmarshaler) Unmarshal(buf []byte) (plog.Logs, error) {
+	logs, record := newSingleRecordLogs()
+	record.Body().SetBytesVal(pcommon.NewImmutableByteSlice(buf))
+	return logs, nil
+}
+
+func (r rawLogsUnmarshaler) unmarshalMessage(message *sarama.ConsumerMessage) (plog.Logs, error) {
+	return unmarshalMessageWithAttributes(r, message)
+}
+
+func (r rawLogsUnmarshaler) Encoding() string {
+	return "raw"
+}
+
+// textLogsUnmarshaler decodes the message from its charset and sets it as the string body of a log record.
+type textLogsUnmarshaler struct {
+	encodingName string
+	enc          encoding.Encoding
+}
+
+var _ messageLogsUnmarshaler = (*textLogsUnmarshaler)(nil)
+
+// newTextLogsUnmarshaler returns the unmarshaler of the `text` encoding, or of a `text_<charset>` encoding.
+func newTextLogsUnmarshaler(encodingName string) (*textLogsUnmarshaler, error) {
+	if encodingName == "text" {
+		return &textLogsUnmarshaler{encodingName: encodingName, enc: unicode.UTF8}, nil
+	}
+	charset := strings.TrimPrefix(encodingName, textEncodingPrefix)
+	enc, err := ianaindex.IANA.Encoding(charset)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset '%s': %w", charset, err)
+	}
+	if enc == nil {
+		return nil, fmt.Errorf("no charset defined for '%s'", charset)
+	}
+	return &textLogsUnmarshaler{encodingName: encodingName, enc: enc}, nil
+}
+
+func (t *textLogsUnmarshaler) Unmarshal(buf []byte) (plog.Logs, error) {
+	decoded, err := t.enc.NewDecoder().Bytes(buf)
+	if err != nil {
+		return plog.Logs{}, err
+	}
+	logs, record := newSingleRecordLogs()
+	record.Body().SetStringVal(string(decoded))
+	return logs, nil
+}
+
+func (t *textLogsUnmarshaler) unmarshalMessage(message *sarama.ConsumerMessage) (plog.Logs, error) {
+	return unmarshalMessageWithAttributes(t, message)
+}
+
+func (t *textLogsUnmarshaler) Encoding() string {
+	return t.encodingName
+}
+
+func newSingleRecordLogs() (plog.Logs, plog.LogRecord) {
+	logs := plog.NewLogs()
+	record := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	return logs, record
+}
+
+// unmarshalMessageWithAttributes unmarshals the value of the message into a single log record, whose
+// attributes are set from the message, and whose observed timestamp is the timestamp of the message.
+func unmarshalMessageWithAttributes(unmarshaler LogsUnmarshaler, message *sarama.ConsumerMessage) (plog.Logs, error) {
+	logs, err := unmarshaler.Unmarshal(message.Value)
+	if err != nil {
+		return logs, err
+	}
+	record := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	if !message.Timestamp.IsZero() {
+		record.SetObservedTimestamp(pcommon.NewTimestampFromTime(message.Timestamp))
+	}
+	attrs := record.Attributes()
+	attrs.UpsertString("kafka.topic", message.Topic)
+	attrs.UpsertInt("kafka.partition", int64(message.Partition))
+	attrs.UpsertInt("kafka.offset", message.Offset)
+	for _, header := range message.Headers {
+		if header == nil {
+			continue
+		}
+		attrs.UpsertString("kafka.header."+string(header.Key), string(header.Value))
+	}
+	return logs, nil
+}
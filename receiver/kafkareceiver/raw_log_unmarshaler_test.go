@@ -0,0 +1,71 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kafkareceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRawLogsUnmarshaler(t *testing.T) {
+	logs, err := rawLogsUnmarshaler{}.Unmarshal([]byte{0x00, 0xff})
+	require.NoError(t, err)
+	require.Equal(t, 1, logs.LogRecordCount())
+	body := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body()
+	assert.Equal(t, []byte{0x00, 0xff}, body.BytesVal().AsRaw())
+}
+
+func TestTextLogsUnmarshaler(t *testing.T) {
+	tests := map[string]struct {
+		encoding string
+		message  []byte
+		want     string
+	}{
+		"utf-8": {
+			encoding: "text",
+			message:  []byte("héllo wörld"),
+			want:     "héllo wörld",
+		},
+		"shift_jis": {
+			encoding: "text_shift_jis",
+			message:  []byte{0x82, 0xb1, 0x82, 0xf1, 0x82, 0xc9, 0x82, 0xbf, 0x82, 0xcd},
+			want:     "こんにちは",
+		},
+		"iso-8859-1": {
+			encoding: "text_iso-8859-1",
+			message:  []byte{0x63, 0x61, 0x66, 0xe9},
+			want:     "café",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			unmarshaler, err := newTextLogsUnmarshaler(test.encoding)
+			require.NoError(t, err)
+			assert.Equal(t, test.encoding, unmarshaler.Encoding())
+			logs, err := unmarshaler.Unmarshal(test.message)
+			require.NoError(t, err)
+			require.Equal(t, 1, logs.LogRecordCount())
+			body := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0).Body()
+			assert.Equal(t, test.want, body.StringVal())
+		})
+	}
+}
+
+func TestTextLogsUnmarshaler_unsupportedCharset(t *testing.T) {
+	_, err := newTextLogsUnmarshaler("text_klingon")
+	assert.ErrorContains(t, err, "unsupported charset 'klingon'")
+}
@@ -77,7 +77,13 @@ func defaultMetricsUnmarshalers() map[string]MetricsUnmarshaler {
 
 func defaultLogsUnmarshalers() map[string]LogsUnmarshaler {
 	otlpPb := newPdataLogsUnmarshaler(plog.NewProtoUnmarshaler(), defaultEncoding)
+	raw := rawLogsUnmarshaler{}
+	text, _ := newTextLogsUnmarshaler("text")
+	jsonRecord := jsonLogsUnmarshaler{}
 	return map[string]LogsUnmarshaler{
-		otlpPb.Encoding(): otlpPb,
+		otlpPb.Encoding():     otlpPb,
+		raw.Encoding():        raw,
+		text.Encoding():       text,
+		jsonRecord.Encoding(): jsonRecord,
 	}
 }
@@ -59,6 +59,9 @@ func TestDefaultMetricsUnMarshaler(t *testing.T) {
 func TestDefaultLogsUnMarshaler(t *testing.T) {
 	expectedEncodings := []string{
 		"otlp_proto",
+		"raw",
+		"text",
+		"json",
 	}
 	marshalers := defaultLogsUnmarshalers()
 	assert.Equal(t, len(expectedEncodings), len(marshalers))
@@ -29,6 +29,8 @@ type Config struct {
 	Driver                                  string  `mapstructure:"driver"`
 	DataSource                              string  `mapstructure:"datasource"`
 	Queries                                 []Query `mapstructure:"queries"`
+	// StorageID is the ID of the storage extension used to persist the tracking values of the logs queries.
+	StorageID *config.ComponentID `mapstructure:"storage"`
 }
 
 func (c Config) Validate() error {
@@ -52,6 +54,12 @@ func (c Config) Validate() error {
 type Query struct {
 	SQL     string      `mapstructure:"sql"`
 	Metrics []MetricCfg `mapstructure:"metrics"`
+	Logs    []LogsCfg   `mapstructure:"logs"`
+	// TrackingColumn is the column whose value in the last returned row is passed as the parameter
+	// of the sql statement at the next execution, so that only new rows are turned into logs.
+	TrackingColumn string `mapstructure:"tracking_column"`
+	// TrackingStartValue is the parameter of the sql statement before any row has been returned.
+	TrackingStartValue string `mapstructure:"tracking_start_value"`
 }
 
 func (q Query) Validate() error {
@@ -59,17 +67,42 @@ func (q Query) Validate() error {
 	if q.SQL == "" {
 		errs = multierr.Append(errs, errors.New("'query.sql' cannot be empty"))
 	}
-	if len(q.Metrics) == 0 {
-		errs = multierr.Append(errs, errors.New("'query.metrics' cannot be empty"))
+	if len(q.Metrics) == 0 && len(q.Logs) == 0 {
+		errs = multierr.Append(errs, errors.New("'query.metrics' and 'query.logs' cannot both be empty"))
 	}
 	for _, metric := range q.Metrics {
 		if err := metric.Validate(); err != nil {
 			errs = multierr.Append(errs, err)
 		}
 	}
+	for _, logs := range q.Logs {
+		if err := logs.Validate(); err != nil {
+			errs = multierr.Append(errs, err)
+		}
+	}
+	if q.TrackingColumn != "" && len(q.Logs) == 0 {
+		errs = multierr.Append(errs, errors.New("'query.tracking_column' requires 'query.logs'"))
+	}
+	// The metrics are scraped without the parameter of the tracking column.
+	if q.TrackingColumn != "" && len(q.Metrics) != 0 {
+		errs = multierr.Append(errs, errors.New("'query.tracking_column' cannot be used with 'query.metrics'"))
+	}
 	return errs
 }
 
+type LogsCfg struct {
+	BodyColumn       string   `mapstructure:"body_column"`
+	AttributeColumns []string `mapstructure:"attribute_columns"`
+	TimestampColumn  string   `mapstructure:"timestamp_column"`
+}
+
+func (c LogsCfg) Validate() error {
+	if c.BodyColumn == "" {
+		return errors.New("'body_column' cannot be empty")
+	}
+	return nil
+}
+
 type MetricCfg struct {
 	MetricName       string            `mapstructure:"metric_name"`
 	ValueColumn      string            `mapstructure:"value_column"`
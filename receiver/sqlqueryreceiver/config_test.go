@@ -49,6 +49,15 @@ func TestParseConfig(t *testing.T) {
 	assert.Equal(t, MetricValueTypeInt, metric.ValueType)
 	assert.Equal(t, map[string]string{"foo": "bar"}, metric.StaticAttributes)
 	assert.Equal(t, MetricAggregationCumulative, metric.Aggregation)
+
+	q = sqlCfg.Queries[1]
+	assert.Equal(t, "id", q.TrackingColumn)
+	assert.Equal(t, "100", q.TrackingStartValue)
+	assert.Equal(t, []LogsCfg{{
+		BodyColumn:       "message",
+		AttributeColumns: []string{"id"},
+		TimestampColumn:  "created_at",
+	}}, q.Logs)
 }
 
 func TestValidateConfig_Invalid(t *testing.T) {
@@ -90,12 +99,16 @@ func TestValidateConfig_Invalid(t *testing.T) {
 		},
 		{
 			fname:     "config-invalid-missing-metrics.yaml",
-			errSubstr: "'query.metrics' cannot be empty",
+			errSubstr: "'query.metrics' and 'query.logs' cannot both be empty",
 		},
 		{
 			fname:     "config-invalid-missing-datasource.yaml",
 			errSubstr: "'datasource' cannot be empty",
 		},
+		{
+			fname:     "config-invalid-tracking-metrics.yaml",
+			errSubstr: "'query.tracking_column' cannot be used with 'query.metrics'",
+		},
 		{
 			fname:     "config-unnecessary-aggregation.yaml",
 			errSubstr: "aggregation=cumulative but data_type=gauge does not support aggregation",
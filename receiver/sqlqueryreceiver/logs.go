@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/sqlqueryreceiver"
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+// timestampLayouts are the layouts tried in order to parse the values of a timestamp_column.
+// The last one is the layout of the time.Time values returned by the drivers, as rendered by fmt.
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+}
+
+func rowToLog(row stringMap, cfg LogsCfg, dest plog.LogRecord, observedTime pcommon.Timestamp) error {
+	dest.SetObservedTimestamp(observedTime)
+	body, found := row[cfg.BodyColumn]
+	if !found {
+		return fmt.Errorf("rowToLog: body_column '%s' not found in result set", cfg.BodyColumn)
+	}
+	dest.Body().SetStringVal(body)
+	if cfg.TimestampColumn != "" {
+		value, found := row[cfg.TimestampColumn]
+		if !found {
+			return fmt.Errorf("rowToLog: timestamp_column '%s' not found in result set", cfg.TimestampColumn)
+		}
+		ts, err := parseTimestamp(value)
+		if err != nil {
+			return fmt.Errorf("rowToLog: %w", err)
+		}
+		dest.SetTimestamp(ts)
+	}
+	attrs := dest.Attributes()
+	for _, columnName := range cfg.AttributeColumns {
+		if attrVal, found := row[columnName]; found {
+			attrs.UpsertString(columnName, attrVal)
+		} else {
+			return fmt.Errorf("rowToLog: attribute_column not found: '%s'", columnName)
+		}
+	}
+	return nil
+}
+
+// parseTimestamp parses a date and time, or a number of seconds since the Unix epoch.
+func parseTimestamp(value string) (pcommon.Timestamp, error) {
+	for _, layout := range timestampLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return pcommon.NewTimestampFromTime(t), nil
+		}
+	}
+	if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+		return pcommon.Timestamp(seconds * float64(time.Second)), nil
+	}
+	return 0, fmt.Errorf("parseTimestamp: unsupported timestamp '%s'", value)
+}
@@ -0,0 +1,234 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/sqlqueryreceiver"
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/multierr"
+	"go.uber.org/zap"
+)
+
+// logsReceiver executes the queries that define logs at each collection interval, and turns
+// each returned row into log records.
+type logsReceiver struct {
+	id                 config.ComponentID
+	config             *Config
+	logger             *zap.Logger
+	consumer           consumer.Logs
+	dbProviderFunc     dbProviderFunc
+	clientProviderFunc clientProviderFunc
+
+	db            *sql.DB
+	queries       []*logsQuery
+	storageClient storage.Client
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// logsQuery is a query that defines logs, along with the value of its tracking column.
+type logsQuery struct {
+	query         Query
+	client        dbClient
+	trackingValue string
+}
+
+var _ component.LogsReceiver = (*logsReceiver)(nil)
+
+func createLogsReceiverFunc(sqlOpenerFunc sqlOpenerFunc, clientProviderFunc clientProviderFunc) component.CreateLogsReceiverFunc {
+	return func(
+		ctx context.Context,
+		settings component.ReceiverCreateSettings,
+		cfg config.Receiver,
+		consumer consumer.Logs,
+	) (component.LogsReceiver, error) {
+		sqlCfg := cfg.(*Config)
+		return &logsReceiver{
+			id:       sqlCfg.ID(),
+			config:   sqlCfg,
+			logger:   settings.TelemetrySettings.Logger,
+			consumer: consumer,
+			dbProviderFunc: func() (*sql.DB, error) {
+				return sqlOpenerFunc(sqlCfg.Driver, sqlCfg.DataSource)
+			},
+			clientProviderFunc: clientProviderFunc,
+		}, nil
+	}
+}
+
+func (r *logsReceiver) Start(ctx context.Context, host component.Host) error {
+	var err error
+	r.db, err = r.dbProviderFunc()
+	if err != nil {
+		return fmt.Errorf("failed to open db connection: %w", err)
+	}
+
+	r.storageClient, err = getStorageClient(ctx, host, r.config.StorageID, r.id)
+	if err != nil {
+		return err
+	}
+
+	for _, query := range r.config.Queries {
+		if len(query.Logs) == 0 {
+			continue
+		}
+		q := &logsQuery{
+			query:         query,
+			client:        r.clientProviderFunc(r.db, query.SQL, r.logger),
+			trackingValue: query.TrackingStartValue,
+		}
+		if query.TrackingColumn != "" {
+			value, err := r.storageClient.Get(ctx, trackingValueKey(query))
+			if err != nil {
+				return fmt.Errorf("failed to read tracking value: %w", err)
+			}
+			if value != nil {
+				q.trackingValue = string(value)
+			}
+		}
+		r.queries = append(r.queries, q)
+	}
+
+	var runCtx context.Context
+	runCtx, r.cancel = context.WithCancel(context.Background())
+	r.wg.Add(1)
+	go r.run(runCtx)
+	return nil
+}
+
+func (r *logsReceiver) run(ctx context.Context) {
+	defer r.wg.Done()
+	ticker := time.NewTicker(r.config.CollectionInterval)
+	defer ticker.Stop()
+	for {
+		r.collect(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *logsReceiver) collect(ctx context.Context) {
+	for _, q := range r.queries {
+		if err := r.collectQuery(ctx, q); err != nil {
+			r.logger.Error("Failed to collect logs", zap.String("query", q.query.SQL), zap.Error(err))
+		}
+	}
+}
+
+// collectQuery executes the query and sends its logs to the next consumer. The tracking value is
+// only updated, and persisted, once the logs are accepted, so that they are collected again otherwise.
+// Rows that cannot be turned into logs are dropped and do not hold the tracking value back: the
+// conversion would fail again at every execution.
+func (r *logsReceiver) collectQuery(ctx context.Context, q *logsQuery) error {
+	var args []interface{}
+	if q.query.TrackingColumn != "" {
+		args = append(args, q.trackingValue)
+	}
+	rows, err := q.client.queryRows(ctx, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	logs := plog.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	observedTime := pcommon.NewTimestampFromTime(time.Now())
+	var errs error
+	for i, row := range rows {
+		for _, logsCfg := range q.query.Logs {
+			record := plog.NewLogRecord()
+			if err = rowToLog(row, logsCfg, record, observedTime); err != nil {
+				errs = multierr.Append(errs, fmt.Errorf("row %d: %w", i, err))
+				continue
+			}
+			record.MoveTo(records.AppendEmpty())
+		}
+	}
+	if errs != nil {
+		r.logger.Error("Dropped rows that could not be converted to logs", zap.Error(errs))
+	}
+
+	if records.Len() > 0 {
+		if err = r.consumer.ConsumeLogs(ctx, logs); err != nil {
+			return err
+		}
+	}
+
+	if q.query.TrackingColumn == "" {
+		return nil
+	}
+	value, found := rows[len(rows)-1][q.query.TrackingColumn]
+	if !found {
+		return fmt.Errorf("tracking_column '%s' not found in result set", q.query.TrackingColumn)
+	}
+	q.trackingValue = value
+	return r.storageClient.Set(ctx, trackingValueKey(q.query), []byte(value))
+}
+
+func (r *logsReceiver) Shutdown(ctx context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+
+	var errs error
+	if r.storageClient != nil {
+		errs = multierr.Append(errs, r.storageClient.Close(ctx))
+	}
+	if r.db != nil {
+		errs = multierr.Append(errs, r.db.Close())
+	}
+	return errs
+}
+
+// trackingValueKey is the storage key of the tracking value of a query. The sql statement is
+// part of the key so that a changed statement starts again from its tracking_start_value.
+func trackingValueKey(query Query) string {
+	return fmt.Sprintf("tracking_value.%s.%s", query.TrackingColumn, query.SQL)
+}
+
+func getStorageClient(ctx context.Context, host component.Host, storageID *config.ComponentID, componentID config.ComponentID) (storage.Client, error) {
+	if storageID == nil {
+		return storage.NewNopClient(), nil
+	}
+
+	extension, ok := host.GetExtensions()[*storageID]
+	if !ok {
+		return nil, fmt.Errorf("storage extension '%s' not found", storageID)
+	}
+
+	storageExtension, ok := extension.(storage.Extension)
+	if !ok {
+		return nil, fmt.Errorf("non-storage extension '%s' found", storageID)
+	}
+
+	return storageExtension.GetClient(ctx, component.KindReceiver, componentID, "")
+}
@@ -0,0 +1,190 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.uber.org/zap"
+)
+
+func TestLogsReceiver_Tracking(t *testing.T) {
+	storageID := config.NewComponentID("teststorage")
+	host := &storageHost{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[config.ComponentID]component.Extension{storageID: newMemoryStorage()},
+	}
+	query := Query{
+		SQL:                "select id, msg from audit where id > $1 order by id",
+		Logs:               []LogsCfg{{BodyColumn: "msg", AttributeColumns: []string{"id"}}},
+		TrackingColumn:     "id",
+		TrackingStartValue: "0",
+	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.CollectionInterval = time.Hour
+	cfg.StorageID = &storageID
+	cfg.Queries = []Query{query, {SQL: "select count(*) from audit", Metrics: []MetricCfg{{MetricName: "count", ValueColumn: "count"}}}}
+
+	client := &fakeDBClient{responses: [][]stringMap{
+		{{"id": "1", "msg": "first"}, {"id": "2", "msg": "second"}},
+		{{"id": "3", "msg": "third"}},
+	}}
+	sink := &consumertest.LogsSink{}
+	receiver := newTestLogsReceiver(t, cfg, client, sink)
+
+	require.NoError(t, receiver.Start(context.Background(), host))
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 2 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, receiver.Shutdown(context.Background()))
+
+	records := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	assert.Equal(t, "first", records.At(0).Body().StringVal())
+	assert.Equal(t, "second", records.At(1).Body().StringVal())
+	assert.Equal(t, [][]interface{}{{"0"}}, client.args)
+
+	// The tracking value is restored from the storage at restart.
+	receiver = newTestLogsReceiver(t, cfg, client, sink)
+	require.NoError(t, receiver.Start(context.Background(), host))
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 3 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, receiver.Shutdown(context.Background()))
+
+	assert.Equal(t, [][]interface{}{{"0"}, {"2"}}, client.args)
+}
+
+func TestLogsReceiver_ConversionError(t *testing.T) {
+	storageID := config.NewComponentID("teststorage")
+	host := &storageHost{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[config.ComponentID]component.Extension{storageID: newMemoryStorage()},
+	}
+	cfg := createDefaultConfig().(*Config)
+	cfg.CollectionInterval = time.Hour
+	cfg.StorageID = &storageID
+	cfg.Queries = []Query{{
+		SQL:                "select id, msg, ts from audit where id > $1 order by id",
+		Logs:               []LogsCfg{{BodyColumn: "msg", TimestampColumn: "ts"}},
+		TrackingColumn:     "id",
+		TrackingStartValue: "0",
+	}}
+
+	client := &fakeDBClient{responses: [][]stringMap{
+		{{"id": "1", "msg": "first", "ts": "1"}, {"id": "2", "msg": "second", "ts": "invalid"}},
+		{{"id": "3", "ts": "3"}},
+	}}
+	sink := &consumertest.LogsSink{}
+	receiver := newTestLogsReceiver(t, cfg, client, sink)
+
+	require.NoError(t, receiver.Start(context.Background(), host))
+	require.Eventually(t, func() bool { return sink.LogRecordCount() == 1 }, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, receiver.Shutdown(context.Background()))
+
+	records := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+	assert.Equal(t, "first", records.At(0).Body().StringVal())
+
+	// The failed row is not collected again, and a result set without any
+	// valid row is not sent.
+	// The query is executed once at start, before Shutdown returns.
+	receiver = newTestLogsReceiver(t, cfg, client, sink)
+	require.NoError(t, receiver.Start(context.Background(), host))
+	require.NoError(t, receiver.Shutdown(context.Background()))
+
+	assert.Equal(t, [][]interface{}{{"0"}, {"2"}}, client.args)
+	assert.Len(t, sink.AllLogs(), 1)
+}
+
+func TestLogsReceiver_MissingStorage(t *testing.T) {
+	storageID := config.NewComponentID("teststorage")
+	cfg := createDefaultConfig().(*Config)
+	cfg.StorageID = &storageID
+	receiver := newTestLogsReceiver(t, cfg, &fakeDBClient{}, consumertest.NewNop())
+	err := receiver.Start(context.Background(), componenttest.NewNopHost())
+	require.ErrorContains(t, err, "storage extension 'teststorage' not found")
+}
+
+func newTestLogsReceiver(t *testing.T, cfg *Config, client dbClient, next consumer.Logs) component.LogsReceiver {
+	createReceiver := createLogsReceiverFunc(fakeDBConnect, func(*sql.DB, string, *zap.Logger) dbClient {
+		return client
+	})
+	receiver, err := createReceiver(context.Background(), componenttest.NewNopReceiverCreateSettings(), cfg, next)
+	require.NoError(t, err)
+	return receiver
+}
+
+type storageHost struct {
+	component.Host
+	extensions map[config.ComponentID]component.Extension
+}
+
+func (h *storageHost) GetExtensions() map[config.ComponentID]component.Extension {
+	return h.extensions
+}
+
+// memoryStorage is a storage extension whose clients share the same in-memory map,
+// so that the values set by a receiver are found after its restart.
+type memoryStorage struct {
+	component.StartFunc
+	component.ShutdownFunc
+	data map[string][]byte
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{data: map[string][]byte{}}
+}
+
+func (s *memoryStorage) GetClient(context.Context, component.Kind, config.ComponentID, string) (storage.Client, error) {
+	return s, nil
+}
+
+func (s *memoryStorage) Get(_ context.Context, key string) ([]byte, error) {
+	return s.data[key], nil
+}
+
+func (s *memoryStorage) Set(_ context.Context, key string, value []byte) error {
+	s.data[key] = value
+	return nil
+}
+
+func (s *memoryStorage) Delete(_ context.Context, key string) error {
+	delete(s.data, key)
+	return nil
+}
+
+func (s *memoryStorage) Batch(ctx context.Context, ops ...storage.Operation) error {
+	for _, op := range ops {
+		switch op.Type {
+		case storage.Get:
+			op.Value = s.data[op.Key]
+		case storage.Set:
+			s.data[op.Key] = op.Value
+		case storage.Delete:
+			delete(s.data, op.Key)
+		}
+	}
+	return nil
+}
+
+func (s *memoryStorage) Close(context.Context) error {
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//       http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqlqueryreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestRowToLog(t *testing.T) {
+	observedTime := pcommon.NewTimestampFromTime(time.Now())
+	record := plog.NewLogRecord()
+	err := rowToLog(stringMap{
+		"message": "user logged in",
+		"user":    "jdoe",
+		"ts":      "2022-09-01 10:00:00.5 +0000 UTC",
+	}, LogsCfg{
+		BodyColumn:       "message",
+		AttributeColumns: []string{"user"},
+		TimestampColumn:  "ts",
+	}, record, observedTime)
+	require.NoError(t, err)
+	assert.Equal(t, "user logged in", record.Body().StringVal())
+	assert.Equal(t, map[string]interface{}{"user": "jdoe"}, record.Attributes().AsRaw())
+	assert.Equal(t, time.Date(2022, 9, 1, 10, 0, 0, 500000000, time.UTC), record.Timestamp().AsTime())
+	assert.Equal(t, observedTime, record.ObservedTimestamp())
+}
+
+func TestRowToLog_Errors(t *testing.T) {
+	row := stringMap{"message": "hello", "ts": "yesterday"}
+	tests := map[string]struct {
+		cfg       LogsCfg
+		errSubstr string
+	}{
+		"missing body column": {
+			cfg:       LogsCfg{BodyColumn: "body"},
+			errSubstr: "body_column 'body' not found",
+		},
+		"missing attribute column": {
+			cfg:       LogsCfg{BodyColumn: "message", AttributeColumns: []string{"user"}},
+			errSubstr: "attribute_column not found: 'user'",
+		},
+		"missing timestamp column": {
+			cfg:       LogsCfg{BodyColumn: "message", TimestampColumn: "time"},
+			errSubstr: "timestamp_column 'time' not found",
+		},
+		"invalid timestamp": {
+			cfg:       LogsCfg{BodyColumn: "message", TimestampColumn: "ts"},
+			errSubstr: "unsupported timestamp 'yesterday'",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := rowToLog(row, test.cfg, plog.NewLogRecord(), 0)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), test.errSubstr)
+		})
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	want := time.Date(2022, 9, 1, 10, 0, 0, 0, time.UTC)
+	for _, value := range []string{
+		"2022-09-01T10:00:00Z",
+		"2022-09-01 10:00:00",
+		"2022-09-01 10:00:00 +0000 UTC",
+		"1662026400",
+	} {
+		ts, err := parseTimestamp(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, ts.AsTime(), value)
+	}
+}
@@ -61,7 +61,7 @@ func (s *scraper) Start(context.Context, component.Host) error {
 
 func (s scraper) Scrape(ctx context.Context) (pmetric.Metrics, error) {
 	out := pmetric.NewMetrics()
-	rows, err := s.client.metricRows(ctx)
+	rows, err := s.client.queryRows(ctx)
 	ts := pcommon.NewTimestampFromTime(time.Now())
 	if err != nil {
 		return out, fmt.Errorf("scraper: %w", err)
@@ -16,6 +16,7 @@ package statsdreceiver // import "github.com/open-telemetry/opentelemetry-collec
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"go.opentelemetry.io/collector/config"
@@ -33,6 +34,9 @@ type Config struct {
 	EnableMetricType        bool                             `mapstructure:"enable_metric_type"`
 	IsMonotonicCounter      bool                             `mapstructure:"is_monotonic_counter"`
 	TimerHistogramMapping   []protocol.TimerHistogramMapping `mapstructure:"timer_histogram_mapping"`
+	// MaxConnections limits the number of concurrent client connections
+	// for the "tcp" and "unix" transports. Zero means no limit.
+	MaxConnections int `mapstructure:"max_connections"`
 }
 
 func (c *Config) validate() error {
@@ -43,6 +47,10 @@ func (c *Config) validate() error {
 		errs = multierr.Append(errs, fmt.Errorf("aggregation_interval must be a positive duration"))
 	}
 
+	if c.MaxConnections < 0 {
+		errs = multierr.Append(errs, fmt.Errorf("max_connections must not be negative"))
+	}
+
 	var TimerHistogramMappingMissingObjectName bool
 	for _, eachMap := range c.TimerHistogramMapping {
 
@@ -52,7 +60,7 @@ func (c *Config) validate() error {
 		}
 
 		switch eachMap.StatsdType {
-		case protocol.TimingTypeName, protocol.TimingAltTypeName, protocol.HistogramTypeName:
+		case protocol.TimingTypeName, protocol.TimingAltTypeName, protocol.HistogramTypeName, protocol.DistributionTypeName:
 		default:
 			errs = multierr.Append(errs, fmt.Errorf("statsd_type is not a supported mapping: %s", eachMap.StatsdType))
 		}
@@ -63,10 +71,26 @@ func (c *Config) validate() error {
 		}
 
 		switch eachMap.ObserverType {
-		case protocol.GaugeObserver, protocol.SummaryObserver:
+		case protocol.GaugeObserver, protocol.SummaryObserver, protocol.HistogramObserver:
 		default:
 			errs = multierr.Append(errs, fmt.Errorf("observer_type is not supported: %s", eachMap.ObserverType))
 		}
+
+		if eachMap.ObserverType != protocol.HistogramObserver {
+			if eachMap.Histogram.MaxSize != 0 || len(eachMap.Histogram.ExplicitBuckets) != 0 {
+				errs = multierr.Append(errs, fmt.Errorf("histogram configuration requires observer_type histogram: %s", eachMap.StatsdType))
+			}
+			continue
+		}
+		if eachMap.Histogram.MaxSize < 0 {
+			errs = multierr.Append(errs, fmt.Errorf("histogram max_size must not be negative: %s", eachMap.StatsdType))
+		}
+		if eachMap.Histogram.MaxSize != 0 && len(eachMap.Histogram.ExplicitBuckets) != 0 {
+			errs = multierr.Append(errs, fmt.Errorf("histogram max_size and explicit_buckets cannot both be set: %s", eachMap.StatsdType))
+		}
+		if !sort.Float64sAreSorted(eachMap.Histogram.ExplicitBuckets) {
+			errs = multierr.Append(errs, fmt.Errorf("histogram explicit_buckets must be sorted in ascending order: %s", eachMap.StatsdType))
+		}
 	}
 
 	if TimerHistogramMappingMissingObjectName {
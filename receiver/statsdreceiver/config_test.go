@@ -53,8 +53,13 @@ func TestLoadConfig(t *testing.T) {
 			Endpoint:  "localhost:12345",
 			Transport: "custom_transport",
 		},
-		AggregationInterval:   70 * time.Second,
-		TimerHistogramMapping: []protocol.TimerHistogramMapping{{StatsdType: "histogram", ObserverType: "gauge"}, {StatsdType: "timing", ObserverType: "gauge"}},
+		AggregationInterval: 70 * time.Second,
+		TimerHistogramMapping: []protocol.TimerHistogramMapping{
+			{StatsdType: "histogram", ObserverType: "gauge"},
+			{StatsdType: "timing", ObserverType: "gauge"},
+			{StatsdType: "distribution", ObserverType: "histogram", Histogram: protocol.HistogramConfig{MaxSize: 100}},
+		},
+		MaxConnections: 10,
 	}, r1)
 }
 
@@ -70,6 +75,11 @@ func TestValidate(t *testing.T) {
 		noObjectNameErr                = "must specify object id for all TimerHistogramMappings"
 		statsdTypeNotSupportErr        = "statsd_type is not a supported mapping: %s"
 		observerTypeNotSupportErr      = "observer_type is not supported: %s"
+		negativeMaxConnectionsErr      = "max_connections must not be negative"
+		histogramWithoutObserverErr    = "histogram configuration requires observer_type histogram: %s"
+		negativeHistogramMaxSizeErr    = "histogram max_size must not be negative: %s"
+		histogramBothBucketsErr        = "histogram max_size and explicit_buckets cannot both be set: %s"
+		unsortedExplicitBucketsErr     = "histogram explicit_buckets must be sorted in ascending order: %s"
 	)
 
 	tests := []test{
@@ -123,6 +133,54 @@ func TestValidate(t *testing.T) {
 			},
 			expectedErr: fmt.Sprintf(observerTypeNotSupportErr, "gauge1"),
 		},
+		{
+			name: "negativeMaxConnections",
+			cfg: &Config{
+				AggregationInterval: 10,
+				MaxConnections:      -1,
+			},
+			expectedErr: negativeMaxConnectionsErr,
+		},
+		{
+			name: "histogramConfigWithoutHistogramObserver",
+			cfg: &Config{
+				AggregationInterval: 10,
+				TimerHistogramMapping: []protocol.TimerHistogramMapping{
+					{StatsdType: "timer", ObserverType: "summary", Histogram: protocol.HistogramConfig{MaxSize: 10}},
+				},
+			},
+			expectedErr: fmt.Sprintf(histogramWithoutObserverErr, "timer"),
+		},
+		{
+			name: "negativeHistogramMaxSize",
+			cfg: &Config{
+				AggregationInterval: 10,
+				TimerHistogramMapping: []protocol.TimerHistogramMapping{
+					{StatsdType: "distribution", ObserverType: "histogram", Histogram: protocol.HistogramConfig{MaxSize: -1}},
+				},
+			},
+			expectedErr: fmt.Sprintf(negativeHistogramMaxSizeErr, "distribution"),
+		},
+		{
+			name: "histogramBothMaxSizeAndExplicitBuckets",
+			cfg: &Config{
+				AggregationInterval: 10,
+				TimerHistogramMapping: []protocol.TimerHistogramMapping{
+					{StatsdType: "histogram", ObserverType: "histogram", Histogram: protocol.HistogramConfig{MaxSize: 10, ExplicitBuckets: []float64{1}}},
+				},
+			},
+			expectedErr: fmt.Sprintf(histogramBothBucketsErr, "histogram"),
+		},
+		{
+			name: "unsortedExplicitBuckets",
+			cfg: &Config{
+				AggregationInterval: 10,
+				TimerHistogramMapping: []protocol.TimerHistogramMapping{
+					{StatsdType: "timing", ObserverType: "histogram", Histogram: protocol.HistogramConfig{ExplicitBuckets: []float64{10, 1}}},
+				},
+			},
+			expectedErr: fmt.Sprintf(unsortedExplicitBucketsErr, "timing"),
+		},
 	}
 
 	for _, test := range tests {
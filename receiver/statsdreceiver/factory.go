@@ -23,6 +23,7 @@ import (
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
 )
 
@@ -30,6 +31,7 @@ const (
 	// The value of "type" key in configuration.
 	typeStr                    = "statsd"
 	stability                  = component.StabilityLevelBeta
+	logsStability              = component.StabilityLevelAlpha
 	defaultBindEndpoint        = "localhost:8125"
 	defaultTransport           = "udp"
 	defaultAggregationInterval = 60 * time.Second
@@ -47,6 +49,7 @@ func NewFactory() component.ReceiverFactory {
 		typeStr,
 		createDefaultConfig,
 		component.WithMetricsReceiver(createMetricsReceiver, stability),
+		component.WithLogsReceiver(createLogsReceiver, logsStability),
 	)
 }
 
@@ -70,10 +73,52 @@ func createMetricsReceiver(
 	cfg config.Receiver,
 	consumer consumer.Metrics,
 ) (component.MetricsReceiver, error) {
+	if consumer == nil {
+		return nil, component.ErrNilNextConsumer
+	}
+	r, err := getReceiver(params, cfg)
+	if err != nil {
+		return nil, err
+	}
+	r.Unwrap().(*statsdReceiver).metricsConsumer = consumer
+	return r, nil
+}
+
+func createLogsReceiver(
+	_ context.Context,
+	params component.ReceiverCreateSettings,
+	cfg config.Receiver,
+	consumer consumer.Logs,
+) (component.LogsReceiver, error) {
+	if consumer == nil {
+		return nil, component.ErrNilNextConsumer
+	}
+	r, err := getReceiver(params, cfg)
+	if err != nil {
+		return nil, err
+	}
+	r.Unwrap().(*statsdReceiver).logsConsumer = consumer
+	return r, nil
+}
+
+func getReceiver(params component.ReceiverCreateSettings, cfg config.Receiver) (*sharedcomponent.SharedComponent, error) {
 	c := cfg.(*Config)
 	err := c.validate()
 	if err != nil {
 		return nil, err
 	}
-	return New(params, *c, consumer)
+	// The receiver only listens once started, so creating it again for the
+	// second pipeline is cheap, and a failed creation is never shared.
+	rcv, err := newReceiver(params, *c)
+	if err != nil {
+		return nil, err
+	}
+	return receivers.GetOrAdd(cfg, func() component.Component {
+		return rcv
+	}), nil
 }
+
+// This is the map of already created StatsD receivers for particular
+// configurations. The metrics and logs pipelines share the receiver, and
+// so its listener, when they are configured with the same receiver.
+var receivers = sharedcomponent.NewSharedComponents()
@@ -43,6 +43,51 @@ func TestCreateReceiver(t *testing.T) {
 	assert.NotNil(t, tReceiver, "receiver creation failed")
 }
 
+func TestCreateLogsReceiver(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr.Endpoint = "localhost:0" // Endpoint is required, not going to be used here.
+
+	params := componenttest.NewNopReceiverCreateSettings()
+	lReceiver, err := createLogsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	assert.NoError(t, err)
+	assert.NotNil(t, lReceiver, "receiver creation failed")
+
+	mReceiver, err := createMetricsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	assert.NoError(t, err)
+	assert.Same(t, lReceiver, mReceiver, "metrics and logs pipelines must share the receiver")
+
+	assert.NoError(t, lReceiver.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, mReceiver.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, lReceiver.Shutdown(context.Background()))
+	assert.NoError(t, mReceiver.Shutdown(context.Background()))
+}
+
+func TestCreateReceiverErrorNotShared(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr.Transport = "unknown"
+
+	params := componenttest.NewNopReceiverCreateSettings()
+	mReceiver, err := createMetricsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	assert.Error(t, err)
+	assert.Nil(t, mReceiver)
+
+	lReceiver, err := createLogsReceiver(context.Background(), params, cfg, consumertest.NewNop())
+	assert.Error(t, err)
+	assert.Nil(t, lReceiver)
+}
+
+func TestCreateLogsReceiverWithNilConsumer(t *testing.T) {
+	receiver, err := createLogsReceiver(
+		context.Background(),
+		componenttest.NewNopReceiverCreateSettings(),
+		createDefaultConfig(),
+		nil,
+	)
+
+	assert.Error(t, err, "nil consumer")
+	assert.Nil(t, receiver)
+}
+
 func TestCreateReceiverWithConfigErr(t *testing.T) {
 	cfg := &Config{
 		AggregationInterval: -1,
@@ -0,0 +1,228 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+const (
+	eventPrefix        = "_e{"
+	serviceCheckPrefix = "_sc|"
+
+	// AttributeDogStatsDType tells events and service checks apart.
+	AttributeDogStatsDType = "dogstatsd.type"
+
+	eventTypeValue        = "event"
+	serviceCheckTypeValue = "service_check"
+)
+
+// Service check statuses, indexed by their wire value.
+var serviceCheckStatuses = []struct {
+	name     string
+	severity plog.SeverityNumber
+}{
+	{"ok", plog.SeverityNumberInfo},
+	{"warning", plog.SeverityNumberWarn},
+	{"critical", plog.SeverityNumberError},
+	{"unknown", plog.SeverityNumberUndefined},
+}
+
+// IsDogStatsDLog reports whether the line is a DogStatsD event or service
+// check rather than a metric.
+func IsDogStatsDLog(line string) bool {
+	return strings.HasPrefix(line, eventPrefix) || strings.HasPrefix(line, serviceCheckPrefix)
+}
+
+// ParseDogStatsDLog translates a DogStatsD event or service check line into
+// a single log record, timeNow is used as the observed timestamp.
+func ParseDogStatsDLog(line string, timeNow time.Time) (plog.Logs, error) {
+	logs := plog.NewLogs()
+	lr := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(timeNow))
+
+	var err error
+	switch {
+	case strings.HasPrefix(line, eventPrefix):
+		err = parseEvent(line, lr)
+	case strings.HasPrefix(line, serviceCheckPrefix):
+		err = parseServiceCheck(line, lr)
+	default:
+		err = fmt.Errorf("not an event or service check: %s", line)
+	}
+	if err != nil {
+		return plog.Logs{}, err
+	}
+	return logs, nil
+}
+
+// parseEvent parses
+// _e{<title length>,<text length>}:<title>|<text>|d:<timestamp>|h:<hostname>|p:<priority>|t:<alert type>|k:<aggregation key>|s:<source type>|#<tags>
+func parseEvent(line string, lr plog.LogRecord) error {
+	header, rest, ok := strings.Cut(line[len(eventPrefix):], "}:")
+	if !ok {
+		return fmt.Errorf("invalid event format: %s", line)
+	}
+	titleLenStr, textLenStr, ok := strings.Cut(header, ",")
+	if !ok {
+		return fmt.Errorf("invalid event lengths: %s", header)
+	}
+	titleLen, err := strconv.Atoi(titleLenStr)
+	if err != nil || titleLen < 0 {
+		return fmt.Errorf("invalid event title length: %s", titleLenStr)
+	}
+	textLen, err := strconv.Atoi(textLenStr)
+	if err != nil || textLen < 0 {
+		return fmt.Errorf("invalid event text length: %s", textLenStr)
+	}
+	if len(rest) < titleLen+1+textLen || rest[titleLen] != '|' {
+		return fmt.Errorf("event title and text do not match their lengths: %s", line)
+	}
+	title := rest[:titleLen]
+	if title == "" {
+		return fmt.Errorf("empty event title: %s", line)
+	}
+	// Newlines are escaped so that the event fits in a single line.
+	text := strings.ReplaceAll(rest[titleLen+1:titleLen+1+textLen], `\n`, "\n")
+	rest = rest[titleLen+1+textLen:]
+
+	priority := "normal"
+	alertType := "info"
+	attrs := lr.Attributes()
+	if rest != "" {
+		if rest[0] != '|' {
+			return fmt.Errorf("event title and text do not match their lengths: %s", line)
+		}
+		for _, part := range strings.Split(rest[1:], "|") {
+			switch {
+			case strings.HasPrefix(part, "d:"):
+				if err := setTimestamp(lr, part[2:]); err != nil {
+					return err
+				}
+			case strings.HasPrefix(part, "h:"):
+				attrs.UpsertString("hostname", part[2:])
+			case strings.HasPrefix(part, "p:"):
+				priority = part[2:]
+			case strings.HasPrefix(part, "t:"):
+				alertType = part[2:]
+			case strings.HasPrefix(part, "k:"):
+				attrs.UpsertString("aggregation_key", part[2:])
+			case strings.HasPrefix(part, "s:"):
+				attrs.UpsertString("source_type_name", part[2:])
+			case strings.HasPrefix(part, "#"):
+				insertTags(attrs, part[1:])
+			default:
+				return fmt.Errorf("unrecognized event part: %s", part)
+			}
+		}
+	}
+
+	switch alertType {
+	case "error":
+		lr.SetSeverityNumber(plog.SeverityNumberError)
+	case "warning":
+		lr.SetSeverityNumber(plog.SeverityNumberWarn)
+	case "info", "success":
+		lr.SetSeverityNumber(plog.SeverityNumberInfo)
+	default:
+		return fmt.Errorf("unsupported event alert type: %s", alertType)
+	}
+	switch priority {
+	case "normal", "low":
+	default:
+		return fmt.Errorf("unsupported event priority: %s", priority)
+	}
+
+	lr.SetSeverityText(alertType)
+	lr.Body().SetStringVal(text)
+	attrs.UpsertString(AttributeDogStatsDType, eventTypeValue)
+	attrs.UpsertString("title", title)
+	attrs.UpsertString("priority", priority)
+	attrs.UpsertString("alert_type", alertType)
+	return nil
+}
+
+// parseServiceCheck parses
+// _sc|<name>|<status>|d:<timestamp>|h:<hostname>|#<tags>|m:<message>
+func parseServiceCheck(line string, lr plog.LogRecord) error {
+	parts := strings.Split(line[len(serviceCheckPrefix):], "|")
+	if len(parts) < 2 {
+		return fmt.Errorf("invalid service check format: %s", line)
+	}
+	name := parts[0]
+	if name == "" {
+		return fmt.Errorf("empty service check name: %s", line)
+	}
+	status, err := strconv.Atoi(parts[1])
+	if err != nil || status < 0 || status >= len(serviceCheckStatuses) {
+		return fmt.Errorf("invalid service check status: %s", parts[1])
+	}
+
+	attrs := lr.Attributes()
+	for i, part := range parts[2:] {
+		if strings.HasPrefix(part, "m:") {
+			// The message comes last and may contain pipes.
+			message := strings.Join(parts[2+i:], "|")[2:]
+			lr.Body().SetStringVal(strings.ReplaceAll(message, `\n`, "\n"))
+			break
+		}
+		switch {
+		case strings.HasPrefix(part, "d:"):
+			if err := setTimestamp(lr, part[2:]); err != nil {
+				return err
+			}
+		case strings.HasPrefix(part, "h:"):
+			attrs.UpsertString("hostname", part[2:])
+		case strings.HasPrefix(part, "#"):
+			insertTags(attrs, part[1:])
+		default:
+			return fmt.Errorf("unrecognized service check part: %s", part)
+		}
+	}
+
+	lr.SetSeverityNumber(serviceCheckStatuses[status].severity)
+	lr.SetSeverityText(serviceCheckStatuses[status].name)
+	attrs.UpsertString(AttributeDogStatsDType, serviceCheckTypeValue)
+	attrs.UpsertString("name", name)
+	attrs.UpsertString("status", serviceCheckStatuses[status].name)
+	return nil
+}
+
+func setTimestamp(lr plog.LogRecord, value string) error {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return fmt.Errorf("parse timestamp: %s", value)
+	}
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(seconds, 0)))
+	return nil
+}
+
+// insertTags adds the comma separated tags as attributes, tags without a
+// value are added with an empty value.
+func insertTags(attrs pcommon.Map, tags string) {
+	for _, tag := range strings.Split(tags, ",") {
+		if tag == "" {
+			continue
+		}
+		k, v, _ := strings.Cut(tag, ":")
+		attrs.UpsertString(k, v)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func TestIsDogStatsDLog(t *testing.T) {
+	assert.True(t, IsDogStatsDLog("_e{5,4}:title|text"))
+	assert.True(t, IsDogStatsDLog("_sc|check|0"))
+	assert.False(t, IsDogStatsDLog("test.metric:42|c"))
+}
+
+func TestParseDogStatsDLog(t *testing.T) {
+	timeNow := time.Unix(711, 0)
+
+	tests := []struct {
+		name          string
+		input         string
+		wantBody      string
+		wantSeverity  plog.SeverityNumber
+		wantText      string
+		wantTimestamp pcommon.Timestamp
+		wantAttrs     map[string]interface{}
+	}{
+		{
+			name:         "minimal event",
+			input:        "_e{5,4}:title|text",
+			wantBody:     "text",
+			wantSeverity: plog.SeverityNumberInfo,
+			wantText:     "info",
+			wantAttrs: map[string]interface{}{
+				"dogstatsd.type": "event",
+				"title":          "title",
+				"priority":       "normal",
+				"alert_type":     "info",
+			},
+		},
+		{
+			name:          "full event",
+			input:         `_e{9,12}:Deploy|ok|line1\nline2|d:1600000000|h:web-1|p:low|t:error|k:deploys|s:ci|#env:prod,canary`,
+			wantBody:      "line1\nline2",
+			wantSeverity:  plog.SeverityNumberError,
+			wantText:      "error",
+			wantTimestamp: pcommon.NewTimestampFromTime(time.Unix(1600000000, 0)),
+			wantAttrs: map[string]interface{}{
+				"dogstatsd.type":   "event",
+				"title":            "Deploy|ok",
+				"priority":         "low",
+				"alert_type":       "error",
+				"hostname":         "web-1",
+				"aggregation_key":  "deploys",
+				"source_type_name": "ci",
+				"env":              "prod",
+				"canary":           "",
+			},
+		},
+		{
+			name:         "service check",
+			input:        "_sc|db.up|1|h:db-1|#env:prod|m:slow queries|see dashboard",
+			wantBody:     "slow queries|see dashboard",
+			wantSeverity: plog.SeverityNumberWarn,
+			wantText:     "warning",
+			wantAttrs: map[string]interface{}{
+				"dogstatsd.type": "service_check",
+				"name":           "db.up",
+				"status":         "warning",
+				"hostname":       "db-1",
+				"env":            "prod",
+			},
+		},
+		{
+			name:          "service check with timestamp",
+			input:         "_sc|db.up|2|d:1600000000",
+			wantSeverity:  plog.SeverityNumberError,
+			wantText:      "critical",
+			wantTimestamp: pcommon.NewTimestampFromTime(time.Unix(1600000000, 0)),
+			wantAttrs: map[string]interface{}{
+				"dogstatsd.type": "service_check",
+				"name":           "db.up",
+				"status":         "critical",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs, err := ParseDogStatsDLog(tt.input, timeNow)
+			require.NoError(t, err)
+			require.Equal(t, 1, logs.LogRecordCount())
+			lr := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+			if tt.wantBody == "" {
+				assert.Equal(t, pcommon.ValueTypeEmpty, lr.Body().Type())
+			} else {
+				assert.Equal(t, tt.wantBody, lr.Body().StringVal())
+			}
+			assert.Equal(t, tt.wantSeverity, lr.SeverityNumber())
+			assert.Equal(t, tt.wantText, lr.SeverityText())
+			assert.Equal(t, tt.wantTimestamp, lr.Timestamp())
+			assert.Equal(t, pcommon.NewTimestampFromTime(timeNow), lr.ObservedTimestamp())
+			assert.Equal(t, tt.wantAttrs, lr.Attributes().AsRaw())
+		})
+	}
+}
+
+func TestParseDogStatsDLog_errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{
+			name:  "not a log",
+			input: "test.metric:42|c",
+			err:   "not an event or service check: test.metric:42|c",
+		},
+		{
+			name:  "missing lengths terminator",
+			input: "_e{5,4|title|text",
+			err:   "invalid event format: _e{5,4|title|text",
+		},
+		{
+			name:  "invalid title length",
+			input: "_e{a,4}:title|text",
+			err:   "invalid event title length: a",
+		},
+		{
+			name:  "lengths do not match",
+			input: "_e{4,4}:title|text",
+			err:   "event title and text do not match their lengths: _e{4,4}:title|text",
+		},
+		{
+			name:  "unsupported alert type",
+			input: "_e{5,4}:title|text|t:fatal",
+			err:   "unsupported event alert type: fatal",
+		},
+		{
+			name:  "unsupported priority",
+			input: "_e{5,4}:title|text|p:high",
+			err:   "unsupported event priority: high",
+		},
+		{
+			name:  "invalid event timestamp",
+			input: "_e{5,4}:title|text|d:yesterday",
+			err:   "parse timestamp: yesterday",
+		},
+		{
+			name:  "missing service check status",
+			input: "_sc|db.up",
+			err:   "invalid service check format: _sc|db.up",
+		},
+		{
+			name:  "invalid service check status",
+			input: "_sc|db.up|4",
+			err:   "invalid service check status: 4",
+		},
+		{
+			name:  "unrecognized service check part",
+			input: "_sc|db.up|0|x:y",
+			err:   "unrecognized service check part: x:y",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseDogStatsDLog(tt.input, time.Now())
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
@@ -15,6 +15,7 @@
 package protocol // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
 
 import (
+	"math"
 	"sort"
 	"time"
 
@@ -27,6 +28,14 @@ var (
 	statsDDefaultPercentiles = []float64{0, 10, 50, 90, 95, 100}
 )
 
+const (
+	// Bounds of the scale of the exponential histograms: the highest scale
+	// has a relative error of ~6.6e-7 per bucket, the lowest covers the full
+	// float64 range in a handful of buckets.
+	maxExponentialHistogramScale = 20
+	minExponentialHistogramScale = -10
+)
+
 func buildCounterMetric(parsedMetric statsDMetric, isMonotonicCounter bool, timeNow, lastIntervalTime time.Time) pmetric.ScopeMetrics {
 	ilm := pmetric.NewScopeMetrics()
 	nm := ilm.Metrics().AppendEmpty()
@@ -98,6 +107,156 @@ func buildSummaryMetric(desc statsDMetricDescription, summary summaryMetric, sta
 	}
 }
 
+func buildHistogramMetric(desc statsDMetricDescription, histogram histogramMetric, startTime, timeNow time.Time, ilm pmetric.ScopeMetrics) {
+	nm := ilm.Metrics().AppendEmpty()
+	nm.SetName(desc.name)
+
+	count := uint64(0)
+	sum := float64(0)
+	min, max := math.Inf(1), math.Inf(-1)
+	for i, point := range histogram.points {
+		// Note: counts are rounded here, see note in counterValue().
+		count += uint64(math.Round(histogram.weights[i]))
+		sum += point * histogram.weights[i]
+		min = math.Min(min, point)
+		max = math.Max(max, point)
+	}
+
+	if len(histogram.config.ExplicitBuckets) > 0 {
+		nm.SetEmptyHistogram().SetAggregationTemporality(pmetric.MetricAggregationTemporalityDelta)
+		dp := nm.Histogram().DataPoints().AppendEmpty()
+		bucketCounts := make([]uint64, len(histogram.config.ExplicitBuckets)+1)
+		for i, point := range histogram.points {
+			// Buckets are upper bound inclusive.
+			bucketCounts[sort.SearchFloat64s(histogram.config.ExplicitBuckets, point)] += uint64(math.Round(histogram.weights[i]))
+		}
+		dp.SetExplicitBounds(pcommon.NewImmutableFloat64Slice(histogram.config.ExplicitBuckets))
+		dp.SetBucketCounts(pcommon.NewImmutableUInt64Slice(bucketCounts))
+		dp.SetCount(count)
+		dp.SetSum(sum)
+		dp.SetMin(min)
+		dp.SetMax(max)
+		dp.SetStartTimestamp(pcommon.NewTimestampFromTime(startTime))
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(timeNow))
+		for i := desc.attrs.Iter(); i.Next(); {
+			dp.Attributes().UpsertString(string(i.Attribute().Key), i.Attribute().Value.AsString())
+		}
+		return
+	}
+
+	nm.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.MetricAggregationTemporalityDelta)
+	dp := nm.ExponentialHistogram().DataPoints().AppendEmpty()
+	maxSize := histogram.config.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultHistogramMaxSize
+	}
+	buildExponentialBuckets(dp, histogram.points, histogram.weights, maxSize)
+	dp.SetCount(count)
+	dp.SetSum(sum)
+	dp.SetMin(min)
+	dp.SetMax(max)
+	dp.SetStartTimestamp(pcommon.NewTimestampFromTime(startTime))
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(timeNow))
+	for i := desc.attrs.Iter(); i.Next(); {
+		dp.Attributes().UpsertString(string(i.Attribute().Key), i.Attribute().Value.AsString())
+	}
+}
+
+// buildExponentialBuckets sets the scale, zero count and buckets of dp using
+// the highest scale at which neither the positive nor the negative range
+// needs more than maxSize buckets.
+func buildExponentialBuckets(dp pmetric.ExponentialHistogramDataPoint, points, weights []float64, maxSize int32) {
+	var positive, negative exponentialBuckets
+	zeroCount := uint64(0)
+	for i, point := range points {
+		weight := uint64(math.Round(weights[i]))
+		switch {
+		case point > 0:
+			positive.add(exponentialIndex(point), weight)
+		case point < 0:
+			negative.add(exponentialIndex(-point), weight)
+		default:
+			zeroCount += weight
+		}
+	}
+
+	// Downscaling by one merges pairs of neighbouring buckets, which halves
+	// the indexes computed at the highest scale.
+	shift := 0
+	for maxExponentialHistogramScale-shift > minExponentialHistogramScale &&
+		(positive.size(shift) > int64(maxSize) || negative.size(shift) > int64(maxSize)) {
+		shift++
+	}
+
+	dp.SetScale(int32(maxExponentialHistogramScale - shift))
+	dp.SetZeroCount(zeroCount)
+	positive.copyTo(dp.Positive(), shift)
+	negative.copyTo(dp.Negative(), shift)
+}
+
+// exponentialIndex returns the index of the bucket holding the positive
+// value at the highest scale, buckets are upper bound inclusive.
+func exponentialIndex(value float64) int64 {
+	// math.Log2 is exact for powers of two, so are the boundaries.
+	return int64(math.Ceil(math.Ldexp(math.Log2(value), maxExponentialHistogramScale))) - 1
+}
+
+type exponentialBuckets struct {
+	indexes []int64
+	counts  []uint64
+}
+
+func (b *exponentialBuckets) add(index int64, count uint64) {
+	b.indexes = append(b.indexes, index)
+	b.counts = append(b.counts, count)
+}
+
+func (b *exponentialBuckets) bounds(shift int) (int64, int64) {
+	low, high := int64(math.MaxInt64), int64(math.MinInt64)
+	for _, index := range b.indexes {
+		index >>= shift
+		if index < low {
+			low = index
+		}
+		if index > high {
+			high = index
+		}
+	}
+	return low, high
+}
+
+func (b *exponentialBuckets) size(shift int) int64 {
+	if len(b.indexes) == 0 {
+		return 0
+	}
+	low, high := b.bounds(shift)
+	return high - low + 1
+}
+
+func (b *exponentialBuckets) copyTo(dest pmetric.Buckets, shift int) {
+	if len(b.indexes) == 0 {
+		return
+	}
+	low, _ := b.bounds(shift)
+	counts := make([]uint64, b.size(shift))
+	for i, index := range b.indexes {
+		counts[(index>>shift)-low] += b.counts[i]
+	}
+	dest.SetOffset(int32(low))
+	dest.SetBucketCounts(pcommon.NewImmutableUInt64Slice(counts))
+}
+
+func buildSetMetric(desc statsDMetricDescription, values map[string]struct{}, timeNow time.Time, ilm pmetric.ScopeMetrics) {
+	nm := ilm.Metrics().AppendEmpty()
+	nm.SetName(desc.name)
+	dp := nm.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetIntVal(int64(len(values)))
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(timeNow))
+	for i := desc.attrs.Iter(); i.Next(); {
+		dp.Attributes().UpsertString(string(i.Attribute().Key), i.Attribute().Value.AsString())
+	}
+}
+
 func (s statsDMetric) counterValue() int64 {
 	x := s.asFloat
 	// Note statds counters are always represented as integers.
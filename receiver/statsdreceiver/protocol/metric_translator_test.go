@@ -204,3 +204,119 @@ func TestBuildSummaryMetricSampled(t *testing.T) {
 		assert.Equal(t, expectedMetric, metric)
 	}
 }
+
+func TestBuildExplicitHistogramMetric(t *testing.T) {
+	timeNow := time.Now()
+
+	histogram := histogramMetric{
+		points:  []float64{1, 10, 50, 500},
+		weights: []float64{1, 2, 1, 1},
+		config:  HistogramConfig{ExplicitBuckets: []float64{10, 100}},
+	}
+
+	desc := statsDMetricDescription{
+		name:       "testHistogram",
+		metricType: TimingType,
+		attrs:      attribute.NewSet(attribute.String("mykey", "myvalue")),
+	}
+
+	metric := pmetric.NewScopeMetrics()
+	buildHistogramMetric(desc, histogram, timeNow.Add(-time.Minute), timeNow, metric)
+
+	expectedMetric := pmetric.NewScopeMetrics()
+	m := expectedMetric.Metrics().AppendEmpty()
+	m.SetName("testHistogram")
+	m.SetEmptyHistogram().SetAggregationTemporality(pmetric.MetricAggregationTemporalityDelta)
+	dp := m.Histogram().DataPoints().AppendEmpty()
+	dp.SetExplicitBounds(pcommon.NewImmutableFloat64Slice([]float64{10, 100}))
+	dp.SetBucketCounts(pcommon.NewImmutableUInt64Slice([]uint64{3, 1, 1}))
+	dp.SetCount(5)
+	dp.SetSum(571)
+	dp.SetMin(1)
+	dp.SetMax(500)
+	dp.SetStartTimestamp(pcommon.NewTimestampFromTime(timeNow.Add(-time.Minute)))
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(timeNow))
+	dp.Attributes().UpsertString("mykey", "myvalue")
+
+	assert.Equal(t, expectedMetric, metric)
+}
+
+func TestBuildExponentialHistogramMetric(t *testing.T) {
+	timeNow := time.Now()
+
+	histogram := histogramMetric{
+		points:  []float64{1, 2, 4, 0, -3},
+		weights: []float64{1, 1, 1, 1, 1},
+		config:  HistogramConfig{MaxSize: 4},
+	}
+
+	desc := statsDMetricDescription{
+		name:       "testHistogram",
+		metricType: DistributionType,
+	}
+
+	metric := pmetric.NewScopeMetrics()
+	buildHistogramMetric(desc, histogram, timeNow.Add(-time.Minute), timeNow, metric)
+
+	expectedMetric := pmetric.NewScopeMetrics()
+	m := expectedMetric.Metrics().AppendEmpty()
+	m.SetName("testHistogram")
+	m.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.MetricAggregationTemporalityDelta)
+	dp := m.ExponentialHistogram().DataPoints().AppendEmpty()
+	// At scale 0 the buckets are (0.5, 1], (1, 2], (2, 4] and so on.
+	dp.SetScale(0)
+	dp.SetZeroCount(1)
+	dp.Positive().SetOffset(-1)
+	dp.Positive().SetBucketCounts(pcommon.NewImmutableUInt64Slice([]uint64{1, 1, 1}))
+	dp.Negative().SetOffset(1)
+	dp.Negative().SetBucketCounts(pcommon.NewImmutableUInt64Slice([]uint64{1}))
+	dp.SetCount(5)
+	dp.SetSum(4)
+	dp.SetMin(-3)
+	dp.SetMax(4)
+	dp.SetStartTimestamp(pcommon.NewTimestampFromTime(timeNow.Add(-time.Minute)))
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(timeNow))
+
+	assert.Equal(t, expectedMetric, metric)
+}
+
+func TestBuildExponentialHistogramMetricDefaultMaxSize(t *testing.T) {
+	histogram := histogramMetric{
+		points:  []float64{1, 2, 4},
+		weights: []float64{1, 1, 1},
+	}
+
+	metric := pmetric.NewScopeMetrics()
+	buildHistogramMetric(statsDMetricDescription{name: "testHistogram"}, histogram, time.Now(), time.Now(), metric)
+
+	dp := metric.Metrics().At(0).ExponentialHistogram().DataPoints().At(0)
+	assert.Equal(t, int32(6), dp.Scale())
+	assert.Equal(t, int32(-1), dp.Positive().Offset())
+	counts := dp.Positive().BucketCounts().AsRaw()
+	assert.Len(t, counts, 129)
+	assert.Equal(t, uint64(1), counts[0])
+	assert.Equal(t, uint64(1), counts[64])
+	assert.Equal(t, uint64(1), counts[128])
+}
+
+func TestBuildSetMetric(t *testing.T) {
+	timeNow := time.Now()
+	desc := statsDMetricDescription{
+		name:       "testSet",
+		metricType: SetType,
+		attrs:      attribute.NewSet(attribute.String("mykey", "myvalue")),
+	}
+
+	metric := pmetric.NewScopeMetrics()
+	buildSetMetric(desc, map[string]struct{}{"a": {}, "b": {}}, timeNow, metric)
+
+	expectedMetric := pmetric.NewScopeMetrics()
+	m := expectedMetric.Metrics().AppendEmpty()
+	m.SetName("testSet")
+	dp := m.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetIntVal(2)
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(timeNow))
+	dp.Attributes().UpsertString("mykey", "myvalue")
+
+	assert.Equal(t, expectedMetric, metric)
+}
@@ -17,6 +17,7 @@ package protocol // import "github.com/open-telemetry/opentelemetry-collector-co
 import (
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -39,27 +40,49 @@ type (
 const (
 	tagMetricType = "metric_type"
 
-	CounterType   MetricType = "c"
-	GaugeType     MetricType = "g"
-	HistogramType MetricType = "h"
-	TimingType    MetricType = "ms"
-
-	CounterTypeName   TypeName = "counter"
-	GaugeTypeName     TypeName = "gauge"
-	HistogramTypeName TypeName = "histogram"
-	TimingTypeName    TypeName = "timing"
-	TimingAltTypeName TypeName = "timer"
-
-	GaugeObserver   ObserverType = "gauge"
-	SummaryObserver ObserverType = "summary"
-	DisableObserver ObserverType = "disabled"
+	CounterType      MetricType = "c"
+	GaugeType        MetricType = "g"
+	HistogramType    MetricType = "h"
+	TimingType       MetricType = "ms"
+	SetType          MetricType = "s"
+	DistributionType MetricType = "d"
+
+	CounterTypeName      TypeName = "counter"
+	GaugeTypeName        TypeName = "gauge"
+	HistogramTypeName    TypeName = "histogram"
+	TimingTypeName       TypeName = "timing"
+	TimingAltTypeName    TypeName = "timer"
+	SetTypeName          TypeName = "set"
+	DistributionTypeName TypeName = "distribution"
+
+	GaugeObserver     ObserverType = "gauge"
+	SummaryObserver   ObserverType = "summary"
+	HistogramObserver ObserverType = "histogram"
+	DisableObserver   ObserverType = "disabled"
 
 	DefaultObserverType = DisableObserver
+
+	// DefaultHistogramMaxSize is the default maximum number of buckets per
+	// range (positive and negative) of an exponential histogram.
+	DefaultHistogramMaxSize int32 = 160
 )
 
 type TimerHistogramMapping struct {
-	StatsdType   TypeName     `mapstructure:"statsd_type"`
-	ObserverType ObserverType `mapstructure:"observer_type"`
+	StatsdType   TypeName        `mapstructure:"statsd_type"`
+	ObserverType ObserverType    `mapstructure:"observer_type"`
+	Histogram    HistogramConfig `mapstructure:"histogram"`
+}
+
+// HistogramConfig configures the histogram observer. When ExplicitBuckets is
+// set explicit-bucket histograms are produced, exponential histograms are
+// produced otherwise.
+type HistogramConfig struct {
+	// MaxSize is the maximum number of buckets per range of an exponential
+	// histogram, DefaultHistogramMaxSize is used when zero.
+	MaxSize int32 `mapstructure:"max_size"`
+	// ExplicitBuckets are the ascending upper bounds of the buckets of an
+	// explicit-bucket histogram.
+	ExplicitBuckets []float64 `mapstructure:"explicit_buckets"`
 }
 
 // StatsDParser supports the Parse method for parsing StatsD messages with Tags.
@@ -67,11 +90,15 @@ type StatsDParser struct {
 	gauges                 map[statsDMetricDescription]pmetric.ScopeMetrics
 	counters               map[statsDMetricDescription]pmetric.ScopeMetrics
 	summaries              map[statsDMetricDescription]summaryMetric
+	histograms             map[statsDMetricDescription]histogramMetric
+	sets                   map[statsDMetricDescription]map[string]struct{}
 	timersAndDistributions []pmetric.ScopeMetrics
 	enableMetricType       bool
 	isMonotonicCounter     bool
 	observeTimer           ObserverType
 	observeHistogram       ObserverType
+	observeDistribution    ObserverType
+	histogramConfigs       map[MetricType]HistogramConfig
 	lastIntervalTime       time.Time
 }
 
@@ -85,12 +112,21 @@ type summaryMetric struct {
 	weights []float64
 }
 
+type histogramMetric struct {
+	points  []float64
+	weights []float64
+	config  HistogramConfig
+}
+
 type statsDMetric struct {
 	description statsDMetricDescription
 	asFloat     float64
-	addition    bool
-	unit        string
-	sampleRate  float64
+	// setValue holds the raw value of set metrics, which are not required
+	// to be numeric.
+	setValue   string
+	addition   bool
+	unit       string
+	sampleRate float64
 }
 
 type statsDMetricDescription struct {
@@ -109,6 +145,10 @@ func (t MetricType) FullName() TypeName {
 		return TimingTypeName
 	case HistogramType:
 		return HistogramTypeName
+	case SetType:
+		return SetTypeName
+	case DistributionType:
+		return DistributionTypeName
 	}
 	return TypeName(fmt.Sprintf("unknown(%s)", t))
 }
@@ -118,9 +158,13 @@ func (p *StatsDParser) Initialize(enableMetricType bool, isMonotonicCounter bool
 	p.gauges = make(map[statsDMetricDescription]pmetric.ScopeMetrics)
 	p.counters = make(map[statsDMetricDescription]pmetric.ScopeMetrics)
 	p.summaries = make(map[statsDMetricDescription]summaryMetric)
+	p.histograms = make(map[statsDMetricDescription]histogramMetric)
+	p.sets = make(map[statsDMetricDescription]map[string]struct{})
+	p.histogramConfigs = make(map[MetricType]HistogramConfig)
 
 	p.observeHistogram = DefaultObserverType
 	p.observeTimer = DefaultObserverType
+	p.observeDistribution = DefaultObserverType
 	p.enableMetricType = enableMetricType
 	p.isMonotonicCounter = isMonotonicCounter
 	// Note: validation occurs in ("../".Config).vaidate()
@@ -128,8 +172,13 @@ func (p *StatsDParser) Initialize(enableMetricType bool, isMonotonicCounter bool
 		switch eachMap.StatsdType {
 		case HistogramTypeName:
 			p.observeHistogram = eachMap.ObserverType
+			p.histogramConfigs[HistogramType] = eachMap.Histogram
 		case TimingTypeName, TimingAltTypeName:
 			p.observeTimer = eachMap.ObserverType
+			p.histogramConfigs[TimingType] = eachMap.Histogram
+		case DistributionTypeName:
+			p.observeDistribution = eachMap.ObserverType
+			p.histogramConfigs[DistributionType] = eachMap.Histogram
 		}
 	}
 	return nil
@@ -163,10 +212,31 @@ func (p *StatsDParser) GetMetrics() pmetric.Metrics {
 		)
 	}
 
+	for desc, histogramMetric := range p.histograms {
+		buildHistogramMetric(
+			desc,
+			histogramMetric,
+			p.lastIntervalTime,
+			timeNowFunc(),
+			rm.ScopeMetrics().AppendEmpty(),
+		)
+	}
+
+	for desc, values := range p.sets {
+		buildSetMetric(
+			desc,
+			values,
+			timeNowFunc(),
+			rm.ScopeMetrics().AppendEmpty(),
+		)
+	}
+
 	p.gauges = make(map[statsDMetricDescription]pmetric.ScopeMetrics)
 	p.counters = make(map[statsDMetricDescription]pmetric.ScopeMetrics)
 	p.timersAndDistributions = nil
 	p.summaries = make(map[statsDMetricDescription]summaryMetric)
+	p.histograms = make(map[statsDMetricDescription]histogramMetric)
+	p.sets = make(map[statsDMetricDescription]map[string]struct{})
 	return metrics
 }
 
@@ -178,6 +248,8 @@ func (p *StatsDParser) observerTypeFor(t MetricType) ObserverType {
 		return p.observeHistogram
 	case TimingType:
 		return p.observeTimer
+	case DistributionType:
+		return p.observeDistribution
 	}
 	return DisableObserver
 }
@@ -213,7 +285,15 @@ func (p *StatsDParser) Aggregate(line string) error {
 			point.SetIntVal(point.IntVal() + parsedMetric.counterValue())
 		}
 
-	case TimingType, HistogramType:
+	case SetType:
+		values, ok := p.sets[parsedMetric.description]
+		if !ok {
+			values = make(map[string]struct{})
+			p.sets[parsedMetric.description] = values
+		}
+		values[parsedMetric.setValue] = struct{}{}
+
+	case TimingType, HistogramType, DistributionType:
 		switch p.observerTypeFor(parsedMetric.description.metricType) {
 		case GaugeObserver:
 			p.timersAndDistributions = append(p.timersAndDistributions, buildGaugeMetric(parsedMetric, timeNowFunc()))
@@ -230,6 +310,15 @@ func (p *StatsDParser) Aggregate(line string) error {
 					weights: append(existing.weights, raw.count),
 				}
 			}
+		case HistogramObserver:
+			raw := parsedMetric.summaryValue()
+			existing, ok := p.histograms[parsedMetric.description]
+			if !ok {
+				existing.config = p.histogramConfigs[parsedMetric.description.metricType]
+			}
+			existing.points = append(existing.points, raw.value)
+			existing.weights = append(existing.weights, raw.count)
+			p.histograms[parsedMetric.description] = existing
 		case DisableObserver:
 			// No action.
 		}
@@ -265,7 +354,7 @@ func parseMessageToMetric(line string, enableMetricType bool) (statsDMetric, err
 
 	inType := MetricType(parts[1])
 	switch inType {
-	case CounterType, GaugeType, HistogramType, TimingType:
+	case CounterType, GaugeType, HistogramType, TimingType, SetType, DistributionType:
 		result.description.metricType = inType
 	default:
 		return result, fmt.Errorf("unsupported metric type: %s", inType)
@@ -304,10 +393,20 @@ func parseMessageToMetric(line string, enableMetricType bool) (statsDMetric, err
 			return result, fmt.Errorf("unrecognized message part: %s", part)
 		}
 	}
-	var err error
-	result.asFloat, err = strconv.ParseFloat(valueStr, 64)
-	if err != nil {
-		return result, fmt.Errorf("parse metric value string: %s", valueStr)
+	if result.description.metricType == SetType {
+		// Set members are arbitrary strings, e.g. user ids.
+		result.setValue = valueStr
+		result.addition = false
+	} else {
+		var err error
+		result.asFloat, err = strconv.ParseFloat(valueStr, 64)
+		if err != nil {
+			return result, fmt.Errorf("parse metric value string: %s", valueStr)
+		}
+		// ParseFloat accepts Inf and NaN, which can't be aggregated.
+		if math.IsInf(result.asFloat, 0) || math.IsNaN(result.asFloat) {
+			return result, fmt.Errorf("non-finite metric value: %s", valueStr)
+		}
 	}
 
 	// add metric_type dimension for all metrics
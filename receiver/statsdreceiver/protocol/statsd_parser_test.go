@@ -210,6 +210,21 @@ func Test_ParseMessageToMetric(t *testing.T) {
 			input: "test.metric:42.abc|h",
 			err:   errors.New("parse metric value string: 42.abc"),
 		},
+		{
+			name:  "infinite distribution metric value",
+			input: "test.metric:Inf|d",
+			err:   errors.New("non-finite metric value: Inf"),
+		},
+		{
+			name:  "negative infinite timer metric value",
+			input: "test.metric:-Inf|ms",
+			err:   errors.New("non-finite metric value: -Inf"),
+		},
+		{
+			name:  "NaN gauge metric value",
+			input: "test.metric:NaN|g",
+			err:   errors.New("non-finite metric value: NaN"),
+		},
 		{
 			name:  "int timer",
 			input: "test.metric:-42|ms",
@@ -887,6 +902,75 @@ func TestStatsDParser_AggregateTimerWithSummary(t *testing.T) {
 	}
 }
 
+func TestStatsDParser_AggregateSet(t *testing.T) {
+	p := &StatsDParser{}
+	assert.NoError(t, p.Initialize(false, false, nil))
+	for _, line := range []string{
+		"users:alice|s|#mykey:myvalue",
+		"users:bob|s|#mykey:myvalue",
+		"users:alice|s|#mykey:myvalue",
+		"users:-1|s|#mykey:myvalue",
+		"users:carol|s|#mykey:othervalue",
+	} {
+		assert.NoError(t, p.Aggregate(line))
+	}
+
+	assert.Equal(t, map[statsDMetricDescription]map[string]struct{}{
+		testDescription("users", "s", []string{"mykey"}, []string{"myvalue"}): {
+			"alice": {},
+			"bob":   {},
+			"-1":    {},
+		},
+		testDescription("users", "s", []string{"mykey"}, []string{"othervalue"}): {
+			"carol": {},
+		},
+	}, p.sets)
+
+	metrics := p.GetMetrics()
+	assert.Equal(t, 2, metrics.ResourceMetrics().At(0).ScopeMetrics().Len())
+	assert.Empty(t, p.sets)
+}
+
+func TestStatsDParser_AggregateWithHistogram(t *testing.T) {
+	p := &StatsDParser{}
+	assert.NoError(t, p.Initialize(false, false, []TimerHistogramMapping{
+		{StatsdType: "timer", ObserverType: "histogram", Histogram: HistogramConfig{ExplicitBuckets: []float64{10, 100}}},
+		{StatsdType: "distribution", ObserverType: "histogram"},
+	}))
+	for _, line := range []string{
+		"statsdTestMetric1:1|ms|#mykey:myvalue",
+		"statsdTestMetric1:50|ms|@0.5|#mykey:myvalue",
+		"statsdTestMetric2:7|d",
+		"statsdTestMetric2:3|d",
+	} {
+		assert.NoError(t, p.Aggregate(line))
+	}
+
+	assert.Equal(t, map[statsDMetricDescription]histogramMetric{
+		testDescription("statsdTestMetric1", "ms", []string{"mykey"}, []string{"myvalue"}): {
+			points:  []float64{1, 50},
+			weights: []float64{1, 2},
+			config:  HistogramConfig{ExplicitBuckets: []float64{10, 100}},
+		},
+		{name: "statsdTestMetric2", metricType: "d"}: {
+			points:  []float64{7, 3},
+			weights: []float64{1, 1},
+		},
+	}, p.histograms)
+}
+
+func TestStatsDParser_AggregateNonFiniteHistogram(t *testing.T) {
+	p := &StatsDParser{}
+	assert.NoError(t, p.Initialize(false, false, []TimerHistogramMapping{
+		{StatsdType: "distribution", ObserverType: "histogram"},
+	}))
+	assert.NoError(t, p.Aggregate("foo:1|d"))
+	assert.Error(t, p.Aggregate("foo:Inf|d"))
+	assert.Error(t, p.Aggregate("foo:NaN|d"))
+
+	assert.Equal(t, 1, p.GetMetrics().MetricCount())
+}
+
 func TestStatsDParser_Initialize(t *testing.T) {
 	p := &StatsDParser{}
 	assert.NoError(t, p.Initialize(true, false, []TimerHistogramMapping{{StatsdType: "timer", ObserverType: "gauge"}, {StatsdType: "histogram", ObserverType: "gauge"}}))
@@ -971,6 +1055,17 @@ func TestStatsDParser_Mappings(t *testing.T) {
 				"Gauge": "T",
 			},
 		},
+		{
+			name: "histo-to-exponential-distribution-to-explicit",
+			mapping: []TimerHistogramMapping{
+				{StatsdType: "histogram", ObserverType: "histogram"},
+				{StatsdType: "distribution", ObserverType: "histogram", Histogram: HistogramConfig{ExplicitBuckets: []float64{1, 10}}},
+			},
+			expect: map[string]string{
+				"ExponentialHistogram": "H",
+				"Histogram":            "D",
+			},
+		},
 	} {
 		t.Run(tc.name, func(t *testing.T) {
 			p := &StatsDParser{}
@@ -979,6 +1074,7 @@ func TestStatsDParser_Mappings(t *testing.T) {
 
 			assert.NoError(t, p.Aggregate("H:10|h"))
 			assert.NoError(t, p.Aggregate("T:10|ms"))
+			assert.NoError(t, p.Aggregate("D:10|d"))
 
 			typeNames := map[string]string{}
 
@@ -31,17 +31,20 @@ import (
 )
 
 var _ component.MetricsReceiver = (*statsdReceiver)(nil)
+var _ component.LogsReceiver = (*statsdReceiver)(nil)
 
-// statsdReceiver implements the component.MetricsReceiver for StatsD protocol.
+// statsdReceiver implements the component.MetricsReceiver for StatsD protocol,
+// and the component.LogsReceiver for DogStatsD events and service checks.
 type statsdReceiver struct {
 	settings component.ReceiverCreateSettings
 	config   *Config
 
-	server       transport.Server
-	reporter     transport.Reporter
-	parser       protocol.Parser
-	nextConsumer consumer.Metrics
-	cancel       context.CancelFunc
+	server          transport.Server
+	reporter        transport.Reporter
+	parser          protocol.Parser
+	metricsConsumer consumer.Metrics
+	logsConsumer    consumer.Logs
+	cancel          context.CancelFunc
 }
 
 // New creates the StatsD receiver with the given parameters.
@@ -54,47 +57,70 @@ func New(
 		return nil, component.ErrNilNextConsumer
 	}
 
+	r, err := newReceiver(set, config)
+	if err != nil {
+		return nil, err
+	}
+	r.metricsConsumer = nextConsumer
+	return r, nil
+}
+
+// newReceiver creates a StatsD receiver without consumers, they are set by
+// the factory as the metrics and logs pipelines share the receiver.
+func newReceiver(set component.ReceiverCreateSettings, config Config) (*statsdReceiver, error) {
 	if config.NetAddr.Endpoint == "" {
 		config.NetAddr.Endpoint = "localhost:8125"
 	}
 
-	server, err := buildTransportServer(config)
-	if err != nil {
-		return nil, err
+	if _, ok := transportServers[strings.ToLower(config.NetAddr.Transport)]; !ok {
+		return nil, fmt.Errorf("unsupported transport %q for receiver %v", config.NetAddr.Transport, config.ID())
 	}
 
 	r := &statsdReceiver{
-		settings:     set,
-		config:       &config,
-		nextConsumer: nextConsumer,
-		server:       server,
-		reporter:     newReporter(config.ID(), set),
-		parser:       &protocol.StatsDParser{},
+		settings: set,
+		config:   &config,
+		reporter: newReporter(config.ID(), set),
+		parser:   &protocol.StatsDParser{},
 	}
 	return r, nil
 }
 
-func buildTransportServer(config Config) (transport.Server, error) {
-	// TODO: Add TCP/unix socket transport implementations
-	switch strings.ToLower(config.NetAddr.Transport) {
-	case "", "udp":
+// transportServers builds the server of each supported transport, the
+// server listens as soon as it is built.
+var transportServers = map[string]func(Config) (transport.Server, error){
+	"": func(config Config) (transport.Server, error) {
 		return transport.NewUDPServer(config.NetAddr.Endpoint)
-	}
-
-	return nil, fmt.Errorf("unsupported transport %q for receiver %v", config.NetAddr.Transport, config.ID())
+	},
+	"udp": func(config Config) (transport.Server, error) {
+		return transport.NewUDPServer(config.NetAddr.Endpoint)
+	},
+	"tcp": func(config Config) (transport.Server, error) {
+		return transport.NewTCPServer(config.NetAddr.Endpoint, config.MaxConnections)
+	},
+	"unixgram": func(config Config) (transport.Server, error) {
+		return transport.NewUnixgramServer(config.NetAddr.Endpoint)
+	},
+	"unix": func(config Config) (transport.Server, error) {
+		return transport.NewUnixServer(config.NetAddr.Endpoint, config.MaxConnections)
+	},
 }
 
-// Start starts a UDP server that can process StatsD messages.
+// Start starts a server that can process StatsD messages.
 func (r *statsdReceiver) Start(ctx context.Context, host component.Host) error {
-	ctx, r.cancel = context.WithCancel(ctx)
-	var transferChan = make(chan string, 10)
-	ticker := time.NewTicker(r.config.AggregationInterval)
 	err := r.parser.Initialize(r.config.EnableMetricType, r.config.IsMonotonicCounter, r.config.TimerHistogramMapping)
 	if err != nil {
 		return err
 	}
+	r.server, err = transportServers[strings.ToLower(r.config.NetAddr.Transport)](*r.config)
+	if err != nil {
+		return err
+	}
+
+	ctx, r.cancel = context.WithCancel(ctx)
+	var transferChan = make(chan string, 10)
+	ticker := time.NewTicker(r.config.AggregationInterval)
 	go func() {
-		if err := r.server.ListenAndServe(r.parser, r.nextConsumer, r.reporter, transferChan); err != nil {
+		if err := r.server.ListenAndServe(r.parser, r.metricsConsumer, r.reporter, transferChan); err != nil {
 			if !errors.Is(err, net.ErrClosed) {
 				host.ReportFatalError(err)
 			}
@@ -104,12 +130,15 @@ func (r *statsdReceiver) Start(ctx context.Context, host component.Host) error {
 		for {
 			select {
 			case <-ticker.C:
+				if r.metricsConsumer == nil {
+					continue
+				}
 				metrics := r.parser.GetMetrics()
 				if metrics.ResourceMetrics().At(0).ScopeMetrics().Len() > 0 {
-					r.Flush(ctx, metrics, r.nextConsumer)
+					r.Flush(ctx, metrics, r.metricsConsumer)
 				}
-			case rawMetric := <-transferChan:
-				_ = r.parser.Aggregate(rawMetric)
+			case line := <-transferChan:
+				r.handleLine(ctx, line)
 			case <-ctx.Done():
 				ticker.Stop()
 				return
@@ -120,8 +149,38 @@ func (r *statsdReceiver) Start(ctx context.Context, host component.Host) error {
 	return nil
 }
 
+// handleLine sends DogStatsD events and service checks to the logs pipeline
+// and aggregates any other line as a metric, lines without a matching
+// pipeline are dropped.
+func (r *statsdReceiver) handleLine(ctx context.Context, line string) {
+	if protocol.IsDogStatsDLog(line) {
+		if r.logsConsumer == nil {
+			return
+		}
+		logs, err := protocol.ParseDogStatsDLog(line, time.Now())
+		if err != nil {
+			r.reporter.OnDebugf("StatsD parse error: %v", err)
+			return
+		}
+		if err = r.logsConsumer.ConsumeLogs(ctx, logs); err != nil {
+			r.reporter.OnDebugf("StatsD failed to send logs: %v", err)
+		}
+		return
+	}
+
+	if r.metricsConsumer == nil {
+		return
+	}
+	if err := r.parser.Aggregate(line); err != nil {
+		r.reporter.OnDebugf("StatsD parse error: %v", err)
+	}
+}
+
 // Shutdown stops the StatsD receiver.
 func (r *statsdReceiver) Shutdown(context.Context) error {
+	if r.server == nil {
+		return nil
+	}
 	err := r.server.Close()
 	r.cancel()
 	return err
@@ -91,6 +91,62 @@ func TestStatsdReceiver_Flush(t *testing.T) {
 	assert.NoError(t, r.Shutdown(ctx))
 }
 
+func TestStatsdReceiver_handleLine(t *testing.T) {
+	tests := []struct {
+		name        string
+		withMetrics bool
+		withLogs    bool
+		wantMetrics int
+		wantLogs    int
+	}{
+		{
+			name:        "metrics and logs",
+			withMetrics: true,
+			withLogs:    true,
+			wantMetrics: 1,
+			wantLogs:    2,
+		},
+		{
+			name:        "metrics only",
+			withMetrics: true,
+			wantMetrics: 1,
+		},
+		{
+			name:     "logs only",
+			withLogs: true,
+			wantLogs: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := createDefaultConfig().(*Config)
+			cfg.NetAddr.Endpoint = "localhost:0"
+			r, err := newReceiver(componenttest.NewNopReceiverCreateSettings(), *cfg)
+			require.NoError(t, err)
+			require.NoError(t, r.parser.Initialize(false, false, nil))
+
+			logsSink := new(consumertest.LogsSink)
+			if tt.withMetrics {
+				r.metricsConsumer = consumertest.NewNop()
+			}
+			if tt.withLogs {
+				r.logsConsumer = logsSink
+			}
+
+			ctx := context.Background()
+			r.handleLine(ctx, "test.metric:42|c")
+			r.handleLine(ctx, "_e{5,4}:title|text")
+			r.handleLine(ctx, "_sc|check|0")
+			r.handleLine(ctx, "_sc|check|9")
+			r.handleLine(ctx, "test.metric:42|x")
+
+			metrics := r.parser.GetMetrics()
+			assert.Equal(t, tt.wantMetrics, metrics.ResourceMetrics().At(0).ScopeMetrics().Len())
+			assert.Len(t, logsSink.AllLogs(), tt.wantLogs)
+		})
+	}
+}
+
 func Test_statsdreceiver_EndToEnd(t *testing.T) {
 	addr := testutil.GetAvailableLocalAddress(t)
 	host, portStr, err := net.SplitHostPort(addr)
@@ -18,20 +18,27 @@ import (
 	"bytes"
 	"errors"
 	"io"
+	"io/fs"
 	"net"
+	"os"
 	"strings"
 
 	"go.opentelemetry.io/collector/consumer"
+	"go.uber.org/multierr"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
 )
 
-type udpServer struct {
+type packetServer struct {
 	packetConn net.PacketConn
 	reporter   Reporter
+	network    string
+	// socketPath is set for unix datagram sockets, which are not unlinked
+	// automatically when the connection is closed.
+	socketPath string
 }
 
-var _ (Server) = (*udpServer)(nil)
+var _ (Server) = (*packetServer)(nil)
 
 // NewUDPServer creates a transport.Server using UDP as its transport.
 func NewUDPServer(addr string) (Server, error) {
@@ -40,19 +47,39 @@ func NewUDPServer(addr string) (Server, error) {
 		return nil, err
 	}
 
-	u := udpServer{
+	u := packetServer{
 		packetConn: packetConn,
+		network:    "UDP",
 	}
 	return &u, nil
 }
 
-func (u *udpServer) ListenAndServe(
+// NewUnixgramServer creates a transport.Server using a unix datagram socket
+// bound to the given path as its transport.
+func NewUnixgramServer(path string) (Server, error) {
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+	packetConn, err := net.ListenPacket("unixgram", path)
+	if err != nil {
+		return nil, err
+	}
+
+	u := packetServer{
+		packetConn: packetConn,
+		network:    "Unixgram",
+		socketPath: path,
+	}
+	return &u, nil
+}
+
+func (u *packetServer) ListenAndServe(
 	parser protocol.Parser,
 	nextConsumer consumer.Metrics,
 	reporter Reporter,
 	transferChan chan<- string,
 ) error {
-	if parser == nil || nextConsumer == nil || reporter == nil {
+	if parser == nil || reporter == nil {
 		return errNilListenAndServeParameters
 	}
 
@@ -67,7 +94,8 @@ func (u *udpServer) ListenAndServe(
 			u.handlePacket(bufCopy, transferChan)
 		}
 		if err != nil {
-			u.reporter.OnDebugf("UDP Transport (%s) - ReadFrom error: %v",
+			u.reporter.OnDebugf("%s Transport (%s) - ReadFrom error: %v",
+				u.network,
 				u.packetConn.LocalAddr(),
 				err)
 			var netErr net.Error
@@ -81,11 +109,17 @@ func (u *udpServer) ListenAndServe(
 	}
 }
 
-func (u *udpServer) Close() error {
-	return u.packetConn.Close()
+func (u *packetServer) Close() error {
+	err := u.packetConn.Close()
+	if u.socketPath != "" {
+		if rmErr := os.Remove(u.socketPath); rmErr != nil && !errors.Is(rmErr, fs.ErrNotExist) {
+			err = multierr.Append(err, rmErr)
+		}
+	}
+	return err
 }
 
-func (u *udpServer) handlePacket(
+func (u *packetServer) handlePacket(
 	data []byte,
 	transferChan chan<- string,
 ) {
@@ -24,7 +24,7 @@ import (
 )
 
 var (
-	errNilListenAndServeParameters = errors.New("no parameter of ListenAndServe can be nil")
+	errNilListenAndServeParameters = errors.New("the parser and reporter of ListenAndServe cannot be nil")
 )
 
 // Server abstracts the type of transport being used and offer an
@@ -32,7 +32,8 @@ var (
 type Server interface {
 	// ListenAndServe is a blocking call that starts to listen for client messages
 	// on the specific transport, and prepares the message to be processed by
-	// the Parser and passed to the next consumer.
+	// the Parser and passed to the next consumer. The next consumer is nil
+	// when the receiver is only part of a logs pipeline.
 	ListenAndServe(
 		p protocol.Parser,
 		mc consumer.Metrics,
@@ -15,7 +15,10 @@
 package transport
 
 import (
+	"io"
 	"net"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"sync"
@@ -35,43 +38,66 @@ func Test_Server_ListenAndServe(t *testing.T) {
 
 	tests := []struct {
 		name          string
+		network       string
+		buildAddrFn   func(t *testing.T) string
 		buildServerFn func(addr string) (Server, error)
-		buildClientFn func(host string, port int) (*client.StatsD, error)
+		buildClientFn func(addr string) (*client.StatsD, error)
 	}{
 		{
-			name:          "udp",
+			name:    "udp",
+			network: "udp",
+			buildAddrFn: func(t *testing.T) string {
+				return testutil.GetAvailableLocalNetworkAddress(t, "udp")
+			},
 			buildServerFn: NewUDPServer,
-			buildClientFn: func(host string, port int) (*client.StatsD, error) {
+			buildClientFn: func(addr string) (*client.StatsD, error) {
+				host, port := splitHostPort(t, addr)
 				return client.NewStatsD(client.UDP, host, port)
 			},
 		},
+		{
+			name:    "tcp",
+			network: "tcp",
+			buildAddrFn: func(t *testing.T) string {
+				return testutil.GetAvailableLocalAddress(t)
+			},
+			buildServerFn: func(addr string) (Server, error) {
+				return NewTCPServer(addr, 0)
+			},
+			buildClientFn: func(addr string) (*client.StatsD, error) {
+				host, port := splitHostPort(t, addr)
+				return client.NewStatsD(client.TCP, host, port)
+			},
+		},
+		{
+			name:        "unix",
+			network:     "unix",
+			buildAddrFn: socketPath,
+			buildServerFn: func(addr string) (Server, error) {
+				return NewUnixServer(addr, 0)
+			},
+			buildClientFn: func(addr string) (*client.StatsD, error) {
+				return client.NewStatsD(client.Unix, addr, 0)
+			},
+		},
+		{
+			name:          "unixgram",
+			network:       "unixgram",
+			buildAddrFn:   socketPath,
+			buildServerFn: NewUnixgramServer,
+			buildClientFn: func(addr string) (*client.StatsD, error) {
+				return client.NewStatsD(client.Unixgram, addr, 0)
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			addr := testutil.GetAvailableLocalNetworkAddress(t, "udp")
-
-			// Endpoint should be free.
-			ln0, err := net.ListenPacket("udp", addr)
-			require.NoError(t, err)
-			require.NotNil(t, ln0)
-
-			// Ensure that the endpoint wasn't something like ":0" by checking that a second listener will fail.
-			ln1, err := net.ListenPacket("udp", addr)
-			require.Error(t, err)
-			require.Nil(t, ln1)
-
-			// Unbind the local address so the mock UDP service can use it
-			ln0.Close()
+			addr := tt.buildAddrFn(t)
 
 			srv, err := tt.buildServerFn(addr)
 			require.NoError(t, err)
 			require.NotNil(t, srv)
 
-			host, portStr, err := net.SplitHostPort(addr)
-			require.NoError(t, err)
-			port, err := strconv.Atoi(portStr)
-			require.NoError(t, err)
-
 			mc := new(consumertest.MetricsSink)
 			p := &protocol.StatsDParser{}
 			require.NoError(t, err)
@@ -87,7 +113,7 @@ func Test_Server_ListenAndServe(t *testing.T) {
 
 			runtime.Gosched()
 
-			gc, err := tt.buildClientFn(host, port)
+			gc, err := tt.buildClientFn(addr)
 			require.NoError(t, err)
 			require.NotNil(t, gc)
 			err = gc.SendMetric(client.Metric{
@@ -111,6 +137,71 @@ func Test_Server_ListenAndServe(t *testing.T) {
 
 			wgListenAndServe.Wait()
 			assert.Equal(t, 1, len(transferChan))
+			assert.Equal(t, "test.metric:42|c", <-transferChan)
+
+			// The endpoint must be reusable once the server is closed.
+			srv, err = tt.buildServerFn(addr)
+			require.NoError(t, err)
+			assert.NoError(t, srv.Close())
 		})
 	}
 }
+
+func Test_StreamServer_MaxConnections(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	srv, err := NewTCPServer(addr, 1)
+	require.NoError(t, err)
+
+	transferChan := make(chan string, 10)
+	wgListenAndServe := sync.WaitGroup{}
+	wgListenAndServe.Add(1)
+	go func() {
+		defer wgListenAndServe.Done()
+		assert.Error(t, srv.ListenAndServe(&protocol.StatsDParser{}, new(consumertest.MetricsSink), NewMockReporter(0), transferChan))
+	}()
+
+	first, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer first.Close()
+	_, err = first.Write([]byte("first:1|c\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "first:1|c", <-transferChan)
+
+	// The second connection is closed by the server without being read.
+	second, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer second.Close()
+	require.NoError(t, second.SetReadDeadline(time.Now().Add(10*time.Second)))
+	_, err = second.Read(make([]byte, 1))
+	assert.ErrorIs(t, err, io.EOF)
+
+	_, err = first.Write([]byte("first:2|c\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "first:2|c", <-transferChan)
+
+	require.NoError(t, srv.Close())
+	wgListenAndServe.Wait()
+	assert.Len(t, transferChan, 0)
+}
+
+func Test_NewUnixServer_notASocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "regular_file")
+	require.NoError(t, os.WriteFile(path, nil, 0600))
+
+	_, err := NewUnixServer(path, 0)
+	assert.ErrorContains(t, err, "is not a unix socket")
+	_, err = NewUnixgramServer(path)
+	assert.ErrorContains(t, err, "is not a unix socket")
+}
+
+func splitHostPort(t *testing.T, addr string) (string, int) {
+	host, portStr, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(portStr)
+	require.NoError(t, err)
+	return host, port
+}
+
+func socketPath(t *testing.T) string {
+	return filepath.Join(t.TempDir(), "statsd.sock")
+}
@@ -0,0 +1,202 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/transport"
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/collector/consumer"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
+)
+
+// maxLineSize is the largest newline framed message accepted on stream
+// transports, matching the largest payload accepted over UDP.
+const maxLineSize = 65527
+
+type streamServer struct {
+	listener       net.Listener
+	reporter       Reporter
+	network        string
+	maxConnections int
+
+	mu     sync.Mutex
+	conns  map[net.Conn]struct{}
+	closed bool
+	wg     sync.WaitGroup
+}
+
+var _ (Server) = (*streamServer)(nil)
+
+// NewTCPServer creates a transport.Server using TCP as its transport.
+// Messages are expected to be newline framed. A maxConnections value
+// greater than zero limits the number of concurrent client connections,
+// any connection above that limit is closed as soon as it is accepted.
+func NewTCPServer(addr string, maxConnections int) (Server, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	return newStreamServer(listener, "TCP", maxConnections), nil
+}
+
+// NewUnixServer creates a transport.Server using a unix stream socket bound
+// to the given path as its transport. Messages are expected to be newline
+// framed and maxConnections behaves as in NewTCPServer.
+func NewUnixServer(path string, maxConnections int) (Server, error) {
+	if err := removeStaleSocket(path); err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	return newStreamServer(listener, "Unix", maxConnections), nil
+}
+
+func newStreamServer(listener net.Listener, network string, maxConnections int) *streamServer {
+	return &streamServer{
+		listener:       listener,
+		network:        network,
+		maxConnections: maxConnections,
+		conns:          make(map[net.Conn]struct{}),
+	}
+}
+
+func (s *streamServer) ListenAndServe(
+	parser protocol.Parser,
+	nextConsumer consumer.Metrics,
+	reporter Reporter,
+	transferChan chan<- string,
+) error {
+	if parser == nil || reporter == nil {
+		return errNilListenAndServeParameters
+	}
+
+	s.reporter = reporter
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			s.reporter.OnDebugf("%s Transport (%s) - Accept error: %v",
+				s.network,
+				s.listener.Addr(),
+				err)
+			var netErr net.Error
+			if errors.As(err, &netErr) {
+				if netErr.Timeout() {
+					continue
+				}
+			}
+			return err
+		}
+
+		if !s.trackConn(conn) {
+			continue
+		}
+		go func() {
+			defer s.wg.Done()
+			defer s.untrackConn(conn)
+			s.handleConn(conn, transferChan)
+		}()
+	}
+}
+
+// trackConn registers conn as active, it closes conn and returns false if
+// the server is closed or the connection limit was reached.
+func (s *streamServer) trackConn(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		conn.Close()
+		return false
+	}
+	if s.maxConnections > 0 && len(s.conns) >= s.maxConnections {
+		s.reporter.OnDebugf("%s Transport (%s) - rejected connection from %s: limit of %d connections reached",
+			s.network,
+			s.listener.Addr(),
+			conn.RemoteAddr(),
+			s.maxConnections)
+		conn.Close()
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+func (s *streamServer) untrackConn(conn net.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.conns, conn)
+	conn.Close()
+}
+
+func (s *streamServer) handleConn(conn net.Conn, transferChan chan<- string) {
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 4096), maxLineSize)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			transferChan <- line
+		}
+	}
+	if err := scanner.Err(); err != nil && !errors.Is(err, net.ErrClosed) {
+		s.reporter.OnDebugf("%s Transport (%s) - read error from %s: %v",
+			s.network,
+			s.listener.Addr(),
+			conn.RemoteAddr(),
+			err)
+	}
+}
+
+// Close stops accepting new connections, closes the active ones and waits
+// for their pending messages to be handed over.
+func (s *streamServer) Close() error {
+	err := s.listener.Close()
+
+	s.mu.Lock()
+	s.closed = true
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+	return err
+}
+
+// removeStaleSocket removes a unix socket left behind at path by a previous
+// process, it refuses to remove anything that is not a socket.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if info.Mode()&fs.ModeSocket == 0 {
+		return fmt.Errorf("%q already exists and is not a unix socket", path)
+	}
+	return os.Remove(path)
+}